@@ -0,0 +1,39 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrphanedArchives(t *testing.T) {
+	c := require.New(t)
+
+	// Login as an admin
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredAdminEmail,
+		"password": registeredAdminPass,
+	})
+	router.ServeHTTP(w, r)
+	adminCookie := w.Result().Cookies()[0]
+
+	// Login as a teacher
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	// Admins get a report of the orphaned archives without deleting them
+	response, status := GetOrphanedArchives(adminCookie)
+	c.Equal(http.StatusOK, status)
+	c.Equal(true, response["dry_run"])
+	c.NotNil(response["orphaned_archives"])
+	c.Empty(response["deleted_archives"])
+
+	// Teachers cannot get the report
+	_, status = GetOrphanedArchives(teacherCookie)
+	c.Equal(http.StatusForbidden, status)
+}
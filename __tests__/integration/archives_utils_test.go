@@ -0,0 +1,14 @@
+package integration
+
+import (
+	"net/http"
+)
+
+func GetOrphanedArchives(cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	w, r := PrepareRequest("GET", "/api/v1/archives/orphaned", nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
@@ -210,6 +210,146 @@ func TestUpdateTestBlock(t *testing.T) {
 	c.Equal(firstLanguageUUID, block["language_uuid"].(string))
 }
 
+func TestTestBlockExecutionLimits(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	cookie := w.Result().Cookies()[0]
+
+	// Create a course
+	courseUUID, status := CreateCourse("Test block execution limits test - course")
+	c.Equal(http.StatusCreated, status)
+
+	// Create a laboratory
+	laboratoryCreationResponse, status := CreateLaboratory(cookie, map[string]interface{}{
+		"name":         "Test block execution limits test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	// Get the supported languages
+	languagesResponse, status := GetSupportedLanguages(cookie)
+	c.Equal(http.StatusOK, status)
+
+	languages := languagesResponse["languages"].([]interface{})
+	firstLanguage := languages[0].(map[string]interface{})
+	firstLanguageUUID := firstLanguage["uuid"].(string)
+	languageLimits := firstLanguage["execution_limits"].(map[string]interface{})
+
+	// Limits out of range are rejected
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	_, status = CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID:  laboratoryUUID,
+		languageUUID:    firstLanguageUUID,
+		blockName:       "Test block execution limits test - block",
+		cookie:          cookie,
+		testFile:        zipFile,
+		executionLimits: map[string]string{"cpu_time_ms": "1"},
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	// Create a test block that overrides the CPU time limit
+	zipFile, err = GetSampleTestsArchive()
+	c.Nil(err)
+
+	blockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID:  laboratoryUUID,
+		languageUUID:    firstLanguageUUID,
+		blockName:       "Test block execution limits test - block",
+		cookie:          cookie,
+		testFile:        zipFile,
+		executionLimits: map[string]string{"cpu_time_ms": "2000"},
+	})
+	c.Equal(http.StatusCreated, status)
+	testBlockUUID := blockCreationResponse["uuid"].(string)
+
+	// The rest of the limits are the ones of the language
+	testBlockResponse, status := GetTestBlock(cookie, testBlockUUID)
+	c.Equal(http.StatusOK, status)
+
+	limits := testBlockResponse["execution_limits"].(map[string]interface{})
+	c.Equal(2000.0, limits["cpu_time_ms"])
+	c.Equal(languageLimits["wall_time_ms"], limits["wall_time_ms"])
+	c.Equal(languageLimits["memory_mb"], limits["memory_mb"])
+	c.Equal(languageLimits["output_size_kb"], limits["output_size_kb"])
+
+	overrides := testBlockResponse["execution_limits_overrides"].(map[string]interface{})
+	c.Equal(2000.0, overrides["cpu_time_ms"])
+	c.Nil(overrides["memory_mb"])
+
+	// The overrides that are not sent are kept when the block is updated
+	_, status = UpdateTestBlock(&UpdateTestBlockUtilsDTO{
+		blockUUID:       testBlockUUID,
+		languageUUID:    firstLanguageUUID,
+		blockName:       "Test block execution limits test - block",
+		cookie:          cookie,
+		executionLimits: map[string]string{"memory_mb": "256"},
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	testBlockResponse, status = GetTestBlock(cookie, testBlockUUID)
+	c.Equal(http.StatusOK, status)
+
+	limits = testBlockResponse["execution_limits"].(map[string]interface{})
+	c.Equal(2000.0, limits["cpu_time_ms"])
+	c.Equal(256.0, limits["memory_mb"])
+
+	_, status = UpdateTestBlock(&UpdateTestBlockUtilsDTO{
+		blockUUID:    testBlockUUID,
+		languageUUID: firstLanguageUUID,
+		blockName:    "Test block execution limits test - renamed block",
+		cookie:       cookie,
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	testBlockResponse, status = GetTestBlock(cookie, testBlockUUID)
+	c.Equal(http.StatusOK, status)
+
+	overrides = testBlockResponse["execution_limits_overrides"].(map[string]interface{})
+	c.Equal(2000.0, overrides["cpu_time_ms"])
+	c.Equal(256.0, overrides["memory_mb"])
+
+	// The overrides are removed when they are reset
+	_, status = UpdateTestBlock(&UpdateTestBlockUtilsDTO{
+		blockUUID:            testBlockUUID,
+		languageUUID:         firstLanguageUUID,
+		blockName:            "Test block execution limits test - block",
+		cookie:               cookie,
+		executionLimits:      map[string]string{"wall_time_ms": "5000"},
+		resetExecutionLimits: true,
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	testBlockResponse, status = GetTestBlock(cookie, testBlockUUID)
+	c.Equal(http.StatusOK, status)
+
+	limits = testBlockResponse["execution_limits"].(map[string]interface{})
+	c.Equal(languageLimits["cpu_time_ms"], limits["cpu_time_ms"])
+	c.Equal(5000.0, limits["wall_time_ms"])
+	c.Equal(languageLimits["memory_mb"], limits["memory_mb"])
+
+	// Only the owner of the block can get it
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredAdminEmail,
+		"password": registeredAdminPass,
+	})
+	router.ServeHTTP(w, r)
+	adminCookie := w.Result().Cookies()[0]
+
+	_, status = GetTestBlock(adminCookie, testBlockUUID)
+	c.Equal(http.StatusForbidden, status)
+}
+
 func TestDeleteTestBlock(t *testing.T) {
 	c := require.New(t)
 
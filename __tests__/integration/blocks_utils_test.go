@@ -36,6 +36,10 @@ type UpdateTestBlockUtilsDTO struct {
 	blockName    string
 	cookie       *http.Cookie
 	testFile     *os.File
+
+	// Optional execution limits fields (e.g. `cpu_time_ms`)
+	executionLimits      map[string]string
+	resetExecutionLimits bool
 }
 
 func UpdateTestBlock(dto *UpdateTestBlockUtilsDTO) (response map[string]interface{}, statusCode int) {
@@ -51,6 +55,15 @@ func UpdateTestBlock(dto *UpdateTestBlockUtilsDTO) (response map[string]interfac
 	// Add the language UUID
 	_ = writer.WriteField("language_uuid", dto.languageUUID)
 
+	// Add the execution limits
+	for field, value := range dto.executionLimits {
+		_ = writer.WriteField(field, value)
+	}
+
+	if dto.resetExecutionLimits {
+		_ = writer.WriteField("reset_execution_limits", "true")
+	}
+
 	// Add the test file
 	if dto.testFile != nil {
 		part, err := writer.CreateFormFile("test_archive", dto.testFile.Name())
@@ -117,6 +130,16 @@ func SwapBlocks(dto *SwapBlocksUtilsDTO) (response map[string]interface{}, statu
 	return jsonResponse, w.Code
 }
 
+func GetTestBlock(cookie *http.Cookie, testBlockUUID string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/blocks/test_blocks/%s", testBlockUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+
+	router.ServeHTTP(w, r)
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
 func GetTestsArchive(testBlockUUID string, cookie *http.Cookie) (bytes []byte, statusCode int) {
 	endpoint := fmt.Sprintf("/api/v1/blocks/test_blocks/%s/tests_archive", testBlockUUID)
 	w, r := PrepareRequest("GET", endpoint, nil)
@@ -580,7 +580,7 @@ func TestGetCourseLaboratories(t *testing.T) {
 	router.ServeHTTP(w, r)
 	cookie := w.Result().Cookies()[0]
 
-	// Create two laboratories
+	// Create an open, a future and a draft laboratory
 	openLaboratoryName := "Get course laboratories test - open laboratory"
 	openLaboratoryCreationResponse, code := CreateLaboratory(cookie, map[string]interface{}{
 		"name":         openLaboratoryName,
@@ -592,13 +592,31 @@ func TestGetCourseLaboratories(t *testing.T) {
 	openLaboratoryUUID := openLaboratoryCreationResponse["uuid"].(string)
 
 	futureLaboratoryName := "Get course laboratories test - future laboratory"
-	_, code = CreateLaboratory(cookie, map[string]interface{}{
+	futureLaboratoryCreationResponse, code := CreateLaboratory(cookie, map[string]interface{}{
 		"name":         futureLaboratoryName,
 		"course_uuid":  courseUUID,
 		"opening_date": "3023-11-01T12:00:00-05:00",
 		"due_date":     defaultLaboratoryDueDate,
 	})
 	c.Equal(http.StatusCreated, code)
+	futureLaboratoryUUID := futureLaboratoryCreationResponse["uuid"].(string)
+
+	_, code = CreateLaboratory(cookie, map[string]interface{}{
+		"name":         "Get course laboratories test - draft laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, code)
+
+	// Publish the open and future laboratories
+	for _, laboratoryUUID := range []string{openLaboratoryUUID, futureLaboratoryUUID} {
+		_, code = CreateMarkdownBlock(cookie, laboratoryUUID)
+		c.Equal(http.StatusCreated, code)
+
+		_, code = PublishLaboratory(cookie, laboratoryUUID)
+		c.Equal(http.StatusNoContent, code)
+	}
 
 	// ## Teacher test cases
 	teacherTestCases := []GenericTestCase{
@@ -622,16 +640,22 @@ func TestGetCourseLaboratories(t *testing.T) {
 
 		if code == http.StatusOK {
 			laboratories := response["laboratories"].([]interface{})
-			c.Equal(2, len(laboratories))
+			c.Equal(3, len(laboratories))
 
 			// Assert the laboratories fields
+			publishedLaboratories := 0
 			for _, laboratory := range laboratories {
 				laboratory := laboratory.(map[string]interface{})
 				c.NotEmpty(laboratory["uuid"])
 				c.NotEmpty(laboratory["name"])
 				c.NotEmpty(laboratory["opening_date"])
 				c.NotEmpty(laboratory["due_date"])
+
+				if laboratory["is_published"].(bool) {
+					publishedLaboratories++
+				}
 			}
+			c.Equal(2, publishedLaboratories)
 		}
 	}
 
@@ -723,3 +747,108 @@ func TestSetStudentStatus(t *testing.T) {
 	student := students["students"].([]interface{})[0].(map[string]interface{})
 	c.Equal(false, student["is_active"])
 }
+
+func TestDeleteCourse(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	// Create a course with a student and a laboratory
+	courseUUID, status := CreateCourse("Delete course test - course")
+	c.Equal(http.StatusCreated, status)
+
+	invitationCode, status := GetInvitationCode(courseUUID)
+	c.Equal(http.StatusOK, status)
+
+	_, status = AddStudentToCourse(invitationCode)
+	c.Equal(http.StatusOK, status)
+
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	studentCookie := w.Result().Cookies()[0]
+
+	laboratoryCreationResponse, status := CreateLaboratory(teacherCookie, map[string]interface{}{
+		"name":         "Delete course test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	isCourseInList := func(courses []interface{}) bool {
+		for _, course := range courses {
+			if course.(map[string]interface{})["uuid"] == courseUUID {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// ## Test: Only the teacher of the course can delete it
+	_, status = DeleteCourse(studentCookie, courseUUID)
+	c.Equal(http.StatusForbidden, status)
+
+	_, status = DeleteCourse(teacherCookie, "not-valid")
+	c.Equal(http.StatusBadRequest, status)
+
+	// ## Test: Deleted courses are hidden, along with their laboratories
+	_, status = DeleteCourse(teacherCookie, courseUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = GetCourseByUUID(teacherCookie, courseUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	_, status = GetCourseByUUID(studentCookie, courseUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	coursesResponse, status := GetCoursesUserIsEnrolledIn(studentCookie)
+	c.Equal(http.StatusOK, status)
+	c.False(isCourseInList(coursesResponse["courses"].([]interface{})))
+
+	_, status = GetLaboratoryInformationByUUID(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	_, status = DeleteCourse(teacherCookie, courseUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	// ## Test: The teacher can list the deleted courses
+	deletedCoursesResponse, status := GetDeletedCourses(teacherCookie)
+	c.Equal(http.StatusOK, status)
+	c.True(isCourseInList(deletedCoursesResponse["courses"].([]interface{})))
+
+	// ## Test: Only the teacher of the course can restore it
+	_, status = RestoreCourse(studentCookie, courseUUID)
+	c.Equal(http.StatusForbidden, status)
+
+	_, status = RestoreCourse(teacherCookie, courseUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = RestoreCourse(teacherCookie, courseUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	// ## Test: Restored courses are shown again, along with their laboratories
+	_, status = GetCourseByUUID(studentCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+
+	coursesResponse, status = GetCoursesUserIsEnrolledIn(studentCookie)
+	c.Equal(http.StatusOK, status)
+	c.True(isCourseInList(coursesResponse["courses"].([]interface{})))
+
+	_, status = GetLaboratoryInformationByUUID(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+
+	deletedCoursesResponse, status = GetDeletedCourses(teacherCookie)
+	c.Equal(http.StatusOK, status)
+	c.False(isCourseInList(deletedCoursesResponse["courses"].([]interface{})))
+}
@@ -3,6 +3,7 @@ package integration
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -128,4 +129,315 @@ func TestGradeStudentSubmission(t *testing.T) {
 	c.Equal(objectiveUUID, selectedCriteriaObjectiveUUID)
 	selectedCriteriaCriteriaUUID := selectedCriteria["criteria_uuid"].(string)
 	c.Equal(criteriaUUID, selectedCriteriaCriteriaUUID)
+	c.False(selectedCriteria["is_automatic"].(bool))
+}
+
+func TestGradingRules(t *testing.T) {
+	c := require.New(t)
+
+	// ## Test preparation
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	cookie := w.Result().Cookies()[0]
+
+	// Create a course
+	courseUUID, _ := CreateCourse("Grading rules test - course")
+
+	// Create a laboratory
+	laboratoryName := "Grading rules test - laboratory"
+	laboratoryCreationResponse, _ := CreateLaboratory(cookie, map[string]interface{}{
+		"name":         laboratoryName,
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	// Create a rubric with an objective and a criteria
+	rubricCreationResponse, _ := CreateRubric(cookie, map[string]interface{}{
+		"name": "Grading rules test - rubric",
+	})
+	rubricUUID := rubricCreationResponse["uuid"].(string)
+
+	objectiveCreationResponse, _ := AddObjectiveToRubric(cookie, rubricUUID, map[string]interface{}{
+		"description": "Grading rules test - objective",
+	})
+	objectiveUUID := objectiveCreationResponse["uuid"].(string)
+
+	criteriaCreationResponse, _ := AddCriteriaToObjective(cookie, objectiveUUID, map[string]interface{}{
+		"description": "Grading rules test - criteria",
+		"weight":      1.0,
+	})
+	criteriaUUID := criteriaCreationResponse["uuid"].(string)
+
+	// Add the rubric to the laboratory
+	UpdateLaboratory(cookie, laboratoryUUID, map[string]interface{}{
+		"rubric_uuid":  rubricUUID,
+		"name":         laboratoryName,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+
+	// Create a test block
+	languagesResponse, _ := GetSupportedLanguages(cookie)
+	languages := languagesResponse["languages"].([]interface{})
+	firstLanguageUUID := languages[0].(map[string]interface{})["uuid"].(string)
+
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	blockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID: laboratoryUUID,
+		languageUUID:   firstLanguageUUID,
+		blockName:      "Grading rules test - block",
+		cookie:         cookie,
+		testFile:       zipFile,
+	})
+	c.Equal(http.StatusCreated, status)
+	testBlockUUID := blockCreationResponse["uuid"].(string)
+
+	// ## Test: Create grading rules
+	testCases := []GenericTestCase{
+		{
+			Payload: map[string]interface{}{
+				"test_block_uuid": testBlockUUID,
+				"objective_uuid":  objectiveUUID,
+				"criteria_uuid":   criteriaUUID,
+				"condition":       "not-valid",
+			},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Payload: map[string]interface{}{
+				"test_block_uuid": testBlockUUID,
+				"objective_uuid":  objectiveUUID,
+				"criteria_uuid":   criteriaUUID,
+				"condition":       "min_tests_ratio",
+			},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Payload: map[string]interface{}{
+				"test_block_uuid": testBlockUUID,
+				"objective_uuid":  objectiveUUID,
+				"criteria_uuid":   criteriaUUID,
+				"condition":       "min_tests_ratio",
+				"min_tests_ratio": 0.8,
+			},
+			ExpectedStatusCode: http.StatusCreated,
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, code := CreateGradingRule(laboratoryUUID, testCase.Payload, cookie)
+		c.Equal(testCase.ExpectedStatusCode, code)
+	}
+
+	// ## Test: Get the grading rules of the laboratory
+	rulesResponse, code := GetGradingRules(laboratoryUUID, cookie)
+	c.Equal(http.StatusOK, code)
+
+	rules := rulesResponse["rules"].([]interface{})
+	c.Equal(1, len(rules))
+
+	rule := rules[0].(map[string]interface{})
+	c.Equal(testBlockUUID, rule["test_block_uuid"])
+	c.Equal(objectiveUUID, rule["objective_uuid"])
+	c.Equal(criteriaUUID, rule["criteria_uuid"])
+	c.Equal("min_tests_ratio", rule["condition"])
+	c.Equal(0.8, rule["min_tests_ratio"])
+
+	// ## Test: Delete the grading rule
+	ruleUUID := rule["uuid"].(string)
+	code = DeleteGradingRule(laboratoryUUID, ruleUUID, cookie)
+	c.Equal(http.StatusNoContent, code)
+
+	code = DeleteGradingRule(laboratoryUUID, ruleUUID, cookie)
+	c.Equal(http.StatusNotFound, code)
+}
+
+func TestLatePolicyPenalty(t *testing.T) {
+	c := require.New(t)
+
+	// ## Test preparation
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	cookie := w.Result().Cookies()[0]
+
+	// Create a course and a laboratory that was due 90 minutes ago
+	courseUUID, _ := CreateCourse("Late policy penalty test - course")
+	courseInvitationCode, _ := GetInvitationCode(courseUUID)
+
+	laboratoryName := "Late policy penalty test - laboratory"
+	laboratoryDueDate := time.Now().Add(-90 * time.Minute).UTC().Format(time.RFC3339)
+
+	laboratoryCreationResponse, status := CreateLaboratory(cookie, map[string]interface{}{
+		"name":         laboratoryName,
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     laboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	languagesResponse, _ := GetSupportedLanguages(cookie)
+	firstLanguage := languagesResponse["languages"].([]interface{})[0].(map[string]interface{})
+
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	blockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID: laboratoryUUID,
+		languageUUID:   firstLanguage["uuid"].(string),
+		blockName:      "Late policy penalty test - block",
+		cookie:         cookie,
+		testFile:       zipFile,
+	})
+	c.Equal(http.StatusCreated, status)
+	testBlockUUID := blockCreationResponse["uuid"].(string)
+
+	// Publish the laboratory before the rubric is added, as its weights are not complete
+	_, status = PublishLaboratory(cookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	// Create a rubric with a single criteria and add it to the laboratory
+	rubricCreationResponse, _ := CreateRubric(cookie, map[string]interface{}{
+		"name": "Late policy penalty test - rubric",
+	})
+	rubricUUID := rubricCreationResponse["uuid"].(string)
+
+	objectiveCreationResponse, _ := AddObjectiveToRubric(cookie, rubricUUID, map[string]interface{}{
+		"description": "Late policy penalty test - objective",
+	})
+	objectiveUUID := objectiveCreationResponse["uuid"].(string)
+
+	criteriaCreationResponse, _ := AddCriteriaToObjective(cookie, objectiveUUID, map[string]interface{}{
+		"description": "Late policy penalty test - criteria",
+		"weight":      10.0,
+	})
+	criteriaUUID := criteriaCreationResponse["uuid"].(string)
+
+	_, status = UpdateLaboratory(cookie, laboratoryUUID, map[string]interface{}{
+		"rubric_uuid":  rubricUUID,
+		"name":         laboratoryName,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     laboratoryDueDate,
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	// Add the student to the course
+	AddStudentToCourse(courseInvitationCode)
+
+	enrolledStudentsResponse, _ := GetStudentsEnrolledInCourse(cookie, courseUUID)
+	studentUUID := enrolledStudentsResponse["students"].([]interface{})[0].(map[string]interface{})["uuid"].(string)
+
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	studentCookie := w.Result().Cookies()[0]
+
+	// ## Test: Late submissions are rejected by default
+	zipFile, err = GetSampleSubmissionArchive()
+	c.Nil(err)
+
+	_, status = SubmitSolutionToTestBlock(&SubmitSToTestBlockUtilsDTO{
+		blockUUID: testBlockUUID,
+		cookie:    studentCookie,
+		file:      zipFile,
+	})
+	c.Equal(http.StatusForbidden, status)
+
+	// ## Test: Invalid late policies
+	_, status = SetLatePolicy(cookie, laboratoryUUID, map[string]interface{}{
+		"policy": "unknown",
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	_, status = SetLatePolicy(cookie, laboratoryUUID, map[string]interface{}{
+		"policy":         "late_window",
+		"window_minutes": 1440,
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	// ## Test: Late submissions are accepted during the late window
+	_, status = SetLatePolicy(cookie, laboratoryUUID, map[string]interface{}{
+		"policy":             "late_window",
+		"window_minutes":     1440,
+		"penalty_percentage": 10,
+		"penalty_unit":       "hour",
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	informationResponse, status := GetLaboratoryInformationByUUID(cookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+	latePolicy := informationResponse["late_policy"].(map[string]interface{})
+	c.Equal("late_window", latePolicy["policy"])
+	c.Equal("hour", latePolicy["penalty_unit"])
+
+	zipFile, err = GetSampleSubmissionArchive()
+	c.Nil(err)
+
+	_, status = SubmitSolutionToTestBlock(&SubmitSToTestBlockUtilsDTO{
+		blockUUID: testBlockUUID,
+		cookie:    studentCookie,
+		file:      zipFile,
+	})
+	c.Equal(http.StatusCreated, status)
+
+	// The submission is flagged with its lateness
+	historyResponse, status := GetStudentSubmissionsHistory(testBlockUUID, studentUUID, cookie)
+	c.Equal(http.StatusOK, status)
+
+	attempts := historyResponse["submissions"].([]interface{})
+	c.Equal(1, len(attempts))
+	lateMinutes := attempts[0].(map[string]interface{})["late_minutes"].(float64)
+	c.GreaterOrEqual(lateMinutes, float64(90))
+	c.Less(lateMinutes, float64(120))
+
+	// ## Test: The penalty is deducted from the grade (Two started hours, 10% each)
+	_, status = SetCriteriaToStudentGrade(&SetCriteriaToStudentGradeUtilsDTO{
+		LaboratoryUUID: laboratoryUUID,
+		StudentUUID:    studentUUID,
+		ObjectiveUUID:  objectiveUUID,
+		CriteriaUUID:   criteriaUUID,
+	}, cookie)
+	c.Equal(http.StatusNoContent, status)
+
+	gradesResponse, status := GetSummarizedGrades(laboratoryUUID, cookie)
+	c.Equal(http.StatusOK, status)
+
+	studentsGrades := gradesResponse["grades"].([]interface{})
+	c.Equal(1, len(studentsGrades))
+
+	studentGrade := studentsGrades[0].(map[string]interface{})
+	c.Equal(10.0, studentGrade["raw_grade"])
+	c.Equal(20.0, studentGrade["penalty_percentage"])
+	c.InDelta(8.0, studentGrade["grade"], 1e-9)
+	c.Equal(lateMinutes, studentGrade["late_minutes"])
+
+	// ## Test: Late submissions are rejected again once the policy is removed
+	_, status = SetLatePolicy(cookie, laboratoryUUID, map[string]interface{}{
+		"policy": "none",
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	zipFile, err = GetSampleSubmissionArchive()
+	c.Nil(err)
+
+	_, status = SubmitSolutionToTestBlock(&SubmitSToTestBlockUtilsDTO{
+		blockUUID: testBlockUUID,
+		cookie:    studentCookie,
+		file:      zipFile,
+	})
+	c.Equal(http.StatusForbidden, status)
 }
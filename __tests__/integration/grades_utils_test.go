@@ -79,3 +79,32 @@ func SetCommentToStudentGrade(dto *SetCommentToStudentGradeUtilsDTO, cookie *htt
 	jsonResponse := ParseJsonResponse(w.Body)
 	return jsonResponse, w.Code
 }
+
+func CreateGradingRule(laboratoryUUID string, payload map[string]interface{}, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/grades/laboratories/%s/rules", laboratoryUUID)
+	w, r := PrepareRequest("POST", endpoint, payload)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func GetGradingRules(laboratoryUUID string, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/grades/laboratories/%s/rules", laboratoryUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func DeleteGradingRule(laboratoryUUID, ruleUUID string, cookie *http.Cookie) (statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/grades/laboratories/%s/rules/%s", laboratoryUUID, ruleUUID)
+	w, r := PrepareRequest("DELETE", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	return w.Code
+}
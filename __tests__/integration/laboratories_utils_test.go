@@ -62,6 +62,9 @@ type CreateTestBlockUtilsDTO struct {
 	blockName      string
 	cookie         *http.Cookie
 	testFile       *os.File
+
+	// Optional execution limits fields (e.g. `cpu_time_ms`)
+	executionLimits map[string]string
 }
 
 func CreateTestBlock(dto *CreateTestBlockUtilsDTO) (response map[string]interface{}, statusCode int) {
@@ -97,6 +100,13 @@ func CreateTestBlock(dto *CreateTestBlockUtilsDTO) (response map[string]interfac
 		panic(err)
 	}
 
+	for field, value := range dto.executionLimits {
+		err = writer.WriteField(field, value)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	// Close the multipart form
 	err = writer.Close()
 	if err != nil {
@@ -133,3 +143,149 @@ func GetProgressOfStudentInLaboratory(laboratoryUUID string, studentUUID string,
 	jsonResponse := ParseJsonResponse(w.Body)
 	return jsonResponse, w.Code
 }
+
+func GetLaboratorySubmissionsArchive(laboratoryUUID string, cookie *http.Cookie) (bytes []byte, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/submissions/archive", laboratoryUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+	return w.Body.Bytes(), w.Code
+}
+
+func SetDateOverrides(cookie *http.Cookie, laboratoryUUID string, payload map[string]interface{}) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/date_overrides", laboratoryUUID)
+	w, r := PrepareRequest("PUT", endpoint, payload)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func GetDateOverrides(cookie *http.Cookie, laboratoryUUID string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/date_overrides", laboratoryUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func DeleteDateOverride(cookie *http.Cookie, laboratoryUUID string, studentUUID string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/date_overrides/%s", laboratoryUUID, studentUUID)
+	w, r := PrepareRequest("DELETE", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func SetLatePolicy(cookie *http.Cookie, laboratoryUUID string, payload map[string]interface{}) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/late_policy", laboratoryUUID)
+	w, r := PrepareRequest("PUT", endpoint, payload)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func PublishLaboratory(cookie *http.Cookie, laboratoryUUID string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/publish", laboratoryUUID)
+	w, r := PrepareRequest("POST", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func UnpublishLaboratory(cookie *http.Cookie, laboratoryUUID string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/unpublish", laboratoryUUID)
+	w, r := PrepareRequest("POST", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func DeleteLaboratory(cookie *http.Cookie, laboratoryUUID string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s", laboratoryUUID)
+	w, r := PrepareRequest("DELETE", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func RestoreLaboratory(cookie *http.Cookie, laboratoryUUID string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/restore", laboratoryUUID)
+	w, r := PrepareRequest("POST", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func CloneLaboratory(cookie *http.Cookie, laboratoryUUID string, payload map[string]interface{}) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/clone", laboratoryUUID)
+	w, r := PrepareRequest("POST", endpoint, payload)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func ExportLaboratory(cookie *http.Cookie, laboratoryUUID string, includeRubric bool) (bytes []byte, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/laboratories/%s/bundle?include_rubric=%t", laboratoryUUID, includeRubric)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+	return w.Body.Bytes(), w.Code
+}
+
+func ImportLaboratory(cookie *http.Cookie, bundle []byte, fields map[string]string) (response map[string]interface{}, statusCode int) {
+	// Create the request body
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	// Add the bundle to the form
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", "form-data; name=\"bundle\"; filename=\"bundle.zip\"")
+	h.Set("Content-Type", "application/zip")
+
+	fileWriter, err := writer.CreatePart(h)
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err := fileWriter.Write(bundle); err != nil {
+		panic(err)
+	}
+
+	// Add the text fields to the form
+	for field, value := range fields {
+		if err := writer.WriteField(field, value); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		panic(err)
+	}
+
+	// Send the request
+	w, r := PrepareMultipartRequest("POST", "/api/v1/laboratories/import", &body)
+	r.AddCookie(cookie)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
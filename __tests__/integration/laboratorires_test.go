@@ -1,7 +1,11 @@
 package integration
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -416,6 +420,9 @@ func TestGetStudentsProgress(t *testing.T) {
 	c.Equal(http.StatusCreated, status)
 	testBlockUUID := blockCreationResponse["uuid"].(string)
 
+	_, status = PublishLaboratory(cookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
 	// Login as a student
 	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
 		"email":    registeredStudentEmail,
@@ -492,4 +499,697 @@ func TestGetStudentsProgress(t *testing.T) {
 	c.Equal(testBlockName, submission["test_block_name"])
 	c.Contains([]string{"pending", "running", "ready"}, submission["status"])
 	c.Contains([]bool{true, false}, submission["is_passing"])
+	c.IsType([]interface{}{}, submission["test_results"])
+
+	// ### Download the latest submissions of the laboratory
+	archiveBytes, status := GetLaboratorySubmissionsArchive(laboratoryUUID, cookie)
+	c.Equal(http.StatusOK, status)
+
+	archiveReader, err := zip.NewReader(bytes.NewReader(archiveBytes), int64(len(archiveBytes)))
+	c.Nil(err)
+
+	archiveFiles := map[string]*zip.File{}
+	for _, file := range archiveReader.File {
+		archiveFiles[file.Name] = file
+	}
+	c.Greater(len(archiveFiles), 1)
+
+	manifestFile, exists := archiveFiles["manifest.csv"]
+	c.True(exists)
+
+	manifestReader, err := manifestFile.Open()
+	c.Nil(err)
+	defer manifestReader.Close()
+
+	manifestRows, err := csv.NewReader(manifestReader).ReadAll()
+	c.Nil(err)
+	c.Equal(2, len(manifestRows))
+
+	manifestRow := manifestRows[1]
+	c.Equal(testBlockName, manifestRow[2])
+	c.Equal(submission["uuid"], manifestRow[3])
+	c.Equal("true", manifestRow[8])
+	c.True(strings.HasSuffix(manifestRow[7], "/"+testBlockName))
+
+	// Only the teacher that owns the laboratory can download the submissions
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	cookie = w.Result().Cookies()[0]
+
+	_, status = GetLaboratorySubmissionsArchive(laboratoryUUID, cookie)
+	c.Equal(http.StatusForbidden, status)
+}
+
+func TestLaboratoryDateOverrides(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	// Create a course with a laboratory that is not open yet
+	courseUUID, status := CreateCourse("Laboratory date overrides test - course")
+	c.Equal(http.StatusCreated, status)
+
+	laboratoryCreationResponse, status := CreateLaboratory(teacherCookie, map[string]interface{}{
+		"name":         "Laboratory date overrides test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": "3023-11-01T17:00:00Z",
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	languagesResponse, status := GetSupportedLanguages(teacherCookie)
+	c.Equal(http.StatusOK, status)
+	firstLanguage := languagesResponse["languages"].([]interface{})[0].(map[string]interface{})
+
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	blockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID: laboratoryUUID,
+		languageUUID:   firstLanguage["uuid"].(string),
+		blockName:      "Laboratory date overrides test - block",
+		cookie:         teacherCookie,
+		testFile:       zipFile,
+	})
+	c.Equal(http.StatusCreated, status)
+	testBlockUUID := blockCreationResponse["uuid"].(string)
+
+	_, status = PublishLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	// Add the student to the course
+	invitationCode, status := GetInvitationCode(courseUUID)
+	c.Equal(http.StatusOK, status)
+
+	_, status = AddStudentToCourse(invitationCode)
+	c.Equal(http.StatusOK, status)
+
+	enrolledStudentsResponse, status := GetStudentsEnrolledInCourse(teacherCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	studentUUID := enrolledStudentsResponse["students"].([]interface{})[0].(map[string]interface{})["uuid"].(string)
+
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	studentCookie := w.Result().Cookies()[0]
+
+	// The laboratory is not listed to the student, nor accepts their submissions
+	laboratoriesResponse, status := GetCourseLaboratories(studentCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(0, len(laboratoriesResponse["laboratories"].([]interface{})))
+
+	zipFile, err = GetSampleSubmissionArchive()
+	c.Nil(err)
+
+	_, status = SubmitSolutionToTestBlock(&SubmitSToTestBlockUtilsDTO{
+		blockUUID: testBlockUUID,
+		cookie:    studentCookie,
+		file:      zipFile,
+	})
+	c.Equal(http.StatusForbidden, status)
+
+	// The due date can not be before the opening date the student would have
+	_, status = SetDateOverrides(teacherCookie, laboratoryUUID, map[string]interface{}{
+		"students_uuids": []string{studentUUID},
+		"due_date":       "2024-01-01T17:00:00Z",
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	// At least one date must be sent
+	_, status = SetDateOverrides(teacherCookie, laboratoryUUID, map[string]interface{}{
+		"students_uuids": []string{studentUUID},
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	// Open the laboratory for the student
+	_, status = SetDateOverrides(teacherCookie, laboratoryUUID, map[string]interface{}{
+		"students_uuids": []string{studentUUID},
+		"opening_date":   defaultLaboratoryOpeningDate,
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	overridesResponse, status := GetDateOverrides(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+
+	overrides := overridesResponse["date_overrides"].([]interface{})
+	c.Equal(1, len(overrides))
+	c.Equal(studentUUID, overrides[0].(map[string]interface{})["student_uuid"])
+	c.Nil(overrides[0].(map[string]interface{})["due_date"])
+
+	// The student sees the dates they were granted
+	laboratoriesResponse, status = GetCourseLaboratories(studentCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+
+	laboratories := laboratoriesResponse["laboratories"].([]interface{})
+	c.Equal(1, len(laboratories))
+	c.Equal(defaultLaboratoryOpeningDateUTC, laboratories[0].(map[string]interface{})["opening_date"])
+
+	informationResponse, status := GetLaboratoryInformationByUUID(studentCookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(defaultLaboratoryOpeningDateUTC, informationResponse["opening_date"])
+	c.Equal(defaultLaboratoryDueDateUTC, informationResponse["due_date"])
+
+	// The teacher keeps seeing the dates of the laboratory
+	informationResponse, status = GetLaboratoryInformationByUUID(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal("3023-11-01T17:00:00Z", informationResponse["opening_date"])
+
+	// The submissions of the student are accepted
+	zipFile, err = GetSampleSubmissionArchive()
+	c.Nil(err)
+
+	_, status = SubmitSolutionToTestBlock(&SubmitSToTestBlockUtilsDTO{
+		blockUUID: testBlockUUID,
+		cookie:    studentCookie,
+		file:      zipFile,
+	})
+	c.Equal(http.StatusCreated, status)
+
+	// Once the override is removed, the dates of the laboratory are applied again
+	_, status = DeleteDateOverride(teacherCookie, laboratoryUUID, studentUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = DeleteDateOverride(teacherCookie, laboratoryUUID, studentUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	laboratoriesResponse, status = GetCourseLaboratories(studentCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(0, len(laboratoriesResponse["laboratories"].([]interface{})))
+}
+
+func TestLaboratoryPublication(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	// Create a course with a laboratory, it starts as a draft
+	courseUUID, status := CreateCourse("Laboratory publication test - course")
+	c.Equal(http.StatusCreated, status)
+
+	laboratoryCreationResponse, status := CreateLaboratory(teacherCookie, map[string]interface{}{
+		"name":         "Laboratory publication test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	laboratoryResponse, status := GetLaboratoryByUUID(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+	c.False(laboratoryResponse["is_published"].(bool))
+
+	// Add the student to the course
+	invitationCode, status := GetInvitationCode(courseUUID)
+	c.Equal(http.StatusOK, status)
+
+	_, status = AddStudentToCourse(invitationCode)
+	c.Equal(http.StatusOK, status)
+
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	studentCookie := w.Result().Cookies()[0]
+
+	// ## Test: Laboratories without blocks can not be published
+	publicationResponse, status := PublishLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusBadRequest, status)
+	c.Equal([]interface{}{"The laboratory does not have blocks"}, publicationResponse["errors"])
+
+	// ## Test: Students can not find the drafts
+	languagesResponse, status := GetSupportedLanguages(teacherCookie)
+	c.Equal(http.StatusOK, status)
+	firstLanguage := languagesResponse["languages"].([]interface{})[0].(map[string]interface{})
+
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	blockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID: laboratoryUUID,
+		languageUUID:   firstLanguage["uuid"].(string),
+		blockName:      "Laboratory publication test - block",
+		cookie:         teacherCookie,
+		testFile:       zipFile,
+	})
+	c.Equal(http.StatusCreated, status)
+	testBlockUUID := blockCreationResponse["uuid"].(string)
+
+	assertDraftIsHidden := func() {
+		laboratoriesResponse, status := GetCourseLaboratories(studentCookie, courseUUID)
+		c.Equal(http.StatusOK, status)
+		c.Equal(0, len(laboratoriesResponse["laboratories"].([]interface{})))
+
+		_, status = GetLaboratoryByUUID(studentCookie, laboratoryUUID)
+		c.Equal(http.StatusNotFound, status)
+
+		_, status = GetLaboratoryInformationByUUID(studentCookie, laboratoryUUID)
+		c.Equal(http.StatusNotFound, status)
+
+		zipFile, err := GetSampleSubmissionArchive()
+		c.Nil(err)
+
+		_, status = SubmitSolutionToTestBlock(&SubmitSToTestBlockUtilsDTO{
+			blockUUID: testBlockUUID,
+			cookie:    studentCookie,
+			file:      zipFile,
+		})
+		c.Equal(http.StatusForbidden, status)
+	}
+	assertDraftIsHidden()
+
+	// ## Test: Only the owner can publish the laboratory
+	_, status = PublishLaboratory(studentCookie, laboratoryUUID)
+	c.Equal(http.StatusForbidden, status)
+
+	_, status = PublishLaboratory(teacherCookie, "not-valid")
+	c.Equal(http.StatusBadRequest, status)
+
+	// ## Test: Published laboratories are shown to the students
+	_, status = PublishLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	laboratoriesResponse, status := GetCourseLaboratories(studentCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(1, len(laboratoriesResponse["laboratories"].([]interface{})))
+
+	laboratoryResponse, status = GetLaboratoryByUUID(studentCookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+	c.True(laboratoryResponse["is_published"].(bool))
+
+	// ## Test: Unpublished laboratories are hidden again
+	_, status = UnpublishLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	assertDraftIsHidden()
+}
+
+func TestDeleteLaboratory(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	studentCookie := w.Result().Cookies()[0]
+
+	// Create a course with a laboratory
+	courseUUID, status := CreateCourse("Delete laboratory test - course")
+	c.Equal(http.StatusCreated, status)
+
+	laboratoryCreationResponse, status := CreateLaboratory(teacherCookie, map[string]interface{}{
+		"name":         "Delete laboratory test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	// ## Test: Only the owner can delete the laboratory
+	_, status = DeleteLaboratory(studentCookie, laboratoryUUID)
+	c.Equal(http.StatusForbidden, status)
+
+	_, status = DeleteLaboratory(teacherCookie, "not-valid")
+	c.Equal(http.StatusBadRequest, status)
+
+	// ## Test: Deleted laboratories are hidden
+	_, status = DeleteLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	laboratoriesResponse, status := GetCourseLaboratories(teacherCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(0, len(laboratoriesResponse["laboratories"].([]interface{})))
+
+	_, status = GetLaboratoryByUUID(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	_, status = DeleteLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	// ## Test: The teacher can list the deleted laboratories of the course
+	deletedLaboratoriesResponse, status := GetCourseDeletedLaboratories(teacherCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	deletedLaboratories := deletedLaboratoriesResponse["laboratories"].([]interface{})
+	c.Equal(1, len(deletedLaboratories))
+	c.Equal(laboratoryUUID, deletedLaboratories[0].(map[string]interface{})["uuid"])
+	c.NotEmpty(deletedLaboratories[0].(map[string]interface{})["restorable_until"])
+
+	// ## Test: Only the owner can restore the laboratory
+	_, status = RestoreLaboratory(studentCookie, laboratoryUUID)
+	c.Equal(http.StatusForbidden, status)
+
+	_, status = RestoreLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = RestoreLaboratory(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusNotFound, status)
+
+	// ## Test: Restored laboratories are shown again
+	laboratoriesResponse, status = GetCourseLaboratories(teacherCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(1, len(laboratoriesResponse["laboratories"].([]interface{})))
+
+	_, status = GetLaboratoryByUUID(teacherCookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+
+	deletedLaboratoriesResponse, status = GetCourseDeletedLaboratories(teacherCookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(0, len(deletedLaboratoriesResponse["laboratories"].([]interface{})))
+}
+
+func TestCloneLaboratory(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	cookie := w.Result().Cookies()[0]
+
+	// Create a laboratory with a markdown block and a test block
+	sourceCourseUUID, status := CreateCourse("Clone laboratory test - source course")
+	c.Equal(http.StatusCreated, status)
+
+	targetCourseUUID, status := CreateCourse("Clone laboratory test - target course")
+	c.Equal(http.StatusCreated, status)
+
+	laboratoryCreationResponse, status := CreateLaboratory(cookie, map[string]interface{}{
+		"name":         "Clone laboratory test - laboratory",
+		"course_uuid":  sourceCourseUUID,
+		"opening_date": "2023-12-01T12:00:00Z",
+		"due_date":     "2023-12-08T12:00:00Z",
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	blockCreationResponse, status := CreateMarkdownBlock(cookie, laboratoryUUID)
+	c.Equal(http.StatusCreated, status)
+
+	_, status = UpdateMarkdownBlockContent(cookie, blockCreationResponse["uuid"].(string), map[string]interface{}{
+		"content": "# Clone laboratory test",
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	languagesResponse, status := GetSupportedLanguages(cookie)
+	c.Equal(http.StatusOK, status)
+	firstLanguage := languagesResponse["languages"].([]interface{})[0].(map[string]interface{})
+
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	testBlockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID:  laboratoryUUID,
+		languageUUID:    firstLanguage["uuid"].(string),
+		blockName:       "Clone laboratory test - block",
+		cookie:          cookie,
+		testFile:        zipFile,
+		executionLimits: map[string]string{"memory_mb": "256"},
+	})
+	c.Equal(http.StatusCreated, status)
+
+	// Grade the test block automatically with a rubric
+	rubricCreationResponse, status := CreateRubric(cookie, map[string]interface{}{
+		"name": "Clone laboratory test - rubric",
+	})
+	c.Equal(http.StatusCreated, status)
+	rubricUUID := rubricCreationResponse["uuid"].(string)
+
+	objectiveCreationResponse, status := AddObjectiveToRubric(cookie, rubricUUID, map[string]interface{}{
+		"description": "Clone laboratory test - objective",
+	})
+	c.Equal(http.StatusCreated, status)
+	objectiveUUID := objectiveCreationResponse["uuid"].(string)
+
+	criteriaCreationResponse, status := AddCriteriaToObjective(cookie, objectiveUUID, map[string]interface{}{
+		"description": "Clone laboratory test - criteria",
+		"weight":      1.0,
+	})
+	c.Equal(http.StatusCreated, status)
+	criteriaUUID := criteriaCreationResponse["uuid"].(string)
+
+	_, status = UpdateLaboratory(cookie, laboratoryUUID, map[string]interface{}{
+		"rubric_uuid":  rubricUUID,
+		"name":         "Clone laboratory test - laboratory",
+		"opening_date": "2023-12-01T12:00:00Z",
+		"due_date":     "2023-12-08T12:00:00Z",
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = CreateGradingRule(laboratoryUUID, map[string]interface{}{
+		"test_block_uuid": testBlockCreationResponse["uuid"].(string),
+		"objective_uuid":  objectiveUUID,
+		"criteria_uuid":   criteriaUUID,
+		"condition":       "passing",
+	}, cookie)
+	c.Equal(http.StatusCreated, status)
+
+	// Invalid requests
+	_, status = CloneLaboratory(cookie, laboratoryUUID, map[string]interface{}{
+		"course_uuid": targetCourseUUID,
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	_, status = CloneLaboratory(cookie, "not-valid", map[string]interface{}{
+		"course_uuid":  targetCourseUUID,
+		"opening_date": "2024-06-01T12:00:00Z",
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	// Clone the laboratory into the target course
+	cloneResponse, status := CloneLaboratory(cookie, laboratoryUUID, map[string]interface{}{
+		"course_uuid":  targetCourseUUID,
+		"opening_date": "2024-06-01T12:00:00Z",
+	})
+	c.Equal(http.StatusCreated, status)
+	cloneUUID := cloneResponse["uuid"].(string)
+	c.NotEqual(laboratoryUUID, cloneUUID)
+
+	sourceLaboratory, status := GetLaboratoryByUUID(cookie, laboratoryUUID)
+	c.Equal(http.StatusOK, status)
+
+	clonedLaboratory, status := GetLaboratoryByUUID(cookie, cloneUUID)
+	c.Equal(http.StatusOK, status)
+
+	// The name is kept and the dates are shifted
+	c.Equal("Clone laboratory test - laboratory", clonedLaboratory["name"])
+	c.Equal("2024-06-01T12:00:00Z", clonedLaboratory["opening_date"])
+	c.Equal("2024-06-08T12:00:00Z", clonedLaboratory["due_date"])
+
+	// The blocks are copied in the same order
+	markdownBlocks := clonedLaboratory["markdown_blocks"].([]interface{})
+	c.Equal(1, len(markdownBlocks))
+	c.Equal("# Clone laboratory test", markdownBlocks[0].(map[string]interface{})["content"])
+	c.Equal(float64(1), markdownBlocks[0].(map[string]interface{})["index"])
+
+	testBlocks := clonedLaboratory["test_blocks"].([]interface{})
+	c.Equal(1, len(testBlocks))
+
+	sourceTestBlock := sourceLaboratory["test_blocks"].([]interface{})[0].(map[string]interface{})
+	clonedTestBlock := testBlocks[0].(map[string]interface{})
+	c.Equal("Clone laboratory test - block", clonedTestBlock["name"])
+	c.Equal(float64(2), clonedTestBlock["index"])
+	c.NotEqual(sourceTestBlock["uuid"], clonedTestBlock["uuid"])
+	c.NotEqual(sourceTestBlock["test_archive_uuid"], clonedTestBlock["test_archive_uuid"])
+
+	// The execution limits of the block are copied
+	clonedTestBlockResponse, status := GetTestBlock(cookie, clonedTestBlock["uuid"].(string))
+	c.Equal(http.StatusOK, status)
+	limitsOverrides := clonedTestBlockResponse["execution_limits_overrides"].(map[string]interface{})
+	c.Equal(float64(256), limitsOverrides["memory_mb"])
+
+	// The test archive of the copy can be downloaded
+	_, status = GetTestsArchive(clonedTestBlock["uuid"].(string), cookie)
+	c.Equal(http.StatusOK, status)
+
+	// The grading rules are copied to the test block of the copy
+	rulesResponse, status := GetGradingRules(cloneUUID, cookie)
+	c.Equal(http.StatusOK, status)
+	rules := rulesResponse["rules"].([]interface{})
+	c.Equal(1, len(rules))
+
+	rule := rules[0].(map[string]interface{})
+	c.Equal(clonedTestBlock["uuid"], rule["test_block_uuid"])
+	c.Equal(objectiveUUID, rule["objective_uuid"])
+	c.Equal(criteriaUUID, rule["criteria_uuid"])
+	c.Equal("passing", rule["condition"])
+
+	// The laboratory is listed in the target course
+	laboratoriesResponse, status := GetCourseLaboratories(cookie, targetCourseUUID)
+	c.Equal(http.StatusOK, status)
+	c.Equal(1, len(laboratoriesResponse["laboratories"].([]interface{})))
+}
+
+func TestExportAndImportLaboratory(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	cookie := w.Result().Cookies()[0]
+
+	// Create a laboratory with a rubric, a markdown block and a test block
+	sourceCourseUUID, status := CreateCourse("Laboratory bundles test - source course")
+	c.Equal(http.StatusCreated, status)
+
+	targetCourseUUID, status := CreateCourse("Laboratory bundles test - target course")
+	c.Equal(http.StatusCreated, status)
+
+	laboratoryCreationResponse, status := CreateLaboratory(cookie, map[string]interface{}{
+		"name":         "Laboratory bundles test - laboratory",
+		"course_uuid":  sourceCourseUUID,
+		"opening_date": "2023-12-01T12:00:00Z",
+		"due_date":     "2023-12-08T12:00:00Z",
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	rubricCreationResponse, status := CreateRubric(cookie, map[string]interface{}{
+		"name": "Laboratory bundles test - rubric",
+	})
+	c.Equal(http.StatusCreated, status)
+	rubricUUID := rubricCreationResponse["uuid"].(string)
+
+	_, status = UpdateLaboratory(cookie, laboratoryUUID, map[string]interface{}{
+		"name":         "Laboratory bundles test - laboratory",
+		"opening_date": "2023-12-01T12:00:00Z",
+		"due_date":     "2023-12-08T12:00:00Z",
+		"rubric_uuid":  rubricUUID,
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	blockCreationResponse, status := CreateMarkdownBlock(cookie, laboratoryUUID)
+	c.Equal(http.StatusCreated, status)
+
+	_, status = UpdateMarkdownBlockContent(cookie, blockCreationResponse["uuid"].(string), map[string]interface{}{
+		"content": "# Laboratory bundles test",
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	languagesResponse, status := GetSupportedLanguages(cookie)
+	c.Equal(http.StatusOK, status)
+	firstLanguage := languagesResponse["languages"].([]interface{})[0].(map[string]interface{})
+
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	_, status = CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID:  laboratoryUUID,
+		languageUUID:    firstLanguage["uuid"].(string),
+		blockName:       "Laboratory bundles test - block",
+		cookie:          cookie,
+		testFile:        zipFile,
+		executionLimits: map[string]string{"memory_mb": "256"},
+	})
+	c.Equal(http.StatusCreated, status)
+
+	// Export the laboratory
+	_, status = ExportLaboratory(cookie, "not-valid", true)
+	c.Equal(http.StatusBadRequest, status)
+
+	bundle, status := ExportLaboratory(cookie, laboratoryUUID, true)
+	c.Equal(http.StatusOK, status)
+
+	bundleReader, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	c.Nil(err)
+
+	bundleFiles := []string{}
+	for _, file := range bundleReader.File {
+		bundleFiles = append(bundleFiles, file.Name)
+	}
+	c.ElementsMatch([]string{"manifest.json", "tests/2_Laboratory bundles test - block.zip"}, bundleFiles)
+
+	// Invalid imports
+	_, status = ImportLaboratory(cookie, bundle, map[string]string{
+		"course_uuid": targetCourseUUID,
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	_, status = ImportLaboratory(cookie, []byte("not a zip archive"), map[string]string{
+		"course_uuid":  targetCourseUUID,
+		"opening_date": "2024-06-01T12:00:00Z",
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	// Import the laboratory into the target course
+	importResponse, status := ImportLaboratory(cookie, bundle, map[string]string{
+		"course_uuid":  targetCourseUUID,
+		"opening_date": "2024-06-01T12:00:00Z",
+		"name":         "Laboratory bundles test - imported laboratory",
+	})
+	c.Equal(http.StatusCreated, status)
+	importedUUID := importResponse["uuid"].(string)
+
+	importedLaboratory, status := GetLaboratoryByUUID(cookie, importedUUID)
+	c.Equal(http.StatusOK, status)
+
+	// The dates keep the duration of the laboratory
+	c.Equal("Laboratory bundles test - imported laboratory", importedLaboratory["name"])
+	c.Equal("2024-06-01T12:00:00Z", importedLaboratory["opening_date"])
+	c.Equal("2024-06-08T12:00:00Z", importedLaboratory["due_date"])
+
+	// The blocks are created in the same order
+	markdownBlocks := importedLaboratory["markdown_blocks"].([]interface{})
+	c.Equal(1, len(markdownBlocks))
+	c.Equal("# Laboratory bundles test", markdownBlocks[0].(map[string]interface{})["content"])
+
+	testBlocks := importedLaboratory["test_blocks"].([]interface{})
+	c.Equal(1, len(testBlocks))
+
+	importedTestBlock := testBlocks[0].(map[string]interface{})
+	c.Equal("Laboratory bundles test - block", importedTestBlock["name"])
+	c.Equal(firstLanguage["uuid"], importedTestBlock["language_uuid"])
+	c.Equal(float64(2), importedTestBlock["index"])
+
+	importedTestBlockResponse, status := GetTestBlock(cookie, importedTestBlock["uuid"].(string))
+	c.Equal(http.StatusOK, status)
+	limitsOverrides := importedTestBlockResponse["execution_limits_overrides"].(map[string]interface{})
+	c.Equal(float64(256), limitsOverrides["memory_mb"])
+
+	_, status = GetTestsArchive(importedTestBlock["uuid"].(string), cookie)
+	c.Equal(http.StatusOK, status)
+
+	// A new rubric is created for the teacher
+	importedRubricUUID, ok := importedLaboratory["rubric_uuid"].(string)
+	c.True(ok)
+	c.NotEqual(rubricUUID, importedRubricUUID)
+
+	importedRubricResponse, status := GetRubricByUUID(cookie, importedRubricUUID)
+	c.Equal(http.StatusOK, status)
+	importedRubric := importedRubricResponse["rubric"].(map[string]interface{})
+	c.Equal("Laboratory bundles test - rubric", importedRubric["name"])
+	c.Equal(1, len(importedRubric["objectives"].([]interface{})))
 }
@@ -1,8 +1,10 @@
 package integration
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/stretchr/testify/require"
@@ -29,12 +31,18 @@ func TestListSupportedLanguages(t *testing.T) {
 
 	// Get all the languages names
 	var languagesNames []string
+	languagesRequiredPaths := map[string]interface{}{}
 	for _, language := range languages {
-		languagesNames = append(languagesNames, language.(map[string]interface{})["name"].(string))
+		languageName := language.(map[string]interface{})["name"].(string)
+		languagesNames = append(languagesNames, languageName)
+		languagesRequiredPaths[languageName] = language.(map[string]interface{})["required_paths"]
 	}
 
 	// Check if the supported languages are included
 	c.Contains(languagesNames, "Java JDK 17")
+
+	// Check the layout of the projects is included
+	c.Equal([]interface{}{"src/main/java"}, languagesRequiredPaths["Java JDK 17"])
 }
 
 func GetFirstSupportedLanguage(cookie *http.Cookie) (language map[string]interface{}) {
@@ -74,3 +82,183 @@ func TestGetLanguageTemplate(t *testing.T) {
 	mtype := mimetype.Detect(template)
 	c.Equal("application/zip", mtype.String())
 }
+
+func TestLanguagesManagement(t *testing.T) {
+	c := require.New(t)
+
+	// Login as an admin
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredAdminEmail,
+		"password": registeredAdminPass,
+	})
+	router.ServeHTTP(w, r)
+	adminCookie := w.Result().Cookies()[0]
+
+	// Login as a teacher
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	// Language names are unique, so a new one is used in each run
+	languageName := fmt.Sprintf("Python %d", time.Now().UnixNano()%1000000)
+
+	// Teachers cannot create languages
+	templateFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	_, status := CreateLanguage(&CreateLanguageUtilsDTO{
+		name:          languageName,
+		requiredPaths: []string{"src/main/java"},
+		templateFile:  templateFile,
+		cookie:        teacherCookie,
+	})
+	c.Equal(http.StatusForbidden, status)
+
+	// The template must follow the layout of the language
+	templateFile, err = GetSampleTestsArchive()
+	c.Nil(err)
+
+	response, status := CreateLanguage(&CreateLanguageUtilsDTO{
+		name:          languageName,
+		requiredPaths: []string{"app"},
+		templateFile:  templateFile,
+		cookie:        adminCookie,
+	})
+	c.Equal(http.StatusBadRequest, status)
+	c.Contains(response["errors"], "The project must contain the `app` directory")
+
+	// Admins can create languages
+	templateFile, err = GetSampleTestsArchive()
+	c.Nil(err)
+
+	response, status = CreateLanguage(&CreateLanguageUtilsDTO{
+		name:            languageName,
+		requiredPaths:   []string{"src/main/java"},
+		templateFile:    templateFile,
+		cookie:          adminCookie,
+		executionLimits: map[string]string{"wall_time_ms": "5000"},
+	})
+	c.Equal(http.StatusCreated, status)
+	languageUUID := response["uuid"].(string)
+
+	// The names cannot be repeated
+	templateFile, err = GetSampleTestsArchive()
+	c.Nil(err)
+
+	_, status = CreateLanguage(&CreateLanguageUtilsDTO{
+		name:          languageName,
+		requiredPaths: []string{"src/main/java"},
+		templateFile:  templateFile,
+		cookie:        adminCookie,
+	})
+	c.Equal(http.StatusConflict, status)
+
+	// Admins can replace the template
+	templateFile, err = GetSampleTestsArchive()
+	c.Nil(err)
+
+	_, status = UpdateLanguageTemplate(adminCookie, languageUUID, templateFile)
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = GetLanguageTemplate(teacherCookie, languageUUID)
+	c.Equal(http.StatusOK, status)
+
+	// Admins can rename the languages
+	languageName = languageName + " - renamed"
+	_, status = RenameLanguage(adminCookie, languageUUID, languageName)
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = RenameLanguage(adminCookie, languageUUID, "Java JDK 17")
+	c.Equal(http.StatusConflict, status)
+
+	// Admins can deprecate the languages
+	_, status = SetLanguageDeprecation(adminCookie, languageUUID, true)
+	c.Equal(http.StatusNoContent, status)
+
+	languagesResponse, status := GetSupportedLanguages(teacherCookie)
+	c.Equal(http.StatusOK, status)
+
+	var deprecatedLanguage map[string]interface{}
+	for _, language := range languagesResponse["languages"].([]interface{}) {
+		if language.(map[string]interface{})["uuid"] == languageUUID {
+			deprecatedLanguage = language.(map[string]interface{})
+		}
+	}
+	c.NotNil(deprecatedLanguage)
+	c.Equal(languageName, deprecatedLanguage["name"])
+	c.Equal(true, deprecatedLanguage["is_deprecated"])
+
+	// The limits that were not sent when the language was created are the default ones
+	limits := deprecatedLanguage["execution_limits"].(map[string]interface{})
+	c.Equal(5000.0, limits["wall_time_ms"])
+	c.Equal(10000.0, limits["cpu_time_ms"])
+
+	// Admins can update some of the limits
+	_, status = UpdateLanguageExecutionLimits(adminCookie, languageUUID, map[string]interface{}{
+		"memory_mb": 1,
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	_, status = UpdateLanguageExecutionLimits(adminCookie, languageUUID, map[string]interface{}{
+		"memory_mb": 1024,
+	})
+	c.Equal(http.StatusNoContent, status)
+
+	_, status = UpdateLanguageExecutionLimits(teacherCookie, languageUUID, map[string]interface{}{
+		"memory_mb": 1024,
+	})
+	c.Equal(http.StatusForbidden, status)
+
+	// Deprecated languages cannot be chosen for new test blocks
+	courseUUID, status := CreateCourse("Languages management test - course")
+	c.Equal(http.StatusCreated, status)
+
+	laboratoryCreationResponse, status := CreateLaboratory(teacherCookie, map[string]interface{}{
+		"name":         "Languages management test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	testFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	_, status = CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID: laboratoryUUID,
+		languageUUID:   languageUUID,
+		blockName:      "Languages management test - block",
+		cookie:         teacherCookie,
+		testFile:       testFile,
+	})
+	c.Equal(http.StatusBadRequest, status)
+
+	// Restored languages can be chosen again
+	_, status = SetLanguageDeprecation(adminCookie, languageUUID, false)
+	c.Equal(http.StatusNoContent, status)
+
+	testFile, err = GetSampleTestsArchive()
+	c.Nil(err)
+
+	blockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID: laboratoryUUID,
+		languageUUID:   languageUUID,
+		blockName:      "Languages management test - block",
+		cookie:         teacherCookie,
+		testFile:       testFile,
+	})
+	c.Equal(http.StatusCreated, status)
+
+	// The test blocks use the updated limits of the language
+	testBlockResponse, status := GetTestBlock(teacherCookie, blockCreationResponse["uuid"].(string))
+	c.Equal(http.StatusOK, status)
+	c.Equal(1024.0, testBlockResponse["execution_limits"].(map[string]interface{})["memory_mb"])
+
+	// The language must exist
+	_, status = SetLanguageDeprecation(adminCookie, "8d3f9a1c-4b2e-4c7d-9e6f-1a2b3c4d5e6f", true)
+	c.Equal(http.StatusNotFound, status)
+}
@@ -1,8 +1,13 @@
 package integration
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"os"
 )
 
 func GetSupportedLanguages(cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
@@ -19,3 +24,101 @@ func GetLanguageTemplate(cookie *http.Cookie, uuid string) (bytes []byte, status
 	router.ServeHTTP(w, r)
 	return w.Body.Bytes(), w.Code
 }
+
+type CreateLanguageUtilsDTO struct {
+	name          string
+	requiredPaths []string
+	templateFile  *os.File
+	cookie        *http.Cookie
+
+	// Optional execution limits fields (e.g. `cpu_time_ms`)
+	executionLimits map[string]string
+}
+
+func CreateLanguage(dto *CreateLanguageUtilsDTO) (response map[string]interface{}, statusCode int) {
+	fields := map[string][]string{
+		"name":           {dto.name},
+		"required_paths": dto.requiredPaths,
+	}
+	for field, value := range dto.executionLimits {
+		fields[field] = []string{value}
+	}
+
+	return sendLanguageTemplateForm("POST", "/api/v1/languages", fields, dto.templateFile, dto.cookie)
+}
+
+func UpdateLanguageTemplate(cookie *http.Cookie, uuid string, templateFile *os.File) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/languages/%s/template", uuid)
+	return sendLanguageTemplateForm("PUT", endpoint, map[string][]string{}, templateFile, cookie)
+}
+
+func RenameLanguage(cookie *http.Cookie, uuid string, name string) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/languages/%s/name", uuid)
+	w, r := PrepareRequest("PATCH", endpoint, map[string]interface{}{
+		"name": name,
+	})
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+	return ParseJsonResponse(w.Body), w.Code
+}
+
+func SetLanguageDeprecation(cookie *http.Cookie, uuid string, isDeprecated bool) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/languages/%s/deprecation", uuid)
+	w, r := PrepareRequest("PATCH", endpoint, map[string]interface{}{
+		"is_deprecated": isDeprecated,
+	})
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+	return ParseJsonResponse(w.Body), w.Code
+}
+
+func UpdateLanguageExecutionLimits(cookie *http.Cookie, uuid string, payload map[string]interface{}) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/languages/%s/execution_limits", uuid)
+	w, r := PrepareRequest("PATCH", endpoint, payload)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+	return ParseJsonResponse(w.Body), w.Code
+}
+
+func sendLanguageTemplateForm(method string, endpoint string, fields map[string][]string, templateFile *os.File, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	// Create the multipart form
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	// Add the file to the form
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", "form-data; name=\"template_archive\"; filename=\"template.zip\"")
+	h.Set("Content-Type", "application/zip")
+
+	fileWriter, err := writer.CreatePart(h)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = io.Copy(fileWriter, templateFile)
+	if err != nil {
+		panic(err)
+	}
+
+	// Add the text fields to the form
+	for name, values := range fields {
+		for _, value := range values {
+			if err := writer.WriteField(name, value); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		panic(err)
+	}
+
+	// Send the request
+	w, r := PrepareMultipartRequest(method, endpoint, &body)
+	r.AddCookie(cookie)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	router.ServeHTTP(w, r)
+
+	return ParseJsonResponse(w.Body), w.Code
+}
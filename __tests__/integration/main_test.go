@@ -10,7 +10,9 @@ import (
 
 	"github.com/UPB-Code-Labs/main-api/src/accounts/infrastructure/requests"
 	configInfrastructure "github.com/UPB-Code-Labs/main-api/src/config/infrastructure"
+	plagiarismImplementations "github.com/UPB-Code-Labs/main-api/src/plagiarism/infrastructure/implementations"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	staticFilesImplementations "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
 	submissionsImplementations "github.com/UPB-Code-Labs/main-api/src/submissions/infrastructure/implementations"
 	"github.com/gin-gonic/gin"
 )
@@ -56,6 +58,9 @@ func TestMain(m *testing.M) {
 	// Setup SSE
 	setupSSE()
 
+	// Setup background jobs
+	setupBackgroundJobs()
+
 	// Setup http router
 	setupRouter()
 	registerBaseAccounts()
@@ -77,6 +82,10 @@ func setupRabbitMQ() {
 	// Start listening for messages in the submissions real time updates queue
 	submissionsRealTimeUpdatesQueueMgr := submissionsImplementations.GetSubmissionsRealTimeUpdatesQueueMgrInstance()
 	go submissionsRealTimeUpdatesQueueMgr.ListenForUpdates()
+
+	// Start saving the submission works sent to the dead letter queue
+	submissionsDeadLetterQueueMgr := submissionsImplementations.GetSubmissionsDeadLetterQueueMgrInstance()
+	go submissionsDeadLetterQueueMgr.ListenForDeadLetters()
 }
 
 func setupSSE() {
@@ -85,6 +94,24 @@ func setupSSE() {
 	go realTimeSubmissionsUpdatesSender.Listen()
 }
 
+func setupBackgroundJobs() {
+	// Start generating the plagiarism reports in background
+	plagiarismReportsQueue := plagiarismImplementations.GetPlagiarismReportsQueueInstance()
+	go plagiarismReportsQueue.Listen()
+
+	// Start publishing the submission works saved in the outbox
+	submissionsOutboxRelay := submissionsImplementations.GetSubmissionsOutboxRelayInstance()
+	go submissionsOutboxRelay.Start()
+
+	// Start looking for stuck submissions
+	submissionsWatchdog := submissionsImplementations.GetSubmissionsWatchdogInstance()
+	go submissionsWatchdog.Start()
+
+	// Start collecting the archives that are no longer referenced
+	orphanedArchivesCollector := staticFilesImplementations.GetOrphanedArchivesCollectorInstance()
+	go orphanedArchivesCollector.Start()
+}
+
 func setupRouter() {
 	router = configInfrastructure.InstanceHttpServer()
 }
@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlagiarismReports(t *testing.T) {
+	c := require.New(t)
+
+	// ## Test preparation
+	// Login as a teacher
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	cookie := w.Result().Cookies()[0]
+
+	// Create a course
+	courseUUID, status := CreateCourse("Plagiarism reports test - course")
+	c.Equal(http.StatusCreated, status)
+
+	// Create a laboratory
+	laboratoryCreationResponse, status := CreateLaboratory(cookie, map[string]interface{}{
+		"name":         "Plagiarism reports test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	// Add the student to the course
+	invitationCode, status := GetInvitationCode(courseUUID)
+	c.Equal(http.StatusOK, status)
+
+	_, status = AddStudentToCourse(invitationCode)
+	c.Equal(http.StatusOK, status)
+
+	// Create a test block
+	languagesResponse, status := GetSupportedLanguages(cookie)
+	c.Equal(http.StatusOK, status)
+	languages := languagesResponse["languages"].([]interface{})
+	firstLanguageUUID := languages[0].(map[string]interface{})["uuid"].(string)
+
+	zipFile, err := GetSampleTestsArchive()
+	c.Nil(err)
+
+	blockCreationResponse, status := CreateTestBlock(&CreateTestBlockUtilsDTO{
+		laboratoryUUID: laboratoryUUID,
+		languageUUID:   firstLanguageUUID,
+		blockName:      "Plagiarism reports test - block",
+		cookie:         cookie,
+		testFile:       zipFile,
+	})
+	c.Equal(http.StatusCreated, status)
+	testBlockUUID := blockCreationResponse["uuid"].(string)
+
+	_, status = PublishLaboratory(cookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
+	// Submit a solution as a student
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	studentCookie := w.Result().Cookies()[0]
+
+	zipFile, err = GetSampleSubmissionArchive()
+	c.Nil(err)
+
+	_, status = SubmitSolutionToTestBlock(&SubmitSToTestBlockUtilsDTO{
+		blockUUID: testBlockUUID,
+		file:      zipFile,
+		cookie:    studentCookie,
+	})
+	c.Equal(http.StatusCreated, status)
+
+	// ## Test
+	// Validation errors
+	_, status = CreatePlagiarismReport("not-a-uuid", nil, cookie)
+	c.Equal(http.StatusBadRequest, status)
+
+	_, status = CreatePlagiarismReport(testBlockUUID, map[string]interface{}{
+		"min_similarity_percentage": 101,
+	}, cookie)
+	c.Equal(http.StatusBadRequest, status)
+
+	_, status = GetPlagiarismReport("not-a-uuid", cookie)
+	c.Equal(http.StatusBadRequest, status)
+
+	// Students can not request reports
+	_, status = CreatePlagiarismReport(testBlockUUID, nil, studentCookie)
+	c.Equal(http.StatusForbidden, status)
+
+	// Create a report
+	response, status := CreatePlagiarismReport(testBlockUUID, map[string]interface{}{
+		"min_similarity_percentage": 50,
+	}, cookie)
+	c.Equal(http.StatusAccepted, status)
+	reportUUID := response["uuid"].(string)
+	c.NotEmpty(reportUUID)
+
+	// Wait for the report to be generated in background
+	var report map[string]interface{}
+	for attempt := 0; attempt < 20; attempt++ {
+		report, status = GetPlagiarismReport(reportUUID, cookie)
+		c.Equal(http.StatusOK, status)
+
+		if report["status"] == "ready" || report["status"] == "failed" {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	c.Equal("ready", report["status"])
+	c.Equal(testBlockUUID, report["test_block_uuid"])
+	c.Equal(50.0, report["min_similarity_percentage"])
+	c.Equal(1.0, report["analyzed_submissions"])
+	c.NotNil(report["finished_at"])
+
+	// A single submission can not be compared against others
+	c.Equal(0, len(report["pairs"].([]interface{})))
+
+	// Other teachers can not read the report
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    secondRegisteredTeacherEmail,
+		"password": secondRegisteredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	secondTeacherCookie := w.Result().Cookies()[0]
+
+	_, status = GetPlagiarismReport(reportUUID, secondTeacherCookie)
+	c.Equal(http.StatusForbidden, status)
+
+	_, status = CreatePlagiarismReport(testBlockUUID, nil, secondTeacherCookie)
+	c.Equal(http.StatusForbidden, status)
+}
@@ -0,0 +1,26 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func CreatePlagiarismReport(testBlockUUID string, payload map[string]interface{}, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/plagiarism/test_blocks/%s/reports", testBlockUUID)
+	w, r := PrepareRequest("POST", endpoint, payload)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func GetPlagiarismReport(reportUUID string, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/plagiarism/reports/%s", reportUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
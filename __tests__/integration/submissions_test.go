@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	submissionsDTOs "github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+	submissionsEntities "github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
+	submissionsImplementations "github.com/UPB-Code-Labs/main-api/src/submissions/infrastructure/implementations"
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/stretchr/testify/require"
 )
@@ -63,6 +66,9 @@ func TestSubmitSolutionToTestBlock(t *testing.T) {
 	c.Equal(http.StatusCreated, status)
 	testBlockUUID := blockCreationResponse["uuid"].(string)
 
+	_, status = PublishLaboratory(cookie, laboratoryUUID)
+	c.Equal(http.StatusNoContent, status)
+
 	// Add a student to the course
 	invitationCode, code := GetInvitationCode(courseUUID)
 	c.Equal(http.StatusOK, code)
@@ -181,4 +187,206 @@ func TestSubmitSolutionToTestBlock(t *testing.T) {
 
 	mType = mimetype.Detect(archive)
 	c.Equal("application/zip", mType.String())
+
+	// ## Test the real time updates of the laboratory as teacher
+	laboratoryUpdates, unsubscribe := SubscribeToLaboratorySubmissionsUpdates(laboratoryUUID, cookie)
+	defer unsubscribe()
+
+	// The update is sent again until the subscription is registered
+	var laboratoryUpdate *submissionsDTOs.LaboratorySubmissionUpdateDTO
+	laboratoriesRealTimeUpdater := submissionsImplementations.GetLaboratoriesRealTimeUpdatesSenderInstance()
+	for attempt := 0; attempt < 20 && laboratoryUpdate == nil; attempt++ {
+		laboratoriesRealTimeUpdater.SendSubmissionUpdate(submissionUUID)
+
+		select {
+		case laboratoryUpdate = <-laboratoryUpdates:
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+
+	c.NotNil(laboratoryUpdate)
+	c.Equal(submissionUUID, laboratoryUpdate.SubmissionUUID)
+	c.Equal(testBlockUUID, laboratoryUpdate.TestBlockUUID)
+	c.Equal("ready", laboratoryUpdate.SubmissionStatus)
+	c.True(laboratoryUpdate.TestsPassed)
+
+	// ## Test get submissions history as teacher
+	students, status := GetStudentsEnrolledInCourse(cookie, courseUUID)
+	c.Equal(http.StatusOK, status)
+	studentUUID := students["students"].([]interface{})[0].(map[string]interface{})["uuid"].(string)
+
+	historyResponse, status := GetStudentSubmissionsHistory(testBlockUUID, studentUUID, cookie)
+	c.Equal(http.StatusOK, status)
+
+	attempts := historyResponse["submissions"].([]interface{})
+	c.Equal(1, len(attempts))
+
+	lastAttempt := attempts[0].(map[string]interface{})
+	c.Equal(submissionUUID, lastAttempt["submission_uuid"])
+	c.Equal(float64(1), lastAttempt["attempt_number"])
+	c.Equal("ready", lastAttempt["status"])
+	c.Equal(true, lastAttempt["passing"])
+	c.NotEmpty(lastAttempt["submitted_at"])
+
+	// Invalid student UUID
+	_, status = GetStudentSubmissionsHistory(testBlockUUID, "not-valid", cookie)
+	c.Equal(http.StatusBadRequest, status)
+
+	// ## Test rerun the submissions of the test block
+	rerunResponse, status := RerunTestBlockSubmissions(testBlockUUID, cookie)
+	c.Equal(http.StatusAccepted, status)
+	c.Equal("manual", rerunResponse["trigger"])
+	c.Equal(float64(1), rerunResponse["total_submissions"])
+	c.Equal(float64(0), rerunResponse["not_queued_submissions"])
+	rerunUUID := rerunResponse["uuid"].(string)
+
+	// The results of the previous evaluation are removed while the submission is evaluated again
+	submissionsRepository := submissionsImplementations.GetSubmissionsRepositoryInstance()
+	testResults, err := submissionsRepository.GetSubmissionTestResults(submissionUUID)
+	c.Nil(err)
+	c.Empty(testResults)
+
+	rerunsResponse, status := GetTestBlockSubmissionsReruns(testBlockUUID, cookie)
+	c.Equal(http.StatusOK, status)
+
+	reruns := rerunsResponse["reruns"].([]interface{})
+	c.Equal(1, len(reruns))
+	c.Equal(rerunUUID, reruns[0].(map[string]interface{})["uuid"])
+
+	// Wait for the submission to be evaluated again
+	for attempt := 0; attempt < 40; attempt++ {
+		rerunResponse, status = GetSubmissionsRerun(rerunUUID, cookie)
+		c.Equal(http.StatusOK, status)
+
+		if rerunResponse["is_completed"] == true {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	c.Equal(true, rerunResponse["is_completed"])
+	c.Equal(float64(1), rerunResponse["ready_submissions"])
+
+	// The rerun does not create a new attempt
+	historyResponse, status = GetStudentSubmissionsHistory(testBlockUUID, studentUUID, cookie)
+	c.Equal(http.StatusOK, status)
+	c.Equal(1, len(historyResponse["submissions"].([]interface{})))
+
+	// The statuses reported by the tests runner are normalized before saving them
+	err = submissionsRepository.SaveSubmissionTestResults(submissionUUID, []*submissionsEntities.SubmissionTestResult{
+		{Name: "first", Status: "PASS"},
+		{Name: "second", Status: "timeout"},
+	})
+	c.Nil(err)
+
+	testResults, err = submissionsRepository.GetSubmissionTestResults(submissionUUID)
+	c.Nil(err)
+	c.Equal(2, len(testResults))
+	c.Equal("passed", testResults[0].Status)
+	c.Equal("error", testResults[1].Status)
+	c.NotEmpty(testResults[1].FailureMessage)
+
+	// Invalid UUIDs
+	_, status = RerunTestBlockSubmissions("not-valid", cookie)
+	c.Equal(http.StatusBadRequest, status)
+
+	_, status = GetSubmissionsRerun("not-valid", cookie)
+	c.Equal(http.StatusBadRequest, status)
+
+	// Other teachers can not rerun the submissions
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    secondRegisteredTeacherEmail,
+		"password": secondRegisteredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	secondTeacherCookie := w.Result().Cookies()[0]
+
+	_, status = RerunTestBlockSubmissions(testBlockUUID, secondTeacherCookie)
+	c.Equal(http.StatusForbidden, status)
+
+	_, status = GetSubmissionsRerun(rerunUUID, secondTeacherCookie)
+	c.Equal(http.StatusForbidden, status)
+}
+
+func TestGetLaboratorySubmissionsUpdates(t *testing.T) {
+	c := require.New(t)
+
+	// Login as a student
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredStudentEmail,
+		"password": registeredStudentPass,
+	})
+	router.ServeHTTP(w, r)
+	studentCookie := w.Result().Cookies()[0]
+
+	// Login as a teacher
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	// Create a laboratory
+	courseUUID, status := CreateCourse("Get laboratory submissions updates test - course")
+	c.Equal(http.StatusCreated, status)
+
+	laboratoryCreationResponse, status := CreateLaboratory(teacherCookie, map[string]interface{}{
+		"name":         "Get laboratory submissions updates test - laboratory",
+		"course_uuid":  courseUUID,
+		"opening_date": defaultLaboratoryOpeningDate,
+		"due_date":     defaultLaboratoryDueDate,
+	})
+	c.Equal(http.StatusCreated, status)
+	laboratoryUUID := laboratoryCreationResponse["uuid"].(string)
+
+	// Students cannot subscribe to the updates of a laboratory
+	status = GetRealTimeLaboratorySubmissionsUpdatesStatus(laboratoryUUID, studentCookie)
+	c.Equal(http.StatusForbidden, status)
+
+	// The laboratory UUID must be valid
+	status = GetRealTimeLaboratorySubmissionsUpdatesStatus("not-valid", teacherCookie)
+	c.Equal(http.StatusBadRequest, status)
+}
+
+func TestSubmissionsDeadLetters(t *testing.T) {
+	c := require.New(t)
+
+	// Login as an admin
+	w, r := PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredAdminEmail,
+		"password": registeredAdminPass,
+	})
+	router.ServeHTTP(w, r)
+	adminCookie := w.Result().Cookies()[0]
+
+	// Login as a teacher
+	w, r = PrepareRequest("POST", "/api/v1/session/login", map[string]interface{}{
+		"email":    registeredTeacherEmail,
+		"password": registeredTeacherPass,
+	})
+	router.ServeHTTP(w, r)
+	teacherCookie := w.Result().Cookies()[0]
+
+	// Admins can list the dead-lettered submissions
+	response, status := GetSubmissionsDeadLetters(adminCookie)
+	c.Equal(http.StatusOK, status)
+	c.NotNil(response["dead_letters"])
+
+	// Teachers cannot list the dead-lettered submissions
+	_, status = GetSubmissionsDeadLetters(teacherCookie)
+	c.Equal(http.StatusForbidden, status)
+
+	// Teachers cannot replay a dead-lettered submission
+	randomUUID := "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c"
+	_, status = ReplaySubmissionDeadLetter(randomUUID, teacherCookie)
+	c.Equal(http.StatusForbidden, status)
+
+	// The dead letter must exist
+	_, status = ReplaySubmissionDeadLetter(randomUUID, adminCookie)
+	c.Equal(http.StatusNotFound, status)
+
+	// The dead letter UUID must be valid
+	_, status = ReplaySubmissionDeadLetter("not-valid", adminCookie)
+	c.Equal(http.StatusBadRequest, status)
 }
@@ -1,13 +1,19 @@
 package integration
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+
+	submissionsDTOs "github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
 )
 
 type SubmitSToTestBlockUtilsDTO struct {
@@ -107,3 +113,127 @@ func GetSubmissionArchive(submissionUUID string, cookie *http.Cookie) (bytes []b
 	router.ServeHTTP(w, r)
 	return w.Body.Bytes(), w.Code
 }
+
+func GetStudentSubmissionsHistory(testBlockUUID, studentUUID string, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/submissions/test_blocks/%s/students/%s/history", testBlockUUID, studentUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+// GetRealTimeLaboratorySubmissionsUpdatesStatus sends a request to subscribe to the real time updates of a laboratory
+// and returns the status code. It should only be used for requests that are expected to be rejected
+func GetRealTimeLaboratorySubmissionsUpdatesStatus(laboratoryUUID string, cookie *http.Cookie) (statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/submissions/laboratories/%s/status", laboratoryUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	return w.Code
+}
+
+// SubscribeToLaboratorySubmissionsUpdates subscribes to the real time updates of a laboratory through a test
+// server, as the updates are streamed until the client closes the connection. The updates are sent to the
+// returned channel until the returned function is called
+func SubscribeToLaboratorySubmissionsUpdates(laboratoryUUID string, cookie *http.Cookie) (updates <-chan *submissionsDTOs.LaboratorySubmissionUpdateDTO, unsubscribe func()) {
+	server := httptest.NewServer(router)
+	ctx, cancel := context.WithCancel(context.Background())
+	updatesCh := make(chan *submissionsDTOs.LaboratorySubmissionUpdateDTO, 16)
+
+	go func() {
+		defer close(updatesCh)
+
+		// Create the request
+		endpoint := fmt.Sprintf("%s/api/v1/submissions/laboratories/%s/status", server.URL, laboratoryUUID)
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return
+		}
+		req.AddCookie(cookie)
+
+		// Send the request
+		res, err := server.Client().Do(req)
+		if err != nil {
+			return
+		}
+		defer res.Body.Close()
+
+		// Parse the data events
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			var update submissionsDTOs.LaboratorySubmissionUpdateDTO
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if err := json.Unmarshal([]byte(data), &update); err != nil {
+				continue
+			}
+
+			select {
+			case updatesCh <- &update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updatesCh, func() {
+		cancel()
+		server.Close()
+	}
+}
+
+func RerunTestBlockSubmissions(testBlockUUID string, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/submissions/test_blocks/%s/reruns", testBlockUUID)
+	w, r := PrepareRequest("POST", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func GetTestBlockSubmissionsReruns(testBlockUUID string, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/submissions/test_blocks/%s/reruns", testBlockUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func GetSubmissionsRerun(rerunUUID string, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/submissions/reruns/%s", rerunUUID)
+	w, r := PrepareRequest("GET", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func GetSubmissionsDeadLetters(cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	w, r := PrepareRequest("GET", "/api/v1/submissions/dead_letters", nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
+
+func ReplaySubmissionDeadLetter(deadLetterUUID string, cookie *http.Cookie) (response map[string]interface{}, statusCode int) {
+	endpoint := fmt.Sprintf("/api/v1/submissions/dead_letters/%s/replay", deadLetterUUID)
+	w, r := PrepareRequest("POST", endpoint, nil)
+	r.AddCookie(cookie)
+	router.ServeHTTP(w, r)
+
+	jsonResponse := ParseJsonResponse(w.Body)
+	return jsonResponse, w.Code
+}
@@ -0,0 +1,43 @@
+-- ## Views
+DROP VIEW IF EXISTS students_progress_view;
+
+DROP VIEW IF EXISTS latest_submissions;
+
+CREATE OR REPLACE VIEW students_progress_view AS
+SELECT
+  users.id AS student_id,
+  users.full_name as student_full_name,
+  test_blocks.laboratory_id,
+  COUNT(submissions.id) FILTER (
+	  WHERE submissions.status = 'pending'
+  ) AS pending_submissions,
+  COUNT(submissions.id) FILTER (
+	  WHERE submissions.status = 'running'
+  ) AS running_submissions,
+  COUNT(submissions.id) FILTER (
+	  WHERE submissions.status = 'ready' AND submissions.passing = FALSE
+  ) AS failing_submissions,
+  COUNT(submissions.id) FILTER (
+	  WHERE submissions.status = 'ready' AND submissions.passing = TRUE
+  ) AS success_submissions
+FROM
+  submissions
+JOIN
+  users ON submissions.student_id = users.id
+JOIN
+  test_blocks ON submissions.test_block_id = test_blocks.id
+GROUP BY
+  users.id, users.full_name, test_blocks.laboratory_id;
+
+-- ## Indexes
+DROP INDEX IF EXISTS idx_submissions_attempts;
+
+-- Keep only the latest attempt of each student before restoring the unique index
+DELETE FROM submissions
+WHERE id NOT IN (
+  SELECT DISTINCT ON (test_block_id, student_id) id
+  FROM submissions
+  ORDER BY test_block_id, student_id, submitted_at DESC
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_submissions ON submissions(test_block_id, student_id);
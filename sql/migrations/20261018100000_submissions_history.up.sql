@@ -0,0 +1,44 @@
+-- ## Indexes
+-- Students can have multiple submissions (attempts) per test block
+DROP INDEX IF EXISTS idx_submissions;
+
+CREATE INDEX IF NOT EXISTS idx_submissions_attempts ON submissions(test_block_id, student_id, submitted_at DESC);
+
+-- ## Views
+--- ### Latest submission of each student in each test block
+CREATE OR REPLACE VIEW latest_submissions AS
+SELECT DISTINCT ON (submissions.test_block_id, submissions.student_id)
+  submissions.*
+FROM
+  submissions
+ORDER BY
+  submissions.test_block_id, submissions.student_id, submissions.submitted_at DESC;
+
+--- ### Students progress (Only the latest submission is taken into account)
+DROP VIEW IF EXISTS students_progress_view;
+
+CREATE OR REPLACE VIEW students_progress_view AS
+SELECT
+  users.id AS student_id,
+  users.full_name as student_full_name,
+  test_blocks.laboratory_id,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'pending'
+  ) AS pending_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'running'
+  ) AS running_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'ready' AND latest_submissions.passing = FALSE
+  ) AS failing_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'ready' AND latest_submissions.passing = TRUE
+  ) AS success_submissions
+FROM
+  latest_submissions
+JOIN
+  users ON latest_submissions.student_id = users.id
+JOIN
+  test_blocks ON latest_submissions.test_block_id = test_blocks.id
+GROUP BY
+  users.id, users.full_name, test_blocks.laboratory_id;
@@ -0,0 +1,8 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_submission_test_results;
+
+-- ## Tables
+DROP TABLE IF EXISTS submission_test_results;
+
+-- ## Types
+DROP TYPE IF EXISTS TEST_RESULT_STATUS;
@@ -0,0 +1,16 @@
+-- ## Types
+CREATE TYPE TEST_RESULT_STATUS AS ENUM ('passed', 'failed', 'error', 'skipped');
+
+-- ## Tables
+CREATE TABLE IF NOT EXISTS submission_test_results (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "submission_id" UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+  "position" INTEGER NOT NULL,
+  "name" VARCHAR NOT NULL,
+  "status" TEST_RESULT_STATUS NOT NULL,
+  "duration_ms" INTEGER NOT NULL DEFAULT 0,
+  "failure_message" TEXT NOT NULL DEFAULT ''
+);
+
+-- ## Indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_submission_test_results ON submission_test_results(submission_id, position);
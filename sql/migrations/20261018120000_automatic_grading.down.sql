@@ -0,0 +1,12 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_grading_rules_objective;
+
+DROP INDEX IF EXISTS idx_grading_rules_test_block;
+
+-- ## Tables
+ALTER TABLE grade_has_criteria DROP COLUMN IF EXISTS "is_automatic";
+
+DROP TABLE IF EXISTS grading_rules;
+
+-- ## Types
+DROP TYPE IF EXISTS GRADING_RULE_CONDITION;
@@ -0,0 +1,22 @@
+-- ## Types
+CREATE TYPE GRADING_RULE_CONDITION AS ENUM ('passing', 'failing', 'min_tests_ratio');
+
+-- ## Tables
+-- Rules to select a criteria of a rubric objective from the result of the latest submission to a test block
+CREATE TABLE IF NOT EXISTS grading_rules (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "test_block_id" UUID NOT NULL REFERENCES test_blocks(id) ON DELETE CASCADE,
+  "objective_id" UUID NOT NULL REFERENCES objectives(id) ON DELETE CASCADE,
+  "criteria_id" UUID NOT NULL REFERENCES criteria(id) ON DELETE CASCADE,
+  "condition" GRADING_RULE_CONDITION NOT NULL,
+  "min_tests_ratio" DECIMAL(5, 4) NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Criteria selected by the automatic grading can be overridden by the teacher
+ALTER TABLE grade_has_criteria ADD COLUMN IF NOT EXISTS "is_automatic" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_grading_rules_test_block ON grading_rules(test_block_id);
+
+CREATE INDEX IF NOT EXISTS idx_grading_rules_objective ON grading_rules(objective_id);
@@ -0,0 +1,12 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_plagiarism_report_pairs_report;
+
+DROP INDEX IF EXISTS idx_plagiarism_reports_test_block;
+
+-- ## Tables
+DROP TABLE IF EXISTS plagiarism_report_pairs;
+
+DROP TABLE IF EXISTS plagiarism_reports;
+
+-- ## Types
+DROP TYPE IF EXISTS PLAGIARISM_REPORT_STATUS;
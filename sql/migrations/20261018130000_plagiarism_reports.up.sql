@@ -0,0 +1,31 @@
+-- ## Types
+CREATE TYPE PLAGIARISM_REPORT_STATUS AS ENUM ('pending', 'running', 'ready', 'failed');
+
+-- ## Tables
+-- Reports comparing the latest submissions of the students to a test block against each other
+CREATE TABLE IF NOT EXISTS plagiarism_reports (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "test_block_id" UUID NOT NULL REFERENCES test_blocks(id) ON DELETE CASCADE,
+  "teacher_id" UUID NOT NULL REFERENCES users(id),
+  "status" PLAGIARISM_REPORT_STATUS NOT NULL DEFAULT 'pending',
+  "min_similarity_percentage" DECIMAL(5, 2) NOT NULL,
+  "analyzed_submissions" INTEGER NOT NULL DEFAULT 0,
+  "error_message" TEXT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "finished_at" TIMESTAMP NULL
+);
+
+-- Pairs of submissions whose similarity is above the threshold of the report
+CREATE TABLE IF NOT EXISTS plagiarism_report_pairs (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "report_id" UUID NOT NULL REFERENCES plagiarism_reports(id) ON DELETE CASCADE,
+  "first_submission_id" UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+  "second_submission_id" UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+  "similarity_percentage" DECIMAL(5, 2) NOT NULL,
+  "matching_regions" JSONB NOT NULL DEFAULT '[]'
+);
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_plagiarism_reports_test_block ON plagiarism_reports(test_block_id);
+
+CREATE INDEX IF NOT EXISTS idx_plagiarism_report_pairs_report ON plagiarism_report_pairs(report_id, similarity_percentage DESC);
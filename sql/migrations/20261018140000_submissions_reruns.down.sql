@@ -0,0 +1,10 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_submissions_reruns_test_block;
+
+-- ## Tables
+DROP TABLE IF EXISTS submissions_rerun_items;
+
+DROP TABLE IF EXISTS submissions_reruns;
+
+-- ## Types
+DROP TYPE IF EXISTS SUBMISSIONS_RERUN_TRIGGER;
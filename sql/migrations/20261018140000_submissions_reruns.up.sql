@@ -0,0 +1,23 @@
+-- ## Types
+CREATE TYPE SUBMISSIONS_RERUN_TRIGGER AS ENUM ('manual', 'test_archive_update');
+
+-- ## Tables
+-- Batches of submissions requeued to be evaluated against the current tests of a test block
+CREATE TABLE IF NOT EXISTS submissions_reruns (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "test_block_id" UUID NOT NULL REFERENCES test_blocks(id) ON DELETE CASCADE,
+  "teacher_id" UUID NOT NULL REFERENCES users(id),
+  "trigger" SUBMISSIONS_RERUN_TRIGGER NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Submissions included in each batch
+CREATE TABLE IF NOT EXISTS submissions_rerun_items (
+  "rerun_id" UUID NOT NULL REFERENCES submissions_reruns(id) ON DELETE CASCADE,
+  "submission_id" UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+  "is_queued" BOOLEAN NOT NULL DEFAULT TRUE,
+  PRIMARY KEY ("rerun_id", "submission_id")
+);
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_submissions_reruns_test_block ON submissions_reruns(test_block_id, created_at DESC);
@@ -0,0 +1,67 @@
+-- ## Triggers
+DROP TRIGGER IF EXISTS set_submission_status_updated_at ON submissions;
+
+DROP FUNCTION IF EXISTS update_submission_status_updated_at;
+
+-- ## Indexes
+DROP INDEX IF EXISTS idx_submissions_unfinished;
+
+-- ## Views
+DROP VIEW IF EXISTS students_progress_view;
+
+DROP VIEW IF EXISTS latest_submissions;
+
+-- ## Types
+-- Values can not be removed from an enum, so the type is created again without the `timed_out` status
+UPDATE submissions SET status = 'ready', passing = FALSE WHERE status = 'timed_out';
+
+ALTER TYPE SUBMISSION_STATUS RENAME TO SUBMISSION_STATUS_OLD;
+
+CREATE TYPE SUBMISSION_STATUS AS ENUM ('pending', 'running', 'ready');
+
+ALTER TABLE submissions
+  ALTER COLUMN status DROP DEFAULT,
+  ALTER COLUMN status TYPE SUBMISSION_STATUS USING status::TEXT::SUBMISSION_STATUS,
+  ALTER COLUMN status SET DEFAULT 'pending';
+
+DROP TYPE IF EXISTS SUBMISSION_STATUS_OLD;
+
+-- ## Tables
+ALTER TABLE submissions
+  DROP COLUMN IF EXISTS "status_updated_at",
+  DROP COLUMN IF EXISTS "requeue_attempts";
+
+-- ## Views
+CREATE OR REPLACE VIEW latest_submissions AS
+SELECT DISTINCT ON (submissions.test_block_id, submissions.student_id)
+  submissions.*
+FROM
+  submissions
+ORDER BY
+  submissions.test_block_id, submissions.student_id, submissions.submitted_at DESC;
+
+CREATE OR REPLACE VIEW students_progress_view AS
+SELECT
+  users.id AS student_id,
+  users.full_name as student_full_name,
+  test_blocks.laboratory_id,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'pending'
+  ) AS pending_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'running'
+  ) AS running_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'ready' AND latest_submissions.passing = FALSE
+  ) AS failing_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'ready' AND latest_submissions.passing = TRUE
+  ) AS success_submissions
+FROM
+  latest_submissions
+JOIN
+  users ON latest_submissions.student_id = users.id
+JOIN
+  test_blocks ON latest_submissions.test_block_id = test_blocks.id
+GROUP BY
+  users.id, users.full_name, test_blocks.laboratory_id;
@@ -0,0 +1,73 @@
+-- ## Types
+-- Submissions that were not evaluated after being requeued the maximum number of times
+ALTER TYPE SUBMISSION_STATUS ADD VALUE IF NOT EXISTS 'timed_out';
+
+-- ## Tables
+ALTER TABLE submissions
+  ADD COLUMN IF NOT EXISTS "status_updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ADD COLUMN IF NOT EXISTS "requeue_attempts" INTEGER NOT NULL DEFAULT 0;
+
+UPDATE submissions SET status_updated_at = submitted_at;
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_submissions_unfinished ON submissions(status_updated_at)
+WHERE status IN ('pending', 'running');
+
+-- ## Triggers
+--- ### Update status_updated_at on submissions
+-- The runners update the status of the submissions directly, so the date is set by the database
+CREATE
+OR REPLACE FUNCTION update_submission_status_updated_at()
+RETURNS TRIGGER
+LANGUAGE PLPGSQL
+AS $$
+BEGIN
+  IF NEW.status IS DISTINCT FROM OLD.status THEN
+    NEW.status_updated_at := CURRENT_TIMESTAMP;
+  END IF;
+
+  RETURN NEW;
+END $$
+;
+
+CREATE
+OR REPLACE TRIGGER set_submission_status_updated_at BEFORE
+UPDATE
+  ON submissions FOR EACH ROW EXECUTE PROCEDURE update_submission_status_updated_at();
+
+-- ## Views
+--- ### Latest submission of each student in each test block (Recreated to include the new columns)
+CREATE OR REPLACE VIEW latest_submissions AS
+SELECT DISTINCT ON (submissions.test_block_id, submissions.student_id)
+  submissions.*
+FROM
+  submissions
+ORDER BY
+  submissions.test_block_id, submissions.student_id, submissions.submitted_at DESC;
+
+--- ### Students progress (Timed out submissions are counted as failing)
+CREATE OR REPLACE VIEW students_progress_view AS
+SELECT
+  users.id AS student_id,
+  users.full_name as student_full_name,
+  test_blocks.laboratory_id,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'pending'
+  ) AS pending_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'running'
+  ) AS running_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status NOT IN ('pending', 'running') AND latest_submissions.passing = FALSE
+  ) AS failing_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'ready' AND latest_submissions.passing = TRUE
+  ) AS success_submissions
+FROM
+  latest_submissions
+JOIN
+  users ON latest_submissions.student_id = users.id
+JOIN
+  test_blocks ON latest_submissions.test_block_id = test_blocks.id
+GROUP BY
+  users.id, users.full_name, test_blocks.laboratory_id;
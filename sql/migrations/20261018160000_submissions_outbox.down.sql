@@ -0,0 +1,5 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_submissions_outbox_unsent;
+
+-- ## Tables
+DROP TABLE IF EXISTS submissions_outbox;
@@ -0,0 +1,17 @@
+-- ## Tables
+-- Submission works waiting to be published to the submissions queue. The messages are saved in the same
+-- transaction as the submission, so a submission is never left pending without its work being queued
+CREATE TABLE IF NOT EXISTS submissions_outbox (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "submission_id" UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+  "payload" JSONB NOT NULL,
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "last_error" TEXT NULL,
+  "locked_until" TIMESTAMP NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "sent_at" TIMESTAMP NULL
+);
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_submissions_outbox_unsent ON submissions_outbox(created_at)
+WHERE sent_at IS NULL;
@@ -0,0 +1,5 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_submissions_dead_letters_dead_lettered_at;
+
+-- ## Tables
+DROP TABLE IF EXISTS submissions_dead_letters;
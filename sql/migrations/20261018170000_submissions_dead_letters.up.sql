@@ -0,0 +1,14 @@
+-- ## Tables
+-- Submission works rejected by the runners or expired in the submissions queue. The messages are kept
+-- so the administrators can inspect and replay them
+CREATE TABLE IF NOT EXISTS submissions_dead_letters (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "submission_id" UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+  "payload" JSONB NOT NULL,
+  "reason" VARCHAR(32) NOT NULL,
+  "dead_lettered_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "replayed_at" TIMESTAMP NULL
+);
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_submissions_dead_letters_dead_lettered_at ON submissions_dead_letters(dead_lettered_at);
@@ -0,0 +1,7 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_archives_created_at;
+
+-- ## Columns
+ALTER TABLE archives
+  DROP COLUMN IF EXISTS "created_at",
+  DROP COLUMN IF EXISTS "archive_type";
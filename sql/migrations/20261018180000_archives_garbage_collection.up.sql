@@ -0,0 +1,18 @@
+-- ## Columns
+-- The type of the archive is needed to delete its file once it is no longer referenced, and the creation
+-- date prevents collecting an archive whose references are still being saved
+ALTER TABLE archives
+  ADD COLUMN IF NOT EXISTS "archive_type" VARCHAR(16) NULL,
+  ADD COLUMN IF NOT EXISTS "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE archives SET archive_type = 'template'
+WHERE id IN ( SELECT template_archive_id FROM languages );
+
+UPDATE archives SET archive_type = 'test'
+WHERE id IN ( SELECT test_archive_id FROM test_blocks );
+
+UPDATE archives SET archive_type = 'submission'
+WHERE id IN ( SELECT archive_id FROM submissions );
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_archives_created_at ON archives(created_at);
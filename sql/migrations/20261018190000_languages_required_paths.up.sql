@@ -0,0 +1,7 @@
+-- ## Columns
+-- Paths the projects uploaded for a language must contain (e.g. `src/main/java`)
+ALTER TABLE languages
+  ADD COLUMN IF NOT EXISTS "required_paths" TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE languages SET required_paths = '{src/main/java}'
+WHERE name = 'Java JDK 17';
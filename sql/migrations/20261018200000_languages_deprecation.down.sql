@@ -0,0 +1,3 @@
+-- ## Columns
+ALTER TABLE languages
+  DROP COLUMN IF EXISTS "deprecated_at";
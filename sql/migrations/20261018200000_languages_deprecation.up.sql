@@ -0,0 +1,4 @@
+-- ## Columns
+-- Deprecated languages can not be chosen for new test blocks, but the existing ones keep working
+ALTER TABLE languages
+  ADD COLUMN IF NOT EXISTS "deprecated_at" TIMESTAMP NULL;
@@ -0,0 +1,29 @@
+-- ## Views
+-- Columns can not be removed with `CREATE OR REPLACE`, so the view is created again
+DROP VIEW IF EXISTS submissions_work_metadata;
+
+CREATE OR REPLACE VIEW submissions_work_metadata AS
+SELECT
+  submissions.id AS submission_id,
+  language_archive.file_id AS language_file_id,
+  test_archive.file_id AS test_file_id,
+  submission_archive.file_id AS submission_file_id
+FROM submissions 
+  INNER JOIN test_blocks ON submissions.test_block_id = test_blocks.id
+  INNER JOIN languages ON test_blocks.language_id = languages.id
+  INNER JOIN archives AS language_archive ON languages.template_archive_id = language_archive.id
+  INNER JOIN archives AS test_archive ON test_blocks.test_archive_id = test_archive.id
+  INNER JOIN archives AS submission_archive ON submissions.archive_id = submission_archive.id;
+
+-- ## Columns
+ALTER TABLE test_blocks
+  DROP COLUMN IF EXISTS "cpu_time_limit_ms",
+  DROP COLUMN IF EXISTS "wall_time_limit_ms",
+  DROP COLUMN IF EXISTS "memory_limit_mb",
+  DROP COLUMN IF EXISTS "output_size_limit_kb";
+
+ALTER TABLE languages
+  DROP COLUMN IF EXISTS "cpu_time_limit_ms",
+  DROP COLUMN IF EXISTS "wall_time_limit_ms",
+  DROP COLUMN IF EXISTS "memory_limit_mb",
+  DROP COLUMN IF EXISTS "output_size_limit_kb";
@@ -0,0 +1,33 @@
+-- ## Columns
+-- Default limits the runners apply to the submissions of each language
+ALTER TABLE languages
+  ADD COLUMN IF NOT EXISTS "cpu_time_limit_ms" INTEGER NOT NULL DEFAULT 10000,
+  ADD COLUMN IF NOT EXISTS "wall_time_limit_ms" INTEGER NOT NULL DEFAULT 20000,
+  ADD COLUMN IF NOT EXISTS "memory_limit_mb" INTEGER NOT NULL DEFAULT 512,
+  ADD COLUMN IF NOT EXISTS "output_size_limit_kb" INTEGER NOT NULL DEFAULT 1024;
+
+-- Limits overridden by a test block, the ones of its language are applied when NULL
+ALTER TABLE test_blocks
+  ADD COLUMN IF NOT EXISTS "cpu_time_limit_ms" INTEGER NULL,
+  ADD COLUMN IF NOT EXISTS "wall_time_limit_ms" INTEGER NULL,
+  ADD COLUMN IF NOT EXISTS "memory_limit_mb" INTEGER NULL,
+  ADD COLUMN IF NOT EXISTS "output_size_limit_kb" INTEGER NULL;
+
+-- ## Views
+--- ### Submissions work metadata (Recreated to include the effective limits of the test block)
+CREATE OR REPLACE VIEW submissions_work_metadata AS
+SELECT
+  submissions.id AS submission_id,
+  language_archive.file_id AS language_file_id,
+  test_archive.file_id AS test_file_id,
+  submission_archive.file_id AS submission_file_id,
+  COALESCE(test_blocks.cpu_time_limit_ms, languages.cpu_time_limit_ms) AS cpu_time_limit_ms,
+  COALESCE(test_blocks.wall_time_limit_ms, languages.wall_time_limit_ms) AS wall_time_limit_ms,
+  COALESCE(test_blocks.memory_limit_mb, languages.memory_limit_mb) AS memory_limit_mb,
+  COALESCE(test_blocks.output_size_limit_kb, languages.output_size_limit_kb) AS output_size_limit_kb
+FROM submissions 
+  INNER JOIN test_blocks ON submissions.test_block_id = test_blocks.id
+  INNER JOIN languages ON test_blocks.language_id = languages.id
+  INNER JOIN archives AS language_archive ON languages.template_archive_id = language_archive.id
+  INNER JOIN archives AS test_archive ON test_blocks.test_archive_id = test_archive.id
+  INNER JOIN archives AS submission_archive ON submissions.archive_id = submission_archive.id;
@@ -0,0 +1,5 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_laboratories_date_overrides_student_id;
+
+-- ## Tables
+DROP TABLE IF EXISTS laboratories_date_overrides;
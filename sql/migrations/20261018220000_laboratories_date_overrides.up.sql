@@ -0,0 +1,15 @@
+-- ## Tables
+-- Opening and due dates of a laboratory granted to a single student (e.g. for medical excuses). The dates
+-- of the laboratory are applied when NULL
+CREATE TABLE IF NOT EXISTS laboratories_date_overrides (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "laboratory_id" UUID NOT NULL REFERENCES laboratories(id) ON DELETE CASCADE,
+  "student_id" UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  "opening_date" TIMESTAMP WITH TIME ZONE NULL,
+  "due_date" TIMESTAMP WITH TIME ZONE NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE ("laboratory_id", "student_id")
+);
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_laboratories_date_overrides_student_id ON laboratories_date_overrides(student_id);
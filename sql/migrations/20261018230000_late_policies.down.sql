@@ -0,0 +1,73 @@
+-- ## Views
+DROP VIEW IF EXISTS summarized_grades;
+
+DROP VIEW IF EXISTS students_progress_view;
+
+DROP VIEW IF EXISTS latest_submissions;
+
+-- ## Tables
+ALTER TABLE submissions DROP COLUMN IF EXISTS "late_minutes";
+
+ALTER TABLE laboratories
+  DROP COLUMN IF EXISTS "late_policy",
+  DROP COLUMN IF EXISTS "late_window_minutes",
+  DROP COLUMN IF EXISTS "late_penalty_percentage",
+  DROP COLUMN IF EXISTS "late_penalty_unit";
+
+-- ## Types
+DROP TYPE IF EXISTS LATE_PENALTY_UNIT;
+
+DROP TYPE IF EXISTS LATE_POLICY;
+
+-- ## Views
+CREATE OR REPLACE VIEW latest_submissions AS
+SELECT DISTINCT ON (submissions.test_block_id, submissions.student_id)
+  submissions.*
+FROM
+  submissions
+ORDER BY
+  submissions.test_block_id, submissions.student_id, submissions.submitted_at DESC;
+
+CREATE OR REPLACE VIEW students_progress_view AS
+SELECT
+  users.id AS student_id,
+  users.full_name as student_full_name,
+  test_blocks.laboratory_id,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'pending'
+  ) AS pending_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'running'
+  ) AS running_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status NOT IN ('pending', 'running') AND latest_submissions.passing = FALSE
+  ) AS failing_submissions,
+  COUNT(latest_submissions.id) FILTER (
+	  WHERE latest_submissions.status = 'ready' AND latest_submissions.passing = TRUE
+  ) AS success_submissions
+FROM
+  latest_submissions
+JOIN
+  users ON latest_submissions.student_id = users.id
+JOIN
+  test_blocks ON latest_submissions.test_block_id = test_blocks.id
+GROUP BY
+  users.id, users.full_name, test_blocks.laboratory_id;
+
+CREATE
+OR REPLACE VIEW summarized_grades AS
+SELECT
+  grades.id AS grade_id,
+  grades.student_id,
+  students.full_name AS student_full_name,
+  grades.laboratory_id,
+  grades.rubric_id,
+  SUM(criteria.weight) AS total_criteria_weight,
+  grades.comment
+FROM
+  grades
+  INNER JOIN users AS students ON grades.student_id = students.id
+  INNER JOIN grade_has_criteria ON grades.id = grade_has_criteria.grade_id
+  INNER JOIN criteria ON grade_has_criteria.criteria_id = criteria.id
+GROUP BY
+  grades.id, students.full_name;
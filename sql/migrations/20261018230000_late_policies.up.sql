@@ -0,0 +1,57 @@
+-- ## Types
+CREATE TYPE LATE_POLICY AS ENUM ('none', 'grace_period', 'late_window');
+
+CREATE TYPE LATE_PENALTY_UNIT AS ENUM ('hour', 'day');
+
+-- ## Tables
+-- Submissions sent after the due date are rejected, accepted during a grace period or accepted
+-- during a late window with a penalty for each started hour or day
+ALTER TABLE laboratories
+  ADD COLUMN IF NOT EXISTS "late_policy" LATE_POLICY NOT NULL DEFAULT 'none',
+  ADD COLUMN IF NOT EXISTS "late_window_minutes" INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS "late_penalty_percentage" DECIMAL(5, 2) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS "late_penalty_unit" LATE_PENALTY_UNIT NULL;
+
+-- Minutes the submission was sent after the due date of the student
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS "late_minutes" INTEGER NOT NULL DEFAULT 0;
+
+-- ## Views
+--- ### Latest submission of each student in each test block (Recreated to include the new columns)
+CREATE OR REPLACE VIEW latest_submissions AS
+SELECT DISTINCT ON (submissions.test_block_id, submissions.student_id)
+  submissions.*
+FROM
+  submissions
+ORDER BY
+  submissions.test_block_id, submissions.student_id, submissions.submitted_at DESC;
+
+--- ### Summarized grades (Including the lateness of the latest submissions of the student in the laboratory)
+CREATE
+OR REPLACE VIEW summarized_grades AS
+SELECT
+  grades.id AS grade_id,
+  grades.student_id,
+  students.full_name AS student_full_name,
+  grades.laboratory_id,
+  grades.rubric_id,
+  SUM(criteria.weight) AS total_criteria_weight,
+  grades.comment,
+  COALESCE(MAX(lateness.late_minutes), 0) AS late_minutes
+FROM
+  grades
+  INNER JOIN users AS students ON grades.student_id = students.id
+  INNER JOIN grade_has_criteria ON grades.id = grade_has_criteria.grade_id
+  INNER JOIN criteria ON grade_has_criteria.criteria_id = criteria.id
+  LEFT JOIN (
+    SELECT
+      latest_submissions.student_id,
+      test_blocks.laboratory_id,
+      MAX(latest_submissions.late_minutes) AS late_minutes
+    FROM
+      latest_submissions
+      INNER JOIN test_blocks ON latest_submissions.test_block_id = test_blocks.id
+    GROUP BY
+      latest_submissions.student_id, test_blocks.laboratory_id
+  ) AS lateness ON lateness.student_id = grades.student_id AND lateness.laboratory_id = grades.laboratory_id
+GROUP BY
+  grades.id, students.full_name;
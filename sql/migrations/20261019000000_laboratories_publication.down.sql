@@ -0,0 +1,3 @@
+-- ## Columns
+ALTER TABLE laboratories
+  DROP COLUMN IF EXISTS "is_published";
@@ -0,0 +1,8 @@
+-- ## Columns
+-- Draft laboratories are hidden from the students until the teacher publishes them. The existing
+-- laboratories were already visible, so they are published
+ALTER TABLE laboratories
+  ADD COLUMN IF NOT EXISTS "is_published" BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE laboratories
+  ALTER COLUMN "is_published" SET DEFAULT FALSE;
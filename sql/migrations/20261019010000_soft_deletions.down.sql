@@ -0,0 +1,70 @@
+-- ## Views
+CREATE OR REPLACE VIEW courses_with_color AS
+SELECT
+  courses.id,
+  courses.teacher_id,
+  courses.name,
+  colors.hexadecimal AS color
+FROM
+  courses
+  INNER JOIN colors ON courses.color_id = colors.id;
+
+CREATE OR REPLACE VIEW courses_has_users_view AS
+SELECT
+  courses_has_users.course_id,
+  courses.name AS course_name,
+  courses.teacher_id AS course_teacher_id,
+  colors.hexadecimal AS course_color,
+  courses_has_users.user_id,
+  users.full_name AS user_full_name,
+  users.email AS user_email,
+  users.role AS user_role,
+  users.institutional_id AS user_institutional_id,
+  courses_has_users.is_class_hidden,
+  courses_has_users.is_user_active
+FROM
+  courses_has_users
+  INNER JOIN users ON courses_has_users.user_id = users.id
+  INNER JOIN courses ON courses_has_users.course_id = courses.id
+  INNER JOIN colors ON courses.color_id = colors.id;
+
+-- ## Indexes
+DROP INDEX IF EXISTS idx_laboratories_deleted_at;
+
+DROP INDEX IF EXISTS idx_courses_deleted_at;
+
+-- ## Foreign keys
+ALTER TABLE grade_has_criteria
+  DROP CONSTRAINT IF EXISTS grade_has_criteria_grade_id_fkey,
+  ADD CONSTRAINT grade_has_criteria_grade_id_fkey FOREIGN KEY ("grade_id") REFERENCES grades(id);
+
+ALTER TABLE grades
+  DROP CONSTRAINT IF EXISTS grades_laboratory_id_fkey,
+  ADD CONSTRAINT grades_laboratory_id_fkey FOREIGN KEY ("laboratory_id") REFERENCES laboratories(id);
+
+ALTER TABLE test_blocks
+  DROP CONSTRAINT IF EXISTS test_blocks_laboratory_id_fkey,
+  ADD CONSTRAINT test_blocks_laboratory_id_fkey FOREIGN KEY ("laboratory_id") REFERENCES laboratories(id);
+
+ALTER TABLE markdown_blocks
+  DROP CONSTRAINT IF EXISTS markdown_blocks_laboratory_id_fkey,
+  ADD CONSTRAINT markdown_blocks_laboratory_id_fkey FOREIGN KEY ("laboratory_id") REFERENCES laboratories(id);
+
+ALTER TABLE blocks_index
+  DROP CONSTRAINT IF EXISTS blocks_index_laboratory_id_fkey,
+  ADD CONSTRAINT blocks_index_laboratory_id_fkey FOREIGN KEY ("laboratory_id") REFERENCES laboratories(id);
+
+ALTER TABLE laboratories
+  DROP CONSTRAINT IF EXISTS laboratories_course_id_fkey,
+  ADD CONSTRAINT laboratories_course_id_fkey FOREIGN KEY ("course_id") REFERENCES courses(id);
+
+ALTER TABLE courses_has_users
+  DROP CONSTRAINT IF EXISTS courses_has_users_course_id_fkey,
+  ADD CONSTRAINT courses_has_users_course_id_fkey FOREIGN KEY ("course_id") REFERENCES courses(id);
+
+-- ## Columns
+ALTER TABLE laboratories
+  DROP COLUMN IF EXISTS "deleted_at";
+
+ALTER TABLE courses
+  DROP COLUMN IF EXISTS "deleted_at";
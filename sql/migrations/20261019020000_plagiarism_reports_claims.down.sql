@@ -0,0 +1,8 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_plagiarism_reports_unfinished;
+
+-- ## Columns
+ALTER TABLE plagiarism_reports
+  DROP COLUMN IF EXISTS "skipped_submissions",
+  DROP COLUMN IF EXISTS "locked_until",
+  DROP COLUMN IF EXISTS "attempts";
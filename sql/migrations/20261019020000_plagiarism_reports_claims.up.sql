@@ -0,0 +1,11 @@
+-- ## Columns
+-- The reports are claimed from the table by the gateway instances, so the pending reports survive a restart.
+-- A report whose claim expires while it is running is claimed again, until the maximum number of attempts
+ALTER TABLE plagiarism_reports
+  ADD COLUMN IF NOT EXISTS "attempts" INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS "locked_until" TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS "skipped_submissions" INTEGER NOT NULL DEFAULT 0;
+
+-- ## Indexes
+CREATE INDEX IF NOT EXISTS idx_plagiarism_reports_unfinished ON plagiarism_reports(created_at)
+WHERE status IN ('pending', 'running');
@@ -0,0 +1,2 @@
+-- ## Indexes
+DROP INDEX IF EXISTS idx_submissions_dead_letters_open;
@@ -0,0 +1,4 @@
+-- ## Indexes
+-- The watchdog does not requeue the submissions whose work was dead-lettered and not replayed yet
+CREATE INDEX IF NOT EXISTS idx_submissions_dead_letters_open ON submissions_dead_letters(submission_id)
+WHERE replayed_at IS NULL;
@@ -2,19 +2,25 @@ package application
 
 import (
 	"errors"
+	"log"
 
 	"github.com/UPB-Code-Labs/main-api/src/blocks/domain/definitions"
 	"github.com/UPB-Code-Labs/main-api/src/blocks/domain/dtos"
 	blocksErrors "github.com/UPB-Code-Labs/main-api/src/blocks/domain/errors"
+	laboratoriesEntities "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
 	languagesDefinitions "github.com/UPB-Code-Labs/main-api/src/languages/domain/definitions"
+	languagesErrors "github.com/UPB-Code-Labs/main-api/src/languages/domain/errors"
 	staticFilesDefinitions "github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
 	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	submissionsDTOs "github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
 )
 
 type BlocksUseCases struct {
 	BlocksRepository      definitions.BlockRepository
 	LanguagesRepository   languagesDefinitions.LanguagesRepository
 	StaticFilesRepository staticFilesDefinitions.StaticFilesRepository
+	SubmissionsRerunner   definitions.SubmissionsRerunner
+	ArchivesValidator     staticFilesDefinitions.ArchivesValidator
 }
 
 func (useCases *BlocksUseCases) UpdateMarkdownBlockContent(dto dtos.UpdateMarkdownBlockContentDTO) (err error) {
@@ -44,13 +50,34 @@ func (useCases *BlocksUseCases) UpdateTestBlock(dto dtos.UpdateTestBlockDTO) (er
 	}
 
 	// Validate the programming language exists
-	_, err = useCases.LanguagesRepository.GetByUUID(dto.LanguageUUID)
+	language, err := useCases.LanguagesRepository.GetByUUID(dto.LanguageUUID)
 	if err != nil {
 		return err
 	}
 
+	// Blocks can keep their deprecated language, but they can not be moved to one
+	if language.IsDeprecated {
+		testBlock, err := useCases.BlocksRepository.GetTestBlockByUUID(dto.BlockUUID)
+		if err != nil {
+			return err
+		}
+
+		if testBlock.LanguageUUID != dto.LanguageUUID {
+			return &languagesErrors.LangIsDeprecatedError{}
+		}
+	}
+
 	// Overwrite the block's tests archive if the teacher uploaded a new one
 	if dto.NewTestArchive != nil {
+		// Check the content of the archive
+		err = useCases.ArchivesValidator.ValidateArchive(&staticFilesDTOs.ValidateArchiveDTO{
+			File:          dto.NewTestArchive,
+			RequiredPaths: language.RequiredPaths,
+		})
+		if err != nil {
+			return err
+		}
+
 		// Get the UUID of the block's tests archive
 		uuid, err := useCases.BlocksRepository.GetTestArchiveUUIDFromTestBlockUUID(dto.BlockUUID)
 		if err != nil {
@@ -71,7 +98,29 @@ func (useCases *BlocksUseCases) UpdateTestBlock(dto dtos.UpdateTestBlockDTO) (er
 	}
 
 	// Update the block
-	return useCases.BlocksRepository.UpdateTestBlock(&dto)
+	err = useCases.BlocksRepository.UpdateTestBlock(&dto)
+	if err != nil {
+		return err
+	}
+
+	// Evaluate the existing submissions against the new tests. The block was already updated, so a failure
+	// does not fail the update and the teacher can rerun the submissions later
+	if dto.NewTestArchive != nil && dto.RerunSubmissions {
+		_, err = useCases.SubmissionsRerunner.RerunTestBlockSubmissions(&submissionsDTOs.RerunTestBlockSubmissionsDTO{
+			TeacherUUID:   dto.TeacherUUID,
+			TestBlockUUID: dto.BlockUUID,
+			Trigger:       "test_archive_update",
+		})
+		if err != nil {
+			log.Printf(
+				"[Blocks]: Unable to rerun the submissions of the updated test block %s: %s",
+				dto.BlockUUID,
+				err.Error(),
+			)
+		}
+	}
+
+	return nil
 }
 
 func (useCases *BlocksUseCases) DeleteMarkdownBlock(dto dtos.DeleteBlockDTO) (err error) {
@@ -194,8 +243,22 @@ func (useCases *BlocksUseCases) SwapBlocks(dto dtos.SwapBlocksDTO) (err error) {
 	return useCases.BlocksRepository.SwapBlocks(dto.FirstBlockUUID, dto.SecondBlockUUID)
 }
 
+// GetTestBlock returns a test block along with the execution limits applied to its submissions
+func (useCases *BlocksUseCases) GetTestBlock(dto *dtos.GetTestBlockDTO) (*laboratoriesEntities.TestBlock, error) {
+	// Validate the teacher is the owner of the block
+	ownsBlock, err := useCases.BlocksRepository.DoesTeacherOwnsTestBlock(dto.TeacherUUID, dto.BlockUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !ownsBlock {
+		return nil, blocksErrors.TeacherDoesNotOwnBlock{}
+	}
+
+	return useCases.BlocksRepository.GetTestBlockByUUID(dto.BlockUUID)
+}
+
 // GetTestBlockTestsArchive returns the bytes of the `.zip` archive containing the tests of a test block
-func (useCases *BlocksUseCases) GetTestBlockTestsArchive(dto *dtos.GetBlockTestsArchiveDTO) (archive []byte, err error) {
+func (useCases *BlocksUseCases) GetTestBlockTestsArchive(dto *dtos.GetBlockTestsArchiveDTO) (archive *staticFilesDTOs.StaticFileStreamDTO, err error) {
 	// Validate the teacher is the owner of the block
 	ownsBlock, err := useCases.BlocksRepository.DoesTeacherOwnsTestBlock(dto.TeacherUUID, dto.BlockUUID)
 	if err != nil {
@@ -212,7 +275,7 @@ func (useCases *BlocksUseCases) GetTestBlockTestsArchive(dto *dtos.GetBlockTests
 	}
 
 	// Get the archive from the microservice
-	return useCases.StaticFilesRepository.GetArchiveBytes(&staticFilesDTOs.StaticFileArchiveDTO{
+	return useCases.StaticFilesRepository.GetArchiveStream(&staticFilesDTOs.StaticFileArchiveDTO{
 		FileUUID: uuid,
 		FileType: "test",
 	})
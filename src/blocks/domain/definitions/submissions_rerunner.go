@@ -0,0 +1,8 @@
+package definitions
+
+import submissionsDTOs "github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+
+type SubmissionsRerunner interface {
+	// Requeue the latest submissions of a test block so they are evaluated against its current tests
+	RerunTestBlockSubmissions(dto *submissionsDTOs.RerunTestBlockSubmissionsDTO) (rerun *submissionsDTOs.SubmissionsRerunDTO, err error)
+}
@@ -1,6 +1,10 @@
 package dtos
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+)
 
 type UpdateMarkdownBlockContentDTO struct {
 	TeacherUUID string
@@ -14,6 +18,14 @@ type UpdateTestBlockDTO struct {
 	LanguageUUID   string
 	Name           string
 	NewTestArchive *multipart.File
+
+	// Limits that replace the defaults of the language, the ones that are not sent are kept. When
+	// `ResetExecutionLimits` is set, the limits that are not sent are not replaced anymore
+	ExecutionLimits      *sharedEntities.ExecutionLimitsOverrides
+	ResetExecutionLimits bool
+
+	// Requeue the latest submissions of the block when a new test archive is uploaded
+	RerunSubmissions bool
 }
 
 type DeleteBlockDTO struct {
@@ -27,6 +39,11 @@ type SwapBlocksDTO struct {
 	SecondBlockUUID string
 }
 
+type GetTestBlockDTO struct {
+	TeacherUUID string
+	BlockUUID   string
+}
+
 type GetBlockTestsArchiveDTO struct {
 	TeacherUUID string
 	BlockUUID   string
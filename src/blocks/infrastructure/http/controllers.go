@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/UPB-Code-Labs/main-api/src/blocks/application"
 	"github.com/UPB-Code-Labs/main-api/src/blocks/domain/dtos"
@@ -66,10 +67,24 @@ func (controller *BlocksController) HandleUpdateTestBlock(c *gin.Context) {
 	// Validate the request struct
 	languageUUID := c.PostForm("language_uuid")
 	blockName := c.PostForm("block_name")
+	rerunSubmissions := c.PostForm("rerun_submissions")
+	resetExecutionLimits := c.PostForm("reset_execution_limits")
+
+	executionLimits, err := sharedInfrastructure.GetExecutionLimitsRequestFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
 
 	req := requests.UpdateTestBlockRequest{
-		LanguageUUID: languageUUID,
-		Name:         blockName,
+		LanguageUUID:         languageUUID,
+		Name:                 blockName,
+		RerunSubmissions:     rerunSubmissions,
+		ExecutionLimits:      executionLimits,
+		ResetExecutionLimits: resetExecutionLimits,
 	}
 
 	if err := sharedInfrastructure.GetValidator().Struct(req); err != nil {
@@ -82,11 +97,14 @@ func (controller *BlocksController) HandleUpdateTestBlock(c *gin.Context) {
 
 	// Create the DTO
 	dto := dtos.UpdateTestBlockDTO{
-		TeacherUUID:  teacherUUID,
-		BlockUUID:    blockUUID,
-		LanguageUUID: languageUUID,
-		Name:         blockName,
+		TeacherUUID:     teacherUUID,
+		BlockUUID:       blockUUID,
+		LanguageUUID:    languageUUID,
+		Name:            blockName,
+		ExecutionLimits: executionLimits.ToOverrides(),
 	}
+	dto.RerunSubmissions, _ = strconv.ParseBool(rerunSubmissions)
+	dto.ResetExecutionLimits, _ = strconv.ParseBool(resetExecutionLimits)
 
 	// Validate the test archive (if any)
 	multipartHeader, err := c.FormFile("test_archive")
@@ -214,6 +232,31 @@ func (controller *BlocksController) HandleSwapBlocks(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// HandleGetTestBlock controller to handle the request of getting a test block along with its execution limits
+func (controller *BlocksController) HandleGetTestBlock(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	blockUUID := c.Param("block_uuid")
+
+	// Validate the block UUID
+	if err := sharedInfrastructure.GetValidator().Var(blockUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Block UUID is not valid",
+		})
+		return
+	}
+
+	testBlock, err := controller.UseCases.GetTestBlock(&dtos.GetTestBlockDTO{
+		TeacherUUID: teacherUUID,
+		BlockUUID:   blockUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, testBlock)
+}
+
 // HandleGetTestBlockTestsArchive controller to handle the request of downloading the `.zip` archive
 // containing the tests of a test block
 func (controller *BlocksController) HandleGetTestBlockTestsArchive(c *gin.Context) {
@@ -239,5 +282,5 @@ func (controller *BlocksController) HandleGetTestBlockTestsArchive(c *gin.Contex
 		return
 	}
 
-	c.Data(http.StatusOK, "application/zip", testsArchive)
+	sharedInfrastructure.SendArchive(c, testsArchive.Content, testsArchive.ContentLength, testsArchive.ContentDisposition)
 }
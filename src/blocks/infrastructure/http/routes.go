@@ -6,16 +6,21 @@ import (
 	languagesImplementations "github.com/UPB-Code-Labs/main-api/src/languages/infrastructure/implementations"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 	staticFilesImplementations "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
+	submissionsImplementations "github.com/UPB-Code-Labs/main-api/src/submissions/infrastructure/implementations"
 	"github.com/gin-gonic/gin"
 )
 
 func StartBlocksRoutes(g *gin.RouterGroup) {
 	blocksGroup := g.Group("/blocks")
 
+	submissionsUseCases := submissionsImplementations.NewSubmissionUseCases()
+
 	useCases := application.BlocksUseCases{
-		StaticFilesRepository: &staticFilesImplementations.StaticFilesMicroserviceImplementation{},
+		StaticFilesRepository: staticFilesImplementations.GetStaticFilesRepositoryInstance(),
 		BlocksRepository:      implementations.GetBlocksPostgresRepositoryInstance(),
 		LanguagesRepository:   languagesImplementations.GetLanguagesRepositoryInstance(),
+		SubmissionsRerunner:   submissionsUseCases,
+		ArchivesValidator:     staticFilesImplementations.GetZipArchivesValidatorInstance(),
 	}
 
 	controller := BlocksController{
@@ -57,6 +62,13 @@ func StartBlocksRoutes(g *gin.RouterGroup) {
 		controller.HandleSwapBlocks,
 	)
 
+	blocksGroup.GET(
+		"/test_blocks/:block_uuid",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleGetTestBlock,
+	)
+
 	blocksGroup.GET(
 		"/test_blocks/:block_uuid/tests_archive",
 		sharedInfrastructure.WithAuthenticationMiddleware(),
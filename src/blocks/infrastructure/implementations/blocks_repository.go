@@ -29,7 +29,7 @@ func GetBlocksPostgresRepositoryInstance() *BlocksPostgresRepository {
 	if blocksPostgresRepositoryInstance == nil {
 		blocksPostgresRepositoryInstance = &BlocksPostgresRepository{
 			Connection:            sharedInfrastructure.GetPostgresConnection(),
-			StaticFilesRepository: &staticFilesImplementations.StaticFilesMicroserviceImplementation{},
+			StaticFilesRepository: staticFilesImplementations.GetStaticFilesRepositoryInstance(),
 		}
 	}
 
@@ -76,8 +76,9 @@ func (repository *BlocksPostgresRepository) DoesTeacherOwnsMarkdownBlock(teacher
 		WHERE id = (
 			SELECT course_id
 			FROM laboratories
-			WHERE id = $1
+			WHERE id = $1 AND deleted_at IS NULL
 		)
+		AND deleted_at IS NULL
 	`
 
 	row = repository.Connection.QueryRowContext(ctx, query, laboratoryUUID)
@@ -117,8 +118,9 @@ func (repository *BlocksPostgresRepository) DoesTeacherOwnsTestBlock(teacherUUID
 		WHERE id = (
 			SELECT course_id
 			FROM laboratories
-			WHERE id = $1
+			WHERE id = $1 AND deleted_at IS NULL
 		)
+		AND deleted_at IS NULL
 	`
 
 	row = repository.Connection.QueryRowContext(ctx, query, laboratoryUUID)
@@ -136,7 +138,7 @@ func (repository *BlocksPostgresRepository) CanStudentSubmitToTestBlock(studentU
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	// Check if the student is enrolled in the laboratory the block belongs to
+	// Check if the student is enrolled in the laboratory the block belongs to and the laboratory is published
 	query := `
 		SELECT user_id
 		FROM courses_has_users
@@ -151,7 +153,10 @@ func (repository *BlocksPostgresRepository) CanStudentSubmitToTestBlock(studentU
 					FROM test_blocks
 					WHERE id = $1
 				)
+				AND is_published
+				AND deleted_at IS NULL
 			)
+			AND deleted_at IS NULL
 		) 
 		AND user_id = $2 
 		AND is_user_active = true
@@ -199,18 +204,41 @@ func (repository *BlocksPostgresRepository) GetTestArchiveUUIDFromTestBlockUUID(
 	return uuid, nil
 }
 
+// UpdateTestBlock updates the language, name and execution limits of a test block. The limits that were not
+// sent are kept, unless the overrides are reset
 func (repository *BlocksPostgresRepository) UpdateTestBlock(dto *dtos.UpdateTestBlockDTO) (err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	// Update the block
+	limits := dto.ExecutionLimits
+	if limits == nil {
+		limits = &sharedEntities.ExecutionLimitsOverrides{}
+	}
+
 	query := `
 		UPDATE test_blocks
-		SET language_id = $1, name = $2
-		WHERE id = $3
+		SET
+			language_id = $1, name = $2,
+			cpu_time_limit_ms = CASE WHEN $7 THEN $3 ELSE COALESCE($3, cpu_time_limit_ms) END,
+			wall_time_limit_ms = CASE WHEN $7 THEN $4 ELSE COALESCE($4, wall_time_limit_ms) END,
+			memory_limit_mb = CASE WHEN $7 THEN $5 ELSE COALESCE($5, memory_limit_mb) END,
+			output_size_limit_kb = CASE WHEN $7 THEN $6 ELSE COALESCE($6, output_size_limit_kb) END
+		WHERE id = $8
 	`
 
-	_, err = repository.Connection.ExecContext(ctx, query, dto.LanguageUUID, dto.Name, dto.BlockUUID)
+	_, err = repository.Connection.ExecContext(
+		ctx,
+		query,
+		dto.LanguageUUID,
+		dto.Name,
+		limits.CPUTimeMs,
+		limits.WallTimeMs,
+		limits.MemoryMb,
+		limits.OutputSizeKb,
+		dto.ResetExecutionLimits,
+		dto.BlockUUID,
+	)
 	if err != nil {
 		return err
 	}
@@ -435,22 +463,40 @@ func (repository *BlocksPostgresRepository) GetTestBlockByUUID(blockUUID string)
 	defer cancel()
 
 	query := `
-		SELECT tb.id, tb.language_id, tb.test_archive_id, tb.name, bi.block_position
+		SELECT
+			tb.id, tb.language_id, tb.test_archive_id, tb.name, bi.block_position,
+			tb.cpu_time_limit_ms, tb.wall_time_limit_ms, tb.memory_limit_mb, tb.output_size_limit_kb,
+			COALESCE(tb.cpu_time_limit_ms, l.cpu_time_limit_ms),
+			COALESCE(tb.wall_time_limit_ms, l.wall_time_limit_ms),
+			COALESCE(tb.memory_limit_mb, l.memory_limit_mb),
+			COALESCE(tb.output_size_limit_kb, l.output_size_limit_kb)
 		FROM test_blocks tb
 		RIGHT JOIN blocks_index bi ON tb.block_index_id = bi.id
+		INNER JOIN languages l ON tb.language_id = l.id
 		WHERE tb.id = $1
 	`
 
 	row := repository.Connection.QueryRowContext(ctx, query, blockUUID)
 
 	// Parse the row
-	testBlock = &laboratoriesEntities.TestBlock{}
+	testBlock = &laboratoriesEntities.TestBlock{
+		ExecutionLimits:          &sharedEntities.ExecutionLimits{},
+		ExecutionLimitsOverrides: &sharedEntities.ExecutionLimitsOverrides{},
+	}
 	err = row.Scan(
 		&testBlock.UUID,
 		&testBlock.LanguageUUID,
 		&testBlock.TestArchiveUUID,
 		&testBlock.Name,
 		&testBlock.Index,
+		&testBlock.ExecutionLimitsOverrides.CPUTimeMs,
+		&testBlock.ExecutionLimitsOverrides.WallTimeMs,
+		&testBlock.ExecutionLimitsOverrides.MemoryMb,
+		&testBlock.ExecutionLimitsOverrides.OutputSizeKb,
+		&testBlock.ExecutionLimits.CPUTimeMs,
+		&testBlock.ExecutionLimits.WallTimeMs,
+		&testBlock.ExecutionLimits.MemoryMb,
+		&testBlock.ExecutionLimits.OutputSizeKb,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1,12 +1,21 @@
 package requests
 
+import sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+
 type UpdateMarkdownBlockContentRequest struct {
 	Content string `json:"content" validate:"required"`
 }
 
 type UpdateTestBlockRequest struct {
-	LanguageUUID string `validate:"required,uuid4"`
-	Name         string `validate:"required,min=4,max=255"`
+	LanguageUUID     string `validate:"required,uuid4"`
+	Name             string `validate:"required,min=4,max=255"`
+	RerunSubmissions string `validate:"omitempty,boolean"`
+
+	// Limits that replace the defaults of the language
+	ExecutionLimits *sharedInfrastructure.ExecutionLimitsRequest
+
+	// Stop replacing the defaults of the language with the limits that are not sent
+	ResetExecutionLimits string `validate:"omitempty,boolean"`
 }
 
 type SwapBlocksRequest struct {
@@ -7,9 +7,11 @@ import (
 	gradesHttp "github.com/UPB-Code-Labs/main-api/src/grades/infrastructure/http"
 	laboratoriesHttp "github.com/UPB-Code-Labs/main-api/src/laboratories/infrastructure/http"
 	languagesHttp "github.com/UPB-Code-Labs/main-api/src/languages/infrastructure/http"
+	plagiarismHttp "github.com/UPB-Code-Labs/main-api/src/plagiarism/infrastructure/http"
 	rubricsHttp "github.com/UPB-Code-Labs/main-api/src/rubrics/infrastructure/http"
 	sessionHttp "github.com/UPB-Code-Labs/main-api/src/session/infrastructure/http"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	staticFilesHttp "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/http"
 	submissionsHttp "github.com/UPB-Code-Labs/main-api/src/submissions/infrastructure/http"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -22,13 +24,18 @@ var routesGroups = []func(*gin.RouterGroup){
 	gradesHttp.StartGradesRoutes,
 	laboratoriesHttp.StartLaboratoriesRoutes,
 	languagesHttp.StartLanguagesRoutes,
+	plagiarismHttp.StartPlagiarismRoutes,
 	rubricsHttp.StartRubricsRoutes,
 	sessionHttp.StartSessionRoutes,
+	staticFilesHttp.StartStaticFilesRoutes,
 	submissionsHttp.StartSubmissionsRoutes,
 }
 
 func InstanceHttpServer() (r *gin.Engine) {
 	engine := gin.Default()
+
+	// Uploaded archives bigger than 1 MB are stored in temporary files instead of memory
+	engine.MaxMultipartMemory = 1 << 20
 	engine.Use(sharedInfrastructure.ErrorHandlerMiddleware())
 
 	// Configure CORS rules
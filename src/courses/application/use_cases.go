@@ -247,6 +247,59 @@ func (useCases *CoursesUseCases) GetCourseLaboratories(dto dtos.GetCourseLaborat
 	if dto.UserRole == "teacher" {
 		return useCases.Repository.GetCourseLaboratories(dto.CourseUUID)
 	} else {
-		return useCases.Repository.GetCourseActiveLaboratories(dto.CourseUUID)
+		return useCases.Repository.GetCourseActiveLaboratories(dto.CourseUUID, dto.UserUUID)
 	}
 }
+
+func (useCases *CoursesUseCases) DeleteCourse(dto *dtos.DeleteCourseDTO) error {
+	// Check the user is the teacher of the course
+	course, err := useCases.Repository.GetCourseByUUID(dto.CourseUUID)
+	if err != nil {
+		return err
+	}
+
+	teacherOwnsCourse := course.TeacherUUID == dto.TeacherUUID
+	if !teacherOwnsCourse {
+		return errors.TeacherDoesNotOwnsCourseError{}
+	}
+
+	// Hide the course until it is restored or purged
+	return useCases.Repository.DeleteCourse(dto.CourseUUID)
+}
+
+func (useCases *CoursesUseCases) RestoreCourse(dto *dtos.RestoreCourseDTO) error {
+	// Only the courses within the restore window can be restored
+	course, err := useCases.Repository.GetDeletedCourse(dto.CourseUUID, dto.RestoreWindowSeconds)
+	if err != nil {
+		return err
+	}
+
+	teacherOwnsCourse := course.TeacherUUID == dto.TeacherUUID
+	if !teacherOwnsCourse {
+		return errors.TeacherDoesNotOwnsCourseError{}
+	}
+
+	return useCases.Repository.RestoreCourse(dto.CourseUUID)
+}
+
+func (useCases *CoursesUseCases) GetDeletedCourses(dto *dtos.GetDeletedCoursesDTO) ([]*dtos.DeletedCourseDTO, error) {
+	return useCases.Repository.GetDeletedCourses(dto.TeacherUUID, dto.RestoreWindowSeconds)
+}
+
+func (useCases *CoursesUseCases) GetCourseDeletedLaboratories(dto *dtos.GetCourseDeletedLaboratoriesDTO) ([]*dtos.DeletedLaboratoryDTO, error) {
+	// Check the teacher owns the course
+	teacherOwnsCourse, err := useCases.Repository.DoesTeacherOwnsCourse(dto.TeacherUUID, dto.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !teacherOwnsCourse {
+		return nil, errors.TeacherDoesNotOwnsCourseError{}
+	}
+
+	return useCases.Repository.GetCourseDeletedLaboratories(dto.CourseUUID, dto.RestoreWindowSeconds)
+}
+
+// PurgeDeletions removes for good the courses and laboratories whose restore window has passed
+func (useCases *CoursesUseCases) PurgeDeletions(restoreWindowSeconds int) (*dtos.PurgedDeletionsDTO, error) {
+	return useCases.Repository.PurgeDeletions(restoreWindowSeconds)
+}
@@ -24,7 +24,16 @@ type CoursesRepository interface {
 	UpdateCourseName(dtos.RenameCourseDTO) error
 
 	GetCourseLaboratories(courseUUID string) ([]*dtos.BaseLaboratoryDTO, error)
-	GetCourseActiveLaboratories(courseUUID string) ([]*dtos.BaseLaboratoryDTO, error)
+	GetCourseActiveLaboratories(courseUUID string, studentUUID string) ([]*dtos.BaseLaboratoryDTO, error)
 
 	DoesTeacherOwnsCourse(teacherUUID, courseUUID string) (bool, error)
+
+	// Soft deletions. The deleted courses and laboratories can be restored within the restore window, then
+	// they are purged along with their blocks, submissions and grades
+	DeleteCourse(courseUUID string) error
+	GetDeletedCourse(courseUUID string, restoreWindowSeconds int) (*dtos.DeletedCourseDTO, error)
+	GetDeletedCourses(teacherUUID string, restoreWindowSeconds int) ([]*dtos.DeletedCourseDTO, error)
+	RestoreCourse(courseUUID string) error
+	GetCourseDeletedLaboratories(courseUUID string, restoreWindowSeconds int) ([]*dtos.DeletedLaboratoryDTO, error)
+	PurgeDeletions(restoreWindowSeconds int) (*dtos.PurgedDeletionsDTO, error)
 }
@@ -49,3 +49,35 @@ type RenameCourseDTO struct {
 	CourseUUID  string
 	NewName     string
 }
+
+type DeleteCourseDTO struct {
+	TeacherUUID string
+	CourseUUID  string
+}
+
+type RestoreCourseDTO struct {
+	TeacherUUID          string
+	CourseUUID           string
+	RestoreWindowSeconds int
+}
+
+type GetDeletedCoursesDTO struct {
+	TeacherUUID          string
+	RestoreWindowSeconds int
+}
+
+// DeletedCourseDTO course that can still be restored by its teacher
+type DeletedCourseDTO struct {
+	UUID            string `json:"uuid"`
+	TeacherUUID     string `json:"-"`
+	Name            string `json:"name"`
+	Color           string `json:"color"`
+	DeletedAt       string `json:"deleted_at"`
+	RestorableUntil string `json:"restorable_until"`
+}
+
+// PurgedDeletionsDTO number of courses and laboratories removed for good by the deletions purger
+type PurgedDeletionsDTO struct {
+	Laboratories int
+	Courses      int
+}
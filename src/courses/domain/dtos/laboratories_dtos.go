@@ -11,4 +11,19 @@ type BaseLaboratoryDTO struct {
 	Name        string `json:"name"`
 	OpeningDate string `json:"opening_date"`
 	DueDate     string `json:"due_date"`
+	IsPublished bool   `json:"is_published"`
+}
+
+type GetCourseDeletedLaboratoriesDTO struct {
+	TeacherUUID          string
+	CourseUUID           string
+	RestoreWindowSeconds int
+}
+
+// DeletedLaboratoryDTO laboratory that can still be restored by the teacher of the course
+type DeletedLaboratoryDTO struct {
+	UUID            string `json:"uuid"`
+	Name            string `json:"name"`
+	DeletedAt       string `json:"deleted_at"`
+	RestorableUntil string `json:"restorable_until"`
 }
@@ -376,3 +376,96 @@ func (controller *CoursesController) HandleSetStudentStatus(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+func (controller *CoursesController) HandleDeleteCourse(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+
+	// Validate course uuid
+	courseUUID := c.Param("course_uuid")
+	if err := infrastructure.GetValidator().Var(courseUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Not valid course uuid",
+		})
+		return
+	}
+
+	err := controller.UseCases.DeleteCourse(&dtos.DeleteCourseDTO{
+		TeacherUUID: teacherUUID,
+		CourseUUID:  courseUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *CoursesController) HandleRestoreCourse(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+
+	// Validate course uuid
+	courseUUID := c.Param("course_uuid")
+	if err := infrastructure.GetValidator().Var(courseUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Not valid course uuid",
+		})
+		return
+	}
+
+	err := controller.UseCases.RestoreCourse(&dtos.RestoreCourseDTO{
+		TeacherUUID:          teacherUUID,
+		CourseUUID:           courseUUID,
+		RestoreWindowSeconds: infrastructure.GetEnvironment().DeletionsRestoreWindowSeconds,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *CoursesController) HandleGetDeletedCourses(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+
+	courses, err := controller.UseCases.GetDeletedCourses(&dtos.GetDeletedCoursesDTO{
+		TeacherUUID:          teacherUUID,
+		RestoreWindowSeconds: infrastructure.GetEnvironment().DeletionsRestoreWindowSeconds,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"courses": courses,
+	})
+}
+
+func (controller *CoursesController) HandleGetCourseDeletedLaboratories(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+
+	// Validate course uuid
+	courseUUID := c.Param("course_uuid")
+	if err := infrastructure.GetValidator().Var(courseUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Not valid course uuid",
+		})
+		return
+	}
+
+	laboratories, err := controller.UseCases.GetCourseDeletedLaboratories(&dtos.GetCourseDeletedLaboratoriesDTO{
+		TeacherUUID:          teacherUUID,
+		CourseUUID:           courseUUID,
+		RestoreWindowSeconds: infrastructure.GetEnvironment().DeletionsRestoreWindowSeconds,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"laboratories": laboratories,
+	})
+}
@@ -33,6 +33,13 @@ func StartCoursesRoutes(g *gin.RouterGroup) {
 		controller.HandleGetEnrolledCourses,
 	)
 
+	coursesGroup.GET(
+		"deleted",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleGetDeletedCourses,
+	)
+
 	coursesGroup.GET(
 		":course_uuid",
 		infrastructure.WithAuthenticationMiddleware(),
@@ -40,6 +47,20 @@ func StartCoursesRoutes(g *gin.RouterGroup) {
 		controller.HandleGetCourse,
 	)
 
+	coursesGroup.DELETE(
+		":course_uuid",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleDeleteCourse,
+	)
+
+	coursesGroup.POST(
+		":course_uuid/restore",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleRestoreCourse,
+	)
+
 	coursesGroup.GET(
 		":course_uuid/invitation-code",
 		infrastructure.WithAuthenticationMiddleware(),
@@ -89,6 +110,13 @@ func StartCoursesRoutes(g *gin.RouterGroup) {
 		controller.HandleGetCourseLaboratories,
 	)
 
+	coursesGroup.GET(
+		":course_uuid/laboratories/deleted",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleGetCourseDeletedLaboratories,
+	)
+
 	coursesGroup.PATCH(
 		":course_uuid/students/:student_uuid/status",
 		infrastructure.WithAuthenticationMiddleware(),
@@ -444,9 +444,9 @@ func (repository *CoursesPostgresRepository) GetCourseLaboratories(courseUUID st
 	defer cancel()
 
 	query := `
-		SELECT id, name, opening_date, due_date
+		SELECT id, name, opening_date, due_date, is_published
 		FROM laboratories
-		WHERE course_id = $1
+		WHERE course_id = $1 AND deleted_at IS NULL
 		ORDER BY opening_date ASC
 	`
 
@@ -459,18 +459,28 @@ func (repository *CoursesPostgresRepository) GetCourseLaboratories(courseUUID st
 	return repository.parseLaboratoriesRows(rows)
 }
 
-func (repository *CoursesPostgresRepository) GetCourseActiveLaboratories(courseUUID string) ([]*dtos.BaseLaboratoryDTO, error) {
+// GetCourseActiveLaboratories returns the published laboratories that are already open for the student, with
+// the dates they were granted, if any
+func (repository *CoursesPostgresRepository) GetCourseActiveLaboratories(courseUUID string, studentUUID string) ([]*dtos.BaseLaboratoryDTO, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	query := `
-		SELECT id, name, opening_date, due_date
-		FROM laboratories
-		WHERE course_id = $1 AND opening_date <= NOW()
+		SELECT * FROM (
+			SELECT
+				l.id, l.name,
+				COALESCE(o.opening_date, l.opening_date) AS opening_date,
+				COALESCE(o.due_date, l.due_date) AS due_date,
+				l.is_published
+			FROM laboratories AS l
+			LEFT JOIN laboratories_date_overrides AS o ON o.laboratory_id = l.id AND o.student_id = $2
+			WHERE l.course_id = $1 AND l.is_published AND l.deleted_at IS NULL
+		) AS student_laboratories
+		WHERE opening_date <= NOW()
 		ORDER BY opening_date ASC
 	`
 
-	rows, err := repository.Connection.QueryContext(ctx, query, courseUUID)
+	rows, err := repository.Connection.QueryContext(ctx, query, courseUUID, studentUUID)
 	if err != nil {
 		return nil, err
 	}
@@ -489,6 +499,7 @@ func (repository *CoursesPostgresRepository) parseLaboratoriesRows(rows *sql.Row
 			&laboratory.Name,
 			&laboratory.OpeningDate,
 			&laboratory.DueDate,
+			&laboratory.IsPublished,
 		)
 		if err != nil {
 			return nil, err
@@ -508,3 +519,198 @@ func (repository *CoursesPostgresRepository) DoesTeacherOwnsCourse(teacherUUID,
 
 	return course.TeacherUUID == teacherUUID, nil
 }
+
+// DeleteCourse hides the course, its laboratories and its invitation code until it is restored or purged
+func (repository *CoursesPostgresRepository) DeleteCourse(courseUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE courses
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	_, err := repository.Connection.ExecContext(ctx, query, courseUUID)
+	return err
+}
+
+// GetDeletedCourse returns the course if it was deleted and its restore window has not passed
+func (repository *CoursesPostgresRepository) GetDeletedCourse(courseUUID string, restoreWindowSeconds int) (*dtos.DeletedCourseDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT c.id, c.teacher_id, c.name, colors.hexadecimal, c.deleted_at,
+			c.deleted_at + make_interval(secs => $2)
+		FROM courses AS c
+		INNER JOIN colors ON c.color_id = colors.id
+		WHERE c.id = $1 AND c.deleted_at > NOW() - make_interval(secs => $2)
+	`
+
+	row := repository.Connection.QueryRowContext(ctx, query, courseUUID, restoreWindowSeconds)
+
+	course := &dtos.DeletedCourseDTO{}
+	if err := row.Scan(
+		&course.UUID, &course.TeacherUUID, &course.Name, &course.Color, &course.DeletedAt, &course.RestorableUntil,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, coursesErrors.NoCourseWithUUIDFound{
+				UUID: courseUUID,
+			}
+		}
+
+		return nil, err
+	}
+
+	return course, nil
+}
+
+// GetDeletedCourses returns the deleted courses of the teacher that can still be restored
+func (repository *CoursesPostgresRepository) GetDeletedCourses(teacherUUID string, restoreWindowSeconds int) ([]*dtos.DeletedCourseDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT c.id, c.teacher_id, c.name, colors.hexadecimal, c.deleted_at,
+			c.deleted_at + make_interval(secs => $2)
+		FROM courses AS c
+		INNER JOIN colors ON c.color_id = colors.id
+		WHERE c.teacher_id = $1 AND c.deleted_at > NOW() - make_interval(secs => $2)
+		ORDER BY c.deleted_at DESC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, teacherUUID, restoreWindowSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses := []*dtos.DeletedCourseDTO{}
+	for rows.Next() {
+		course := &dtos.DeletedCourseDTO{}
+
+		err := rows.Scan(
+			&course.UUID,
+			&course.TeacherUUID,
+			&course.Name,
+			&course.Color,
+			&course.DeletedAt,
+			&course.RestorableUntil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		courses = append(courses, course)
+	}
+
+	return courses, nil
+}
+
+// RestoreCourse shows the course again, along with the laboratories that were not deleted on their own
+func (repository *CoursesPostgresRepository) RestoreCourse(courseUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE courses
+		SET deleted_at = NULL
+		WHERE id = $1
+	`
+
+	_, err := repository.Connection.ExecContext(ctx, query, courseUUID)
+	return err
+}
+
+// GetCourseDeletedLaboratories returns the deleted laboratories of the course that can still be restored
+func (repository *CoursesPostgresRepository) GetCourseDeletedLaboratories(courseUUID string, restoreWindowSeconds int) ([]*dtos.DeletedLaboratoryDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, name, deleted_at, deleted_at + make_interval(secs => $2)
+		FROM laboratories
+		WHERE course_id = $1 AND deleted_at > NOW() - make_interval(secs => $2)
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, courseUUID, restoreWindowSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	laboratories := []*dtos.DeletedLaboratoryDTO{}
+	for rows.Next() {
+		laboratory := &dtos.DeletedLaboratoryDTO{}
+
+		err := rows.Scan(
+			&laboratory.UUID,
+			&laboratory.Name,
+			&laboratory.DeletedAt,
+			&laboratory.RestorableUntil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		laboratories = append(laboratories, laboratory)
+	}
+
+	return laboratories, nil
+}
+
+// PurgeDeletions removes for good the courses and laboratories whose restore window has passed. The foreign
+// keys cascade the deletion to their blocks, submissions and grades, so their archives become orphaned
+func (repository *CoursesPostgresRepository) PurgeDeletions(restoreWindowSeconds int) (*dtos.PurgedDeletionsDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	purged := &dtos.PurgedDeletionsDTO{}
+
+	// Purge the laboratories first, so the ones of the purged courses are not counted
+	purgeLaboratoriesQuery := `
+		DELETE FROM laboratories
+		WHERE deleted_at <= NOW() - make_interval(secs => $1)
+	`
+
+	result, err := tx.ExecContext(ctx, purgeLaboratoriesQuery, restoreWindowSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	purgedLaboratories, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	purged.Laboratories = int(purgedLaboratories)
+
+	purgeCoursesQuery := `
+		DELETE FROM courses
+		WHERE deleted_at <= NOW() - make_interval(secs => $1)
+	`
+
+	result, err = tx.ExecContext(ctx, purgeCoursesQuery, restoreWindowSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	purgedCourses, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	purged.Courses = int(purgedCourses)
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
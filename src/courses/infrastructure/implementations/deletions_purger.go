@@ -0,0 +1,65 @@
+package implementations
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/UPB-Code-Labs/main-api/src/courses/application"
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+)
+
+// DeletionsPurger periodically removes the courses and laboratories whose restore window has passed, along with
+// their blocks, submissions and grades. Their archives are left to the orphaned archives collector
+type DeletionsPurger struct {
+	// Deleted courses and laboratories can be restored within this window
+	RestoreWindowSeconds int
+
+	// Time between each purge
+	Interval time.Duration
+}
+
+// Singleton instance
+var deletionsPurgerInstance *DeletionsPurger
+var deletionsPurgerOnce sync.Once
+
+func GetDeletionsPurgerInstance() *DeletionsPurger {
+	deletionsPurgerOnce.Do(func() {
+		environment := sharedInfrastructure.GetEnvironment()
+
+		deletionsPurgerInstance = &DeletionsPurger{
+			RestoreWindowSeconds: environment.DeletionsRestoreWindowSeconds,
+			Interval:             time.Duration(environment.DeletionsPurgerIntervalSeconds) * time.Second,
+		}
+	})
+
+	return deletionsPurgerInstance
+}
+
+// Start purges the expired deletions every interval
+func (purger *DeletionsPurger) Start() {
+	log.Println("[Deletions purger]: Purging the expired deletions every", purger.Interval)
+
+	useCases := application.CoursesUseCases{
+		Repository: GetCoursesPgRepository(),
+	}
+
+	ticker := time.NewTicker(purger.Interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := useCases.PurgeDeletions(purger.RestoreWindowSeconds)
+		if err != nil {
+			log.Println("[Deletions purger]: There was an error while purging the expired deletions", err.Error())
+			continue
+		}
+
+		if purged.Courses > 0 || purged.Laboratories > 0 {
+			log.Printf(
+				"[Deletions purger]: %d courses and %d laboratories were purged",
+				purged.Courses,
+				purged.Laboratories,
+			)
+		}
+	}
+}
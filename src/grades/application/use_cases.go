@@ -3,6 +3,7 @@ package application
 import (
 	gradesDefinitions "github.com/UPB-Code-Labs/main-api/src/grades/domain/definitions"
 	"github.com/UPB-Code-Labs/main-api/src/grades/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/grades/domain/entities"
 	gradesErrors "github.com/UPB-Code-Labs/main-api/src/grades/domain/errors"
 	laboratoriesDefinitions "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
 	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
@@ -44,7 +45,18 @@ func (useCases *GradesUseCases) GetSummarizedGradesInLaboratory(dto *dtos.GetSum
 	}
 
 	// Get the grades
-	return useCases.GradesRepository.GetStudentsGradesInLaboratory(laboratoryUUID, *rubricUUID)
+	grades, err := useCases.GradesRepository.GetStudentsGradesInLaboratory(laboratoryUUID, *rubricUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deduct the penalty for the late submissions
+	for _, grade := range grades {
+		grade.PenaltyPercentage = laboratoryInformation.LatePolicy.GetPenaltyPercentage(grade.LateMinutes)
+		grade.Grade = grade.RawGrade * (100 - grade.PenaltyPercentage) / 100
+	}
+
+	return grades, nil
 }
 
 // SetCriteriaToGrade sets a criteria to a student's grade
@@ -130,7 +142,20 @@ func (useCases *GradesUseCases) GetStudentGradeInLaboratoryWithRubric(dto *dtos.
 
 	// Get the grade
 	grade, err := useCases.GradesRepository.GetStudentGradeInLaboratoryWithRubric(dto)
-	return grade, err
+	if err != nil {
+		return nil, err
+	}
+
+	// Deduct the penalty for the late submissions
+	laboratoryInformation, err := useCases.LaboratoriesRepository.GetLaboratoryInformationByUUID(dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	grade.PenaltyPercentage = laboratoryInformation.LatePolicy.GetPenaltyPercentage(grade.LateMinutes)
+	grade.Grade = grade.RawGrade * (100 - grade.PenaltyPercentage) / 100
+
+	return grade, nil
 }
 
 // SetCommentToGrade sets a comment to an student's grade
@@ -166,3 +191,251 @@ func (useCases *GradesUseCases) SetCommentToGrade(dto *dtos.SetCommentToGradeDTO
 	// Set the comment to the student's grade
 	return useCases.GradesRepository.SetCommentToGrade(dto)
 }
+
+// CreateGradingRule links a criteria of an objective of the laboratory's rubric to the result of a test block
+func (useCases *GradesUseCases) CreateGradingRule(dto *dtos.CreateGradingRuleDTO) (*entities.GradingRule, error) {
+	// Validate the teacher owns the laboratory
+	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnLaboratory(
+		dto.TeacherUUID,
+		dto.LaboratoryUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !teacherOwnsLaboratory {
+		return nil, laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
+	}
+
+	// Validate the test block belongs to the laboratory
+	laboratoryHasTestBlock, err := useCases.GradesRepository.DoesLaboratoryHaveTestBlock(
+		dto.LaboratoryUUID,
+		dto.TestBlockUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !laboratoryHasTestBlock {
+		return nil, gradesErrors.TestBlockDoesNotBelongToLaboratoryError{}
+	}
+
+	// Get the UUID of the current rubric of the laboratory
+	laboratoryInformation, err := useCases.LaboratoriesRepository.GetLaboratoryInformationByUUID(dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return an error if the laboratory does not have a rubric
+	rubricUUID := laboratoryInformation.RubricUUID
+	if rubricUUID == nil {
+		return nil, gradesErrors.LaboratoryDoesNotHaveRubricError{}
+	}
+
+	// Validate the objective belongs to the rubric
+	objectiveBelongsToRubric, err := useCases.RubricsRepository.DoesRubricHaveObjective(
+		*rubricUUID,
+		dto.ObjectiveUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !objectiveBelongsToRubric {
+		return nil, &rubricsErrors.ObjectiveDoesNotBelongToRubricError{}
+	}
+
+	// Validate the criteria belongs to the objective
+	criteriaBelongsToObjective, err := useCases.RubricsRepository.DoesObjectiveHaveCriteria(
+		dto.ObjectiveUUID,
+		dto.CriteriaUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !criteriaBelongsToObjective {
+		return nil, &rubricsErrors.CriteriaDoesNotBelongToObjectiveError{}
+	}
+
+	// Save the rule
+	return useCases.GradesRepository.SaveGradingRule(dto)
+}
+
+// GetGradingRulesInLaboratory returns the grading rules of the test blocks of a laboratory
+func (useCases *GradesUseCases) GetGradingRulesInLaboratory(dto *dtos.GetGradingRulesInLaboratoryDTO) ([]*entities.GradingRule, error) {
+	// Validate the teacher owns the laboratory
+	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnLaboratory(
+		dto.TeacherUUID,
+		dto.LaboratoryUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !teacherOwnsLaboratory {
+		return nil, laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
+	}
+
+	return useCases.GradesRepository.GetGradingRulesInLaboratory(dto.LaboratoryUUID)
+}
+
+// DeleteGradingRule deletes a grading rule of a laboratory. Criteria that were already selected
+// by the rule are kept in the students' grades
+func (useCases *GradesUseCases) DeleteGradingRule(dto *dtos.DeleteGradingRuleDTO) error {
+	// Validate the teacher owns the laboratory
+	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnLaboratory(
+		dto.TeacherUUID,
+		dto.LaboratoryUUID,
+	)
+	if err != nil {
+		return err
+	}
+	if !teacherOwnsLaboratory {
+		return laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
+	}
+
+	// Validate the rule belongs to the laboratory
+	laboratoryHasRule, err := useCases.GradesRepository.DoesLaboratoryHaveGradingRule(
+		dto.LaboratoryUUID,
+		dto.RuleUUID,
+	)
+	if err != nil {
+		return err
+	}
+	if !laboratoryHasRule {
+		return gradesErrors.GradingRuleNotFoundError{}
+	}
+
+	return useCases.GradesRepository.DeleteGradingRule(dto.RuleUUID)
+}
+
+// ApplyAutomaticGrading selects the criteria of the objectives linked to the test block of a ready
+// submission, using the results of the latest submissions of the student in the laboratory.
+//
+// When an objective is linked to several test blocks, the criteria with the lowest weight among the
+// ones selected for each test block is used. Objectives are skipped until all their test blocks have
+// a ready submission that matches, at least, one rule
+func (useCases *GradesUseCases) ApplyAutomaticGrading(submissionUUID string) error {
+	// Get the student, test block and laboratory of the submission
+	gradingContext, err := useCases.GradesRepository.GetSubmissionGradingContext(submissionUUID)
+	if err != nil {
+		return err
+	}
+
+	// Laboratories without a rubric cannot be graded
+	if gradingContext.RubricUUID == nil {
+		return nil
+	}
+
+	// Get the rules of the objectives linked to the test block
+	rules, err := useCases.GradesRepository.GetGradingRulesLinkedToTestBlock(
+		gradingContext.LaboratoryUUID,
+		*gradingContext.RubricUUID,
+		gradingContext.TestBlockUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// Get the results of the latest submissions of the student
+	results, err := useCases.GradesRepository.GetStudentTestBlocksResults(
+		gradingContext.StudentUUID,
+		gradingContext.LaboratoryUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	resultsByTestBlock := map[string]*dtos.StudentTestBlockResultDTO{}
+	for _, result := range results {
+		resultsByTestBlock[result.TestBlockUUID] = result
+	}
+
+	// Select the criteria of each objective
+	rulesByObjective := map[string][]*dtos.WeightedGradingRuleDTO{}
+	for _, rule := range rules {
+		rulesByObjective[rule.ObjectiveUUID] = append(rulesByObjective[rule.ObjectiveUUID], rule)
+	}
+
+	for objectiveUUID, objectiveRules := range rulesByObjective {
+		selectedRule := selectObjectiveRule(objectiveRules, resultsByTestBlock)
+		if selectedRule == nil {
+			continue
+		}
+
+		err = useCases.GradesRepository.SetAutomaticCriteriaToGrade(&dtos.SetAutomaticCriteriaToGradeDTO{
+			StudentUUID:    gradingContext.StudentUUID,
+			LaboratoryUUID: gradingContext.LaboratoryUUID,
+			RubricUUID:     *gradingContext.RubricUUID,
+			ObjectiveUUID:  objectiveUUID,
+			CriteriaUUID:   selectedRule.CriteriaUUID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// selectObjectiveRule returns the rule whose criteria should be selected for an objective or nil
+// if the criteria cannot be decided yet
+func selectObjectiveRule(rules []*dtos.WeightedGradingRuleDTO, resultsByTestBlock map[string]*dtos.StudentTestBlockResultDTO) *dtos.WeightedGradingRuleDTO {
+	// Get the matching rule with the highest weight of each test block
+	bestRuleByTestBlock := map[string]*dtos.WeightedGradingRuleDTO{}
+	for _, rule := range rules {
+		bestRuleByTestBlock[rule.TestBlockUUID] = nil
+	}
+
+	for _, rule := range rules {
+		result, hasResult := resultsByTestBlock[rule.TestBlockUUID]
+		if !hasResult || !result.IsReady || !doesRuleMatchResult(rule, result) {
+			continue
+		}
+
+		currentBest := bestRuleByTestBlock[rule.TestBlockUUID]
+		if currentBest == nil || rule.CriteriaWeight > currentBest.CriteriaWeight {
+			bestRuleByTestBlock[rule.TestBlockUUID] = rule
+		}
+	}
+
+	// Keep the rule with the lowest weight among the test blocks
+	var selectedRule *dtos.WeightedGradingRuleDTO
+	for _, bestRule := range bestRuleByTestBlock {
+		if bestRule == nil {
+			return nil
+		}
+
+		if selectedRule == nil || bestRule.CriteriaWeight < selectedRule.CriteriaWeight {
+			selectedRule = bestRule
+		}
+	}
+
+	return selectedRule
+}
+
+// doesRuleMatchResult checks if the result of a submission fulfills the condition of a rule
+func doesRuleMatchResult(rule *dtos.WeightedGradingRuleDTO, result *dtos.StudentTestBlockResultDTO) bool {
+	switch rule.Condition {
+	case "passing":
+		return result.IsPassing
+	case "failing":
+		return !result.IsPassing
+	case "min_tests_ratio":
+		if rule.MinTestsRatio == nil {
+			return false
+		}
+
+		// Submissions without detailed results pass all or none of the tests
+		testsRatio := 0.0
+		if result.TotalTests > 0 {
+			testsRatio = float64(result.PassedTests) / float64(result.TotalTests)
+		} else if result.IsPassing {
+			testsRatio = 1.0
+		}
+
+		return testsRatio >= *rule.MinTestsRatio
+	default:
+		return false
+	}
+}
@@ -0,0 +1,471 @@
+package application
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	gradesDefinitions "github.com/UPB-Code-Labs/main-api/src/grades/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/grades/domain/dtos"
+	gradesEntities "github.com/UPB-Code-Labs/main-api/src/grades/domain/entities"
+	laboratoriesDefinitions "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
+	laboratoriesDTOs "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	laboratoriesEntities "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+)
+
+type laboratoriesRepositoryStub struct {
+	laboratoriesDefinitions.LaboratoriesRepository
+
+	laboratory *laboratoriesDTOs.LaboratoryDetailsDTO
+}
+
+func (stub *laboratoriesRepositoryStub) DoesTeacherOwnLaboratory(teacherUUID string, laboratoryUUID string) (bool, error) {
+	return teacherUUID == "teacher", nil
+}
+
+func (stub *laboratoriesRepositoryStub) GetLaboratoryInformationByUUID(uuid string) (*laboratoriesDTOs.LaboratoryDetailsDTO, error) {
+	return stub.laboratory, nil
+}
+
+// latePolicyGradesRepositoryStub returns the raw grades with the given lateness
+type latePolicyGradesRepositoryStub struct {
+	gradesDefinitions.GradesRepository
+
+	grades       []*dtos.SummarizedStudentGradeDTO
+	studentGrade *dtos.StudentGradeInLaboratoryWithRubricDTO
+}
+
+func (stub *latePolicyGradesRepositoryStub) GetStudentsGradesInLaboratory(laboratoryUUID, rubricUUID string) ([]*dtos.SummarizedStudentGradeDTO, error) {
+	return stub.grades, nil
+}
+
+func (stub *latePolicyGradesRepositoryStub) GetStudentGradeInLaboratoryWithRubric(dto *dtos.GetStudentGradeInLaboratoryWithRubricDTO) (*dtos.StudentGradeInLaboratoryWithRubricDTO, error) {
+	return stub.studentGrade, nil
+}
+
+func TestGetSummarizedGradesInLaboratoryAppliesLatePolicy(t *testing.T) {
+	hour := "hour"
+	day := "day"
+	rubricUUID := "rubric"
+
+	testCases := []struct {
+		name              string
+		latePolicy        laboratoriesEntities.LatePolicy
+		lateMinutes       int
+		expectedPenalty   float64
+		expectedGradeOf80 float64
+	}{
+		{
+			name:              "on time",
+			latePolicy:        laboratoriesEntities.LatePolicy{Policy: "late_window", WindowMinutes: 600, PenaltyPercentage: 10, PenaltyUnit: &hour},
+			lateMinutes:       0,
+			expectedPenalty:   0,
+			expectedGradeOf80: 80,
+		},
+		{
+			name:              "grace period",
+			latePolicy:        laboratoriesEntities.LatePolicy{Policy: "grace_period", WindowMinutes: 30},
+			lateMinutes:       20,
+			expectedPenalty:   0,
+			expectedGradeOf80: 80,
+		},
+		{
+			// Every started hour is penalized
+			name:              "late window per hour",
+			latePolicy:        laboratoriesEntities.LatePolicy{Policy: "late_window", WindowMinutes: 600, PenaltyPercentage: 10, PenaltyUnit: &hour},
+			lateMinutes:       61,
+			expectedPenalty:   20,
+			expectedGradeOf80: 64,
+		},
+		{
+			name:              "late window per day",
+			latePolicy:        laboratoriesEntities.LatePolicy{Policy: "late_window", WindowMinutes: 4320, PenaltyPercentage: 25, PenaltyUnit: &day},
+			lateMinutes:       1440,
+			expectedPenalty:   25,
+			expectedGradeOf80: 60,
+		},
+		{
+			name:              "penalty never exceeds the grade",
+			latePolicy:        laboratoriesEntities.LatePolicy{Policy: "late_window", WindowMinutes: 4320, PenaltyPercentage: 60, PenaltyUnit: &day},
+			lateMinutes:       2000,
+			expectedPenalty:   100,
+			expectedGradeOf80: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			useCases := &GradesUseCases{
+				LaboratoriesRepository: &laboratoriesRepositoryStub{
+					laboratory: &laboratoriesDTOs.LaboratoryDetailsDTO{
+						UUID:       "laboratory",
+						RubricUUID: &rubricUUID,
+						LatePolicy: testCase.latePolicy,
+					},
+				},
+				GradesRepository: &latePolicyGradesRepositoryStub{
+					grades: []*dtos.SummarizedStudentGradeDTO{
+						{StudentUUID: "student", Grade: 80, RawGrade: 80, LateMinutes: testCase.lateMinutes},
+					},
+				},
+			}
+
+			grades, err := useCases.GetSummarizedGradesInLaboratory(&dtos.GetSummarizedGradesInLaboratoryDTO{
+				TeacherUUID:    "teacher",
+				LaboratoryUUID: "laboratory",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if grades[0].PenaltyPercentage != testCase.expectedPenalty {
+				t.Errorf("expected a penalty of %v, got %v", testCase.expectedPenalty, grades[0].PenaltyPercentage)
+			}
+
+			if math.Abs(grades[0].Grade-testCase.expectedGradeOf80) > 1e-9 {
+				t.Errorf("expected a grade of %v, got %v", testCase.expectedGradeOf80, grades[0].Grade)
+			}
+
+			if grades[0].RawGrade != 80 {
+				t.Errorf("expected the raw grade to be kept, got %v", grades[0].RawGrade)
+			}
+		})
+	}
+}
+
+func TestGetStudentGradeInLaboratoryWithRubricAppliesLatePolicy(t *testing.T) {
+	hour := "hour"
+	rubricUUID := "rubric"
+
+	useCases := &GradesUseCases{
+		LaboratoriesRepository: &laboratoriesRepositoryStub{
+			laboratory: &laboratoriesDTOs.LaboratoryDetailsDTO{
+				UUID:       "laboratory",
+				RubricUUID: &rubricUUID,
+				LatePolicy: laboratoriesEntities.LatePolicy{Policy: "late_window", WindowMinutes: 600, PenaltyPercentage: 10, PenaltyUnit: &hour},
+			},
+		},
+		GradesRepository: &latePolicyGradesRepositoryStub{
+			studentGrade: &dtos.StudentGradeInLaboratoryWithRubricDTO{Grade: 80, RawGrade: 80, LateMinutes: 61},
+		},
+	}
+
+	// The student reads their own grade
+	grade, err := useCases.GetStudentGradeInLaboratoryWithRubric(&dtos.GetStudentGradeInLaboratoryWithRubricDTO{
+		UserUUID:       "student",
+		StudentUUID:    "student",
+		LaboratoryUUID: "laboratory",
+		RubricUUID:     rubricUUID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if grade.PenaltyPercentage != 20 {
+		t.Errorf("expected a penalty of 20, got %v", grade.PenaltyPercentage)
+	}
+
+	if math.Abs(grade.Grade-64) > 1e-9 {
+		t.Errorf("expected a grade of 64, got %v", grade.Grade)
+	}
+
+	if grade.RawGrade != 80 {
+		t.Errorf("expected the raw grade to be kept, got %v", grade.RawGrade)
+	}
+}
+
+// automaticGradingRepositoryStub returns the given grading data and records the criteria selected for each
+// objective
+type automaticGradingRepositoryStub struct {
+	gradesDefinitions.GradesRepository
+
+	gradingContext   *dtos.SubmissionGradingContextDTO
+	rules            []*dtos.WeightedGradingRuleDTO
+	results          []*dtos.StudentTestBlockResultDTO
+	selectedCriteria map[string]string
+}
+
+func (stub *automaticGradingRepositoryStub) GetSubmissionGradingContext(submissionUUID string) (*dtos.SubmissionGradingContextDTO, error) {
+	return stub.gradingContext, nil
+}
+
+func (stub *automaticGradingRepositoryStub) GetGradingRulesLinkedToTestBlock(laboratoryUUID, rubricUUID, testBlockUUID string) ([]*dtos.WeightedGradingRuleDTO, error) {
+	return stub.rules, nil
+}
+
+func (stub *automaticGradingRepositoryStub) GetStudentTestBlocksResults(studentUUID, laboratoryUUID string) ([]*dtos.StudentTestBlockResultDTO, error) {
+	return stub.results, nil
+}
+
+func (stub *automaticGradingRepositoryStub) SetAutomaticCriteriaToGrade(dto *dtos.SetAutomaticCriteriaToGradeDTO) error {
+	stub.selectedCriteria[dto.ObjectiveUUID] = dto.CriteriaUUID
+	return nil
+}
+
+// newTestGradingRule returns a rule of an objective whose criteria has the given weight
+func newTestGradingRule(objectiveUUID, testBlockUUID, criteriaUUID string, weight float64, condition string, minTestsRatio *float64) *dtos.WeightedGradingRuleDTO {
+	return &dtos.WeightedGradingRuleDTO{
+		GradingRule: gradesEntities.GradingRule{
+			UUID:          objectiveUUID + " " + criteriaUUID,
+			TestBlockUUID: testBlockUUID,
+			ObjectiveUUID: objectiveUUID,
+			CriteriaUUID:  criteriaUUID,
+			Condition:     condition,
+			MinTestsRatio: minTestsRatio,
+		},
+		CriteriaWeight: weight,
+	}
+}
+
+func TestDoesRuleMatchResult(t *testing.T) {
+	halfOfTheTests := 0.5
+
+	testCases := []struct {
+		name          string
+		condition     string
+		minTestsRatio *float64
+		result        dtos.StudentTestBlockResultDTO
+		expected      bool
+	}{
+		{
+			name:      "passing rule with a passing submission",
+			condition: "passing",
+			result:    dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: true},
+			expected:  true,
+		},
+		{
+			name:      "passing rule with a failing submission",
+			condition: "passing",
+			result:    dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: false},
+			expected:  false,
+		},
+		{
+			name:      "failing rule with a failing submission",
+			condition: "failing",
+			result:    dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: false},
+			expected:  true,
+		},
+		{
+			name:      "failing rule with a passing submission",
+			condition: "failing",
+			result:    dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: true},
+			expected:  false,
+		},
+		{
+			name:          "ratio reached",
+			condition:     "min_tests_ratio",
+			minTestsRatio: &halfOfTheTests,
+			result:        dtos.StudentTestBlockResultDTO{IsReady: true, TotalTests: 4, PassedTests: 2},
+			expected:      true,
+		},
+		{
+			name:          "ratio not reached",
+			condition:     "min_tests_ratio",
+			minTestsRatio: &halfOfTheTests,
+			result:        dtos.StudentTestBlockResultDTO{IsReady: true, TotalTests: 4, PassedTests: 1},
+			expected:      false,
+		},
+		{
+			name:          "ratio of a passing submission without detailed results",
+			condition:     "min_tests_ratio",
+			minTestsRatio: &halfOfTheTests,
+			result:        dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: true},
+			expected:      true,
+		},
+		{
+			name:          "ratio of a failing submission without detailed results",
+			condition:     "min_tests_ratio",
+			minTestsRatio: &halfOfTheTests,
+			result:        dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: false},
+			expected:      false,
+		},
+		{
+			name:      "ratio rule without a ratio",
+			condition: "min_tests_ratio",
+			result:    dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: true, TotalTests: 1, PassedTests: 1},
+			expected:  false,
+		},
+		{
+			name:      "unknown condition",
+			condition: "unknown",
+			result:    dtos.StudentTestBlockResultDTO{IsReady: true, IsPassing: true},
+			expected:  false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule := newTestGradingRule("objective", "block", "criteria", 1, testCase.condition, testCase.minTestsRatio)
+
+			if matches := doesRuleMatchResult(rule, &testCase.result); matches != testCase.expected {
+				t.Errorf("expected the rule to match: %v, got %v", testCase.expected, matches)
+			}
+		})
+	}
+}
+
+func TestSelectObjectiveRule(t *testing.T) {
+	mostOfTheTests := 0.75
+	halfOfTheTests := 0.5
+
+	passingRule := newTestGradingRule("objective", "first-block", "excellent", 5, "passing", nil)
+	mostTestsRule := newTestGradingRule("objective", "first-block", "good", 4, "min_tests_ratio", &mostOfTheTests)
+	halfTestsRule := newTestGradingRule("objective", "first-block", "regular", 3, "min_tests_ratio", &halfOfTheTests)
+	failingRule := newTestGradingRule("objective", "first-block", "bad", 1, "failing", nil)
+	firstBlockRules := []*dtos.WeightedGradingRuleDTO{failingRule, halfTestsRule, mostTestsRule, passingRule}
+
+	secondBlockRule := newTestGradingRule("objective", "second-block", "regular in the second block", 3, "passing", nil)
+
+	testCases := []struct {
+		name     string
+		rules    []*dtos.WeightedGradingRuleDTO
+		results  []*dtos.StudentTestBlockResultDTO
+		expected *dtos.WeightedGradingRuleDTO
+	}{
+		{
+			name:  "passing takes precedence over the ratios",
+			rules: firstBlockRules,
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: true, IsPassing: true, TotalTests: 4, PassedTests: 4},
+			},
+			expected: passingRule,
+		},
+		{
+			name:  "the highest reached ratio takes precedence",
+			rules: firstBlockRules,
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: true, IsPassing: false, TotalTests: 4, PassedTests: 3},
+			},
+			expected: mostTestsRule,
+		},
+		{
+			name:  "a ratio takes precedence over failing",
+			rules: firstBlockRules,
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: true, IsPassing: false, TotalTests: 4, PassedTests: 2},
+			},
+			expected: halfTestsRule,
+		},
+		{
+			name:  "failing when no ratio is reached",
+			rules: firstBlockRules,
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: true, IsPassing: false, TotalTests: 4, PassedTests: 1},
+			},
+			expected: failingRule,
+		},
+		{
+			name:     "no result for the test block",
+			rules:    firstBlockRules,
+			results:  []*dtos.StudentTestBlockResultDTO{},
+			expected: nil,
+		},
+		{
+			name:  "the result is not ready",
+			rules: firstBlockRules,
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: false, IsPassing: true},
+			},
+			expected: nil,
+		},
+		{
+			name:  "no rule matches the result",
+			rules: []*dtos.WeightedGradingRuleDTO{passingRule},
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: true, IsPassing: false},
+			},
+			expected: nil,
+		},
+		{
+			name:  "the lowest criteria among the test blocks is used",
+			rules: append([]*dtos.WeightedGradingRuleDTO{secondBlockRule}, firstBlockRules...),
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: true, IsPassing: true},
+				{TestBlockUUID: "second-block", IsReady: true, IsPassing: true},
+			},
+			expected: secondBlockRule,
+		},
+		{
+			name:  "a test block without a result blocks the objective",
+			rules: append([]*dtos.WeightedGradingRuleDTO{secondBlockRule}, firstBlockRules...),
+			results: []*dtos.StudentTestBlockResultDTO{
+				{TestBlockUUID: "first-block", IsReady: true, IsPassing: true},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			resultsByTestBlock := map[string]*dtos.StudentTestBlockResultDTO{}
+			for _, result := range testCase.results {
+				resultsByTestBlock[result.TestBlockUUID] = result
+			}
+
+			selectedRule := selectObjectiveRule(testCase.rules, resultsByTestBlock)
+			if selectedRule != testCase.expected {
+				t.Errorf("expected the rule %v, got %v", testCase.expected, selectedRule)
+			}
+		})
+	}
+}
+
+func TestApplyAutomaticGrading(t *testing.T) {
+	rubricUUID := "rubric"
+	repository := &automaticGradingRepositoryStub{
+		gradingContext: &dtos.SubmissionGradingContextDTO{
+			SubmissionUUID: "submission",
+			StudentUUID:    "student",
+			TestBlockUUID:  "first-block",
+			LaboratoryUUID: "laboratory",
+			RubricUUID:     &rubricUUID,
+		},
+		rules: []*dtos.WeightedGradingRuleDTO{
+			newTestGradingRule("correctness", "first-block", "correct", 5, "passing", nil),
+			newTestGradingRule("correctness", "first-block", "incorrect", 0, "failing", nil),
+			newTestGradingRule("style", "first-block", "clean", 5, "passing", nil),
+			newTestGradingRule("style", "second-block", "clean in the second block", 5, "passing", nil),
+		},
+		results: []*dtos.StudentTestBlockResultDTO{
+			{TestBlockUUID: "first-block", IsReady: true, IsPassing: true},
+		},
+		selectedCriteria: map[string]string{},
+	}
+
+	useCases := &GradesUseCases{GradesRepository: repository}
+	if err := useCases.ApplyAutomaticGrading("submission"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The style objective is skipped, as there is no result for the second test block yet
+	expectedCriteria := map[string]string{"correctness": "correct"}
+	if !reflect.DeepEqual(repository.selectedCriteria, expectedCriteria) {
+		t.Errorf("expected the criteria %v, got %v", expectedCriteria, repository.selectedCriteria)
+	}
+}
+
+func TestApplyAutomaticGradingWithoutRubric(t *testing.T) {
+	repository := &automaticGradingRepositoryStub{
+		gradingContext: &dtos.SubmissionGradingContextDTO{
+			SubmissionUUID: "submission",
+			TestBlockUUID:  "first-block",
+			LaboratoryUUID: "laboratory",
+		},
+		rules: []*dtos.WeightedGradingRuleDTO{
+			newTestGradingRule("correctness", "first-block", "correct", 5, "passing", nil),
+		},
+		results: []*dtos.StudentTestBlockResultDTO{
+			{TestBlockUUID: "first-block", IsReady: true, IsPassing: true},
+		},
+		selectedCriteria: map[string]string{},
+	}
+
+	useCases := &GradesUseCases{GradesRepository: repository}
+	if err := useCases.ApplyAutomaticGrading("submission"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repository.selectedCriteria) != 0 {
+		t.Errorf("expected no criteria to be selected, got %v", repository.selectedCriteria)
+	}
+}
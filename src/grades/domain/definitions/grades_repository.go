@@ -1,6 +1,9 @@
 package definitions
 
-import "github.com/UPB-Code-Labs/main-api/src/grades/domain/dtos"
+import (
+	"github.com/UPB-Code-Labs/main-api/src/grades/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/grades/domain/entities"
+)
 
 // GradesRepository interface to be implemented by the repository
 type GradesRepository interface {
@@ -18,4 +21,17 @@ type GradesRepository interface {
 		*dtos.StudentGradeInLaboratoryWithRubricDTO,
 		error,
 	)
+
+	// Automatic grading rules
+	DoesLaboratoryHaveTestBlock(laboratoryUUID, testBlockUUID string) (bool, error)
+	SaveGradingRule(dto *dtos.CreateGradingRuleDTO) (*entities.GradingRule, error)
+	GetGradingRulesInLaboratory(laboratoryUUID string) ([]*entities.GradingRule, error)
+	DoesLaboratoryHaveGradingRule(laboratoryUUID, ruleUUID string) (bool, error)
+	DeleteGradingRule(ruleUUID string) error
+
+	// Automatic grading
+	GetSubmissionGradingContext(submissionUUID string) (*dtos.SubmissionGradingContextDTO, error)
+	GetGradingRulesLinkedToTestBlock(laboratoryUUID, rubricUUID, testBlockUUID string) ([]*dtos.WeightedGradingRuleDTO, error)
+	GetStudentTestBlocksResults(studentUUID, laboratoryUUID string) ([]*dtos.StudentTestBlockResultDTO, error)
+	SetAutomaticCriteriaToGrade(dto *dtos.SetAutomaticCriteriaToGradeDTO) error
 }
@@ -1,16 +1,22 @@
 package dtos
 
+import "github.com/UPB-Code-Labs/main-api/src/grades/domain/entities"
+
 // GetSummarizedGradesInLaboratoryDTO data transfer object to parse the request of the endpoint
 type GetSummarizedGradesInLaboratoryDTO struct {
 	TeacherUUID    string
 	LaboratoryUUID string
 }
 
-// SummarizedStudentGradeDTO data transfer object to be used as the response of the endpoint
+// SummarizedStudentGradeDTO data transfer object to be used as the response of the endpoint. The grade
+// is the raw grade after deducting the penalty of the late policy of the laboratory
 type SummarizedStudentGradeDTO struct {
-	StudentUUID     string  `json:"student_uuid"`
-	StudentFullName string  `json:"student_full_name"`
-	Grade           float64 `json:"grade"`
+	StudentUUID       string  `json:"student_uuid"`
+	StudentFullName   string  `json:"student_full_name"`
+	Grade             float64 `json:"grade"`
+	RawGrade          float64 `json:"raw_grade"`
+	LateMinutes       int     `json:"late_minutes"`
+	PenaltyPercentage float64 `json:"penalty_percentage"`
 }
 
 // SetCriteriaToGradeDTO data transfer object to parse the request of the endpoint
@@ -46,17 +52,22 @@ type GetStudentGradeInLaboratoryWithRubricDTO struct {
 	RubricUUID     string
 }
 
-// STudentGradeInLaboratoryWithRubricDTO data transfer object to be used as the response of the endpoint
+// STudentGradeInLaboratoryWithRubricDTO data transfer object to be used as the response of the endpoint. The
+// grade is the raw grade after deducting the penalty of the late policy of the laboratory
 type StudentGradeInLaboratoryWithRubricDTO struct {
-	Grade            float64                              `json:"grade"`
-	Comment          string                               `json:"comment"`
-	SelectedCriteria []*SelectedCriteriaInStudentGradeDTO `json:"selected_criteria"`
+	Grade             float64                              `json:"grade"`
+	RawGrade          float64                              `json:"raw_grade"`
+	LateMinutes       int                                  `json:"late_minutes"`
+	PenaltyPercentage float64                              `json:"penalty_percentage"`
+	Comment           string                               `json:"comment"`
+	SelectedCriteria  []*SelectedCriteriaInStudentGradeDTO `json:"selected_criteria"`
 }
 
 // SelectedCriteriaInStudentGradeDTO data transfer object to obtain the selected criteria in a student's grade
 type SelectedCriteriaInStudentGradeDTO struct {
 	ObjectiveUUID string  `json:"objective_uuid"`
 	CriteriaUUID  *string `json:"criteria_uuid"`
+	IsAutomatic   bool    `json:"is_automatic"`
 }
 
 // SetCommentToGradeDTO data transfer object to parse the request of the endpoint
@@ -67,3 +78,62 @@ type SetCommentToGradeDTO struct {
 	StudentUUID    string
 	Comment        string
 }
+
+// CreateGradingRuleDTO data transfer object to parse the request of the endpoint
+type CreateGradingRuleDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+	TestBlockUUID  string
+	ObjectiveUUID  string
+	CriteriaUUID   string
+	Condition      string
+	MinTestsRatio  *float64
+}
+
+// GetGradingRulesInLaboratoryDTO data transfer object to parse the request of the endpoint
+type GetGradingRulesInLaboratoryDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+}
+
+// DeleteGradingRuleDTO data transfer object to parse the request of the endpoint
+type DeleteGradingRuleDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+	RuleUUID       string
+}
+
+// SubmissionGradingContextDTO data transfer object with the information needed to
+// automatically grade a submission
+type SubmissionGradingContextDTO struct {
+	SubmissionUUID string
+	StudentUUID    string
+	TestBlockUUID  string
+	LaboratoryUUID string
+	RubricUUID     *string
+}
+
+// WeightedGradingRuleDTO data transfer object with a grading rule and the weight of its criteria
+type WeightedGradingRuleDTO struct {
+	entities.GradingRule
+	CriteriaWeight float64
+}
+
+// StudentTestBlockResultDTO data transfer object with the result of the latest submission
+// of a student to a test block
+type StudentTestBlockResultDTO struct {
+	TestBlockUUID string
+	IsReady       bool
+	IsPassing     bool
+	TotalTests    int
+	PassedTests   int
+}
+
+// SetAutomaticCriteriaToGradeDTO data transfer object to set a criteria selected by the automatic grading
+type SetAutomaticCriteriaToGradeDTO struct {
+	StudentUUID    string
+	LaboratoryUUID string
+	RubricUUID     string
+	ObjectiveUUID  string
+	CriteriaUUID   string
+}
@@ -0,0 +1,12 @@
+package entities
+
+// GradingRule rule to automatically select a criteria of a rubric objective
+// from the result of the latest submission of a student to a test block
+type GradingRule struct {
+	UUID          string   `json:"uuid"`
+	TestBlockUUID string   `json:"test_block_uuid"`
+	ObjectiveUUID string   `json:"objective_uuid"`
+	CriteriaUUID  string   `json:"criteria_uuid"`
+	Condition     string   `json:"condition"`
+	MinTestsRatio *float64 `json:"min_tests_ratio"`
+}
@@ -34,3 +34,26 @@ func (err UserCannotReadGradeError) Error() string {
 func (err UserCannotReadGradeError) StatusCode() int {
 	return http.StatusForbidden
 }
+
+// TestBlockDoesNotBelongToLaboratoryError error to be thrown when a teacher tries to create a grading rule
+// with a test block from another laboratory
+type TestBlockDoesNotBelongToLaboratoryError struct{}
+
+func (err TestBlockDoesNotBelongToLaboratoryError) Error() string {
+	return "The test block does not belong to the laboratory"
+}
+
+func (err TestBlockDoesNotBelongToLaboratoryError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// GradingRuleNotFoundError error to be thrown when a grading rule is not found in a laboratory
+type GradingRuleNotFoundError struct{}
+
+func (err GradingRuleNotFoundError) Error() string {
+	return "The grading rule was not found in the laboratory"
+}
+
+func (err GradingRuleNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
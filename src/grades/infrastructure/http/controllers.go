@@ -185,3 +185,111 @@ func (controller *GradesController) HandleSetCommentToGrade(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// HandleCreateGradingRule controller to link a criteria of a rubric objective to the result of a test block
+func (controller *GradesController) HandleCreateGradingRule(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratoryUUID")
+
+	// Validate laboratory UUID
+	if err := sharedInfrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Parse the request body
+	var request requests.CreateGradingRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Request body is not valid",
+		})
+		return
+	}
+
+	// Validate the request body
+	if err := sharedInfrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Create the rule
+	rule, err := controller.UseCases.CreateGradingRule(&dtos.CreateGradingRuleDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+		TestBlockUUID:  request.TestBlockUUID,
+		ObjectiveUUID:  request.ObjectiveUUID,
+		CriteriaUUID:   request.CriteriaUUID,
+		Condition:      request.Condition,
+		MinTestsRatio:  request.MinTestsRatio,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// HandleGetGradingRulesInLaboratory controller to get the grading rules of a laboratory
+func (controller *GradesController) HandleGetGradingRulesInLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratoryUUID")
+
+	// Validate laboratory UUID
+	if err := sharedInfrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Get the rules
+	rules, err := controller.UseCases.GetGradingRulesInLaboratory(&dtos.GetGradingRulesInLaboratoryDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+	})
+}
+
+// HandleDeleteGradingRule controller to delete a grading rule of a laboratory
+func (controller *GradesController) HandleDeleteGradingRule(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratoryUUID")
+	ruleUUID := c.Param("ruleUUID")
+
+	// Validate UUIDs
+	uuids := []string{laboratoryUUID, ruleUUID}
+	for _, uuid := range uuids {
+		if err := sharedInfrastructure.GetValidator().Var(uuid, "uuid4"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "Please, make sure you are sending valid UUIDs",
+			})
+			return
+		}
+	}
+
+	// Delete the rule
+	err := controller.UseCases.DeleteGradingRule(&dtos.DeleteGradingRuleDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+		RuleUUID:       ruleUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -49,4 +49,25 @@ func StartGradesRoutes(g *gin.RouterGroup) {
 		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
 		controller.HandleSetCommentToGrade,
 	)
+
+	gradesGroup.GET(
+		"/laboratories/:laboratoryUUID/rules",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleGetGradingRulesInLaboratory,
+	)
+
+	gradesGroup.POST(
+		"/laboratories/:laboratoryUUID/rules",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleCreateGradingRule,
+	)
+
+	gradesGroup.DELETE(
+		"/laboratories/:laboratoryUUID/rules/:ruleUUID",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleDeleteGradingRule,
+	)
 }
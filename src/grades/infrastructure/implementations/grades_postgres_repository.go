@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/UPB-Code-Labs/main-api/src/grades/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/grades/domain/entities"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 )
 
@@ -28,13 +29,14 @@ func GetGradesPostgresRepositoryInstance() *GradesPostgresRepository {
 }
 
 // GetStudentsGradesInLaboratory returns the grades of the students in a laboratory
-// that were graded using the current rubric of the laboratory by the teacher
+// that were graded using the current rubric of the laboratory by the teacher, along with
+// the maximum lateness of their latest submissions
 func (repository *GradesPostgresRepository) GetStudentsGradesInLaboratory(laboratoryUUID, rubricUUID string) ([]*dtos.SummarizedStudentGradeDTO, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
 	query := `
-		SELECT student_id, student_full_name, total_criteria_weight
+		SELECT student_id, student_full_name, total_criteria_weight, late_minutes
 		FROM summarized_grades
 		WHERE laboratory_id = $1 AND rubric_id = $2
 	`
@@ -53,10 +55,13 @@ func (repository *GradesPostgresRepository) GetStudentsGradesInLaboratory(labora
 		if err := rows.Scan(
 			&studentGrade.StudentUUID,
 			&studentGrade.StudentFullName,
-			&studentGrade.Grade); err != nil {
+			&studentGrade.RawGrade,
+			&studentGrade.LateMinutes); err != nil {
 			return nil, err
 		}
 
+		studentGrade.Grade = studentGrade.RawGrade
+
 		summarizedGrades = append(summarizedGrades, &studentGrade)
 	}
 
@@ -68,8 +73,8 @@ func (repository *GradesPostgresRepository) SetCriteriaToGrade(dto *dtos.SetCrit
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
-	// Check if the student has a grade in the laboratory
-	studentHasGrade, err := repository.doesStudentHasGrade(&dtos.CheckIfStudentHasGradeDTO{
+	// Get the UUID of the grade of the student in the laboratory with the given rubric
+	studentGradeUUID, err := repository.getOrCreateStudentGradeUUID(&dtos.CheckIfStudentHasGradeDTO{
 		StudentUUID:    dto.StudentUUID,
 		LaboratoryUUID: dto.LaboratoryUUID,
 		RubricUUID:     dto.RubricUUID,
@@ -78,44 +83,14 @@ func (repository *GradesPostgresRepository) SetCriteriaToGrade(dto *dtos.SetCrit
 		return err
 	}
 
-	// Get the UUID of the grade of the student in the laboratory with the given rubric
-	studentGradeUUID := ""
-
-	if !studentHasGrade {
-		// Create a grade for the student if they do not have one
-		studentGradeUUID, err = repository.createStudentGrade(&dtos.CreateStudentGradeDTO{
-			CheckIfStudentHasGradeDTO: dtos.CheckIfStudentHasGradeDTO{
-				StudentUUID:    dto.StudentUUID,
-				LaboratoryUUID: dto.LaboratoryUUID,
-				RubricUUID:     dto.RubricUUID,
-			},
-		})
-
-		if err != nil {
-			return err
-		}
-	} else {
-		// Get the UUID of the grade of the student in the laboratory with the given rubric
-		studentGradeUUID, err = repository.getStudentGradeUUID(&dtos.GetStudentGradeDTO{
-			CheckIfStudentHasGradeDTO: dtos.CheckIfStudentHasGradeDTO{
-				StudentUUID:    dto.StudentUUID,
-				LaboratoryUUID: dto.LaboratoryUUID,
-				RubricUUID:     dto.RubricUUID,
-			},
-		})
-
-		if err != nil {
-			return err
-		}
-	}
-
-	// UPSERT the criteria to the grade
+	// UPSERT the criteria to the grade. Criteria selected by the teacher override the automatic ones
 	query := `
-		INSERT INTO grade_has_criteria (grade_id, criteria_id, objective_id)	
-		VALUES ($1, $2, $3)
+		INSERT INTO grade_has_criteria (grade_id, criteria_id, objective_id, is_automatic)
+		VALUES ($1, $2, $3, FALSE)
 		ON CONFLICT (grade_id, objective_id) DO
 		UPDATE SET 
-			criteria_id = $2
+			criteria_id = $2,
+			is_automatic = FALSE
 	`
 
 	// Run the query
@@ -132,35 +107,25 @@ func (repository *GradesPostgresRepository) SetCriteriaToGrade(dto *dtos.SetCrit
 	return nil
 }
 
-// doesStudentHasGrade checks if a student has a grade in a laboratory
-func (repository *GradesPostgresRepository) doesStudentHasGrade(dto *dtos.CheckIfStudentHasGradeDTO) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-	defer cancel()
-
-	query := `
-		SELECT EXISTS (
-			SELECT 1
-			FROM grades
-			WHERE student_id = $1 AND laboratory_id = $2 AND rubric_id = $3
-		)
-	`
-
-	// Run the query
-	row := repository.Connection.QueryRowContext(
-		ctx,
-		query,
-		dto.StudentUUID,
-		dto.LaboratoryUUID,
-		dto.RubricUUID,
-	)
+// getOrCreateStudentGradeUUID returns the UUID of the grade of a student in a laboratory
+// with the given rubric, creating it if the student does not have one
+func (repository *GradesPostgresRepository) getOrCreateStudentGradeUUID(dto *dtos.CheckIfStudentHasGradeDTO) (string, error) {
+	// Create a grade for the student if they do not have one. The grade may be created at the same time
+	// by another request (e.g. the teacher and the automatic grading), so conflicts are ignored
+	gradeUUID, err := repository.createStudentGrade(&dtos.CreateStudentGradeDTO{
+		CheckIfStudentHasGradeDTO: *dto,
+	})
+	if err != nil {
+		return "", err
+	}
 
-	// Parse the result
-	var studentHasGrade bool
-	if err := row.Scan(&studentHasGrade); err != nil {
-		return false, err
+	if gradeUUID != "" {
+		return gradeUUID, nil
 	}
 
-	return studentHasGrade, nil
+	return repository.getStudentGradeUUID(&dtos.GetStudentGradeDTO{
+		CheckIfStudentHasGradeDTO: *dto,
+	})
 }
 
 // getStudentGrade returns the grade of a student in a laboratory
@@ -192,7 +157,8 @@ func (repository *GradesPostgresRepository) getStudentGradeUUID(dto *dtos.GetStu
 	return gradeUUID, nil
 }
 
-// createStudentGrade creates a grade for a student in a laboratory
+// createStudentGrade creates a grade for a student in a laboratory. Returns an empty UUID if the
+// student already has a grade
 func (repository *GradesPostgresRepository) createStudentGrade(dto *dtos.CreateStudentGradeDTO) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
@@ -200,6 +166,7 @@ func (repository *GradesPostgresRepository) createStudentGrade(dto *dtos.CreateS
 	query := `
 		INSERT INTO grades (student_id, laboratory_id, rubric_id)
 		VALUES ($1, $2, $3)
+		ON CONFLICT (laboratory_id, rubric_id, student_id) DO NOTHING
 		RETURNING id
 	`
 
@@ -215,6 +182,10 @@ func (repository *GradesPostgresRepository) createStudentGrade(dto *dtos.CreateS
 	// Parse the result
 	var gradeUUID string
 	if err := row.Scan(&gradeUUID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+
 		return "", err
 	}
 
@@ -227,9 +198,9 @@ func (repository *GradesPostgresRepository) GetStudentGradeInLaboratoryWithRubri
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
-	// Get the grade and comment
+	// Get the grade, the lateness and the comment
 	query := `
-		SELECT grade_id, total_criteria_weight, comment
+		SELECT grade_id, total_criteria_weight, late_minutes, comment
 		FROM summarized_grades
 		WHERE student_id = $1 AND laboratory_id = $2 AND rubric_id = $3
 	`
@@ -245,7 +216,7 @@ func (repository *GradesPostgresRepository) GetStudentGradeInLaboratoryWithRubri
 	var gradeUUID string
 	grade := &dtos.StudentGradeInLaboratoryWithRubricDTO{}
 
-	if err := row.Scan(&gradeUUID, &grade.Grade, &grade.Comment); err != nil {
+	if err := row.Scan(&gradeUUID, &grade.RawGrade, &grade.LateMinutes, &grade.Comment); err != nil {
 		// If the student does not have a grade, return the zero-valued grade
 		if err == sql.ErrNoRows {
 			grade.SelectedCriteria = []*dtos.SelectedCriteriaInStudentGradeDTO{}
@@ -257,7 +228,7 @@ func (repository *GradesPostgresRepository) GetStudentGradeInLaboratoryWithRubri
 
 	// Get the selected criteria
 	query = `
-		SELECT criteria_id, objective_id
+		SELECT criteria_id, objective_id, is_automatic
 		FROM grade_has_criteria
 		WHERE grade_id = $1
 	`
@@ -270,13 +241,14 @@ func (repository *GradesPostgresRepository) GetStudentGradeInLaboratoryWithRubri
 	for rows.Next() {
 		var selectedCriteria dtos.SelectedCriteriaInStudentGradeDTO
 
-		if err := rows.Scan(&selectedCriteria.CriteriaUUID, &selectedCriteria.ObjectiveUUID); err != nil {
+		if err := rows.Scan(&selectedCriteria.CriteriaUUID, &selectedCriteria.ObjectiveUUID, &selectedCriteria.IsAutomatic); err != nil {
 			return nil, err
 		}
 
 		grade.SelectedCriteria = append(grade.SelectedCriteria, &selectedCriteria)
 	}
 
+	grade.Grade = grade.RawGrade
 	return grade, nil
 }
 
@@ -285,8 +257,8 @@ func (repository *GradesPostgresRepository) SetCommentToGrade(dto *dtos.SetComme
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
-	// Check if the student has a grade in the laboratory
-	studentHasGrade, err := repository.doesStudentHasGrade(&dtos.CheckIfStudentHasGradeDTO{
+	// Get the UUID of the grade of the student in the laboratory with the given rubric
+	studentGradeUUID, err := repository.getOrCreateStudentGradeUUID(&dtos.CheckIfStudentHasGradeDTO{
 		StudentUUID:    dto.StudentUUID,
 		LaboratoryUUID: dto.LaboratoryUUID,
 		RubricUUID:     dto.RubricUUID,
@@ -295,37 +267,6 @@ func (repository *GradesPostgresRepository) SetCommentToGrade(dto *dtos.SetComme
 		return err
 	}
 
-	// Get the UUID of the grade of the student in the laboratory with the given rubric
-	studentGradeUUID := ""
-
-	if !studentHasGrade {
-		// Create a grade for the student if they do not have one
-		studentGradeUUID, err = repository.createStudentGrade(&dtos.CreateStudentGradeDTO{
-			CheckIfStudentHasGradeDTO: dtos.CheckIfStudentHasGradeDTO{
-				StudentUUID:    dto.StudentUUID,
-				LaboratoryUUID: dto.LaboratoryUUID,
-				RubricUUID:     dto.RubricUUID,
-			},
-		})
-
-		if err != nil {
-			return err
-		}
-	} else {
-		// Get the UUID of the grade of the student in the laboratory with the given rubric
-		studentGradeUUID, err = repository.getStudentGradeUUID(&dtos.GetStudentGradeDTO{
-			CheckIfStudentHasGradeDTO: dtos.CheckIfStudentHasGradeDTO{
-				StudentUUID:    dto.StudentUUID,
-				LaboratoryUUID: dto.LaboratoryUUID,
-				RubricUUID:     dto.RubricUUID,
-			},
-		})
-
-		if err != nil {
-			return err
-		}
-	}
-
 	// Set the comment to the grade
 	query := `
 		UPDATE grades
@@ -345,3 +286,322 @@ func (repository *GradesPostgresRepository) SetCommentToGrade(dto *dtos.SetComme
 
 	return nil
 }
+
+// DoesLaboratoryHaveTestBlock checks if a test block belongs to a laboratory
+func (repository *GradesPostgresRepository) DoesLaboratoryHaveTestBlock(laboratoryUUID, testBlockUUID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM test_blocks
+			WHERE id = $1 AND laboratory_id = $2
+		)
+	`
+
+	// Run the query
+	row := repository.Connection.QueryRowContext(ctx, query, testBlockUUID, laboratoryUUID)
+
+	// Parse the result
+	var laboratoryHasTestBlock bool
+	if err := row.Scan(&laboratoryHasTestBlock); err != nil {
+		return false, err
+	}
+
+	return laboratoryHasTestBlock, nil
+}
+
+// SaveGradingRule saves a rule to automatically select a criteria from the result of a test block
+func (repository *GradesPostgresRepository) SaveGradingRule(dto *dtos.CreateGradingRuleDTO) (*entities.GradingRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		INSERT INTO grading_rules (test_block_id, objective_id, criteria_id, condition, min_tests_ratio)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	// Only the `min_tests_ratio` condition uses the ratio
+	var minTestsRatio *float64
+	if dto.Condition == "min_tests_ratio" {
+		minTestsRatio = dto.MinTestsRatio
+	}
+
+	// Run the query
+	row := repository.Connection.QueryRowContext(
+		ctx,
+		query,
+		dto.TestBlockUUID,
+		dto.ObjectiveUUID,
+		dto.CriteriaUUID,
+		dto.Condition,
+		minTestsRatio,
+	)
+
+	// Parse the result
+	rule := &entities.GradingRule{
+		TestBlockUUID: dto.TestBlockUUID,
+		ObjectiveUUID: dto.ObjectiveUUID,
+		CriteriaUUID:  dto.CriteriaUUID,
+		Condition:     dto.Condition,
+		MinTestsRatio: minTestsRatio,
+	}
+
+	if err := row.Scan(&rule.UUID); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// GetGradingRulesInLaboratory returns the grading rules of the test blocks of a laboratory
+func (repository *GradesPostgresRepository) GetGradingRulesInLaboratory(laboratoryUUID string) ([]*entities.GradingRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT gr.id, gr.test_block_id, gr.objective_id, gr.criteria_id, gr.condition, gr.min_tests_ratio
+		FROM grading_rules AS gr
+		INNER JOIN test_blocks AS tb ON gr.test_block_id = tb.id
+		WHERE tb.laboratory_id = $1
+		ORDER BY gr.created_at ASC
+	`
+
+	// Run the query
+	rows, err := repository.Connection.QueryContext(ctx, query, laboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Parse the results
+	rules := []*entities.GradingRule{}
+	for rows.Next() {
+		var rule entities.GradingRule
+
+		if err := rows.Scan(
+			&rule.UUID,
+			&rule.TestBlockUUID,
+			&rule.ObjectiveUUID,
+			&rule.CriteriaUUID,
+			&rule.Condition,
+			&rule.MinTestsRatio,
+		); err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+// DoesLaboratoryHaveGradingRule checks if a grading rule belongs to a test block of a laboratory
+func (repository *GradesPostgresRepository) DoesLaboratoryHaveGradingRule(laboratoryUUID, ruleUUID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM grading_rules AS gr
+			INNER JOIN test_blocks AS tb ON gr.test_block_id = tb.id
+			WHERE gr.id = $1 AND tb.laboratory_id = $2
+		)
+	`
+
+	// Run the query
+	row := repository.Connection.QueryRowContext(ctx, query, ruleUUID, laboratoryUUID)
+
+	// Parse the result
+	var laboratoryHasRule bool
+	if err := row.Scan(&laboratoryHasRule); err != nil {
+		return false, err
+	}
+
+	return laboratoryHasRule, nil
+}
+
+// DeleteGradingRule deletes a grading rule
+func (repository *GradesPostgresRepository) DeleteGradingRule(ruleUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		DELETE FROM grading_rules
+		WHERE id = $1
+	`
+
+	// Run the query
+	_, err := repository.Connection.ExecContext(ctx, query, ruleUUID)
+	return err
+}
+
+// GetSubmissionGradingContext returns the student, test block, laboratory and current rubric
+// of the laboratory a submission belongs to
+func (repository *GradesPostgresRepository) GetSubmissionGradingContext(submissionUUID string) (*dtos.SubmissionGradingContextDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT s.id, s.student_id, s.test_block_id, l.id, l.rubric_id
+		FROM submissions AS s
+		INNER JOIN test_blocks AS tb ON s.test_block_id = tb.id
+		INNER JOIN laboratories AS l ON tb.laboratory_id = l.id
+		WHERE s.id = $1
+	`
+
+	// Run the query
+	row := repository.Connection.QueryRowContext(ctx, query, submissionUUID)
+
+	// Parse the result
+	gradingContext := &dtos.SubmissionGradingContextDTO{}
+	if err := row.Scan(
+		&gradingContext.SubmissionUUID,
+		&gradingContext.StudentUUID,
+		&gradingContext.TestBlockUUID,
+		&gradingContext.LaboratoryUUID,
+		&gradingContext.RubricUUID,
+	); err != nil {
+		return nil, err
+	}
+
+	return gradingContext, nil
+}
+
+// GetGradingRulesLinkedToTestBlock returns all the grading rules in the laboratory of the objectives
+// (of the given rubric) that are linked to the given test block, including the rules linked to other
+// test blocks of the laboratory
+func (repository *GradesPostgresRepository) GetGradingRulesLinkedToTestBlock(laboratoryUUID, rubricUUID, testBlockUUID string) ([]*dtos.WeightedGradingRuleDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT gr.id, gr.test_block_id, gr.objective_id, gr.criteria_id, gr.condition, gr.min_tests_ratio, c.weight
+		FROM grading_rules AS gr
+		INNER JOIN criteria AS c ON gr.criteria_id = c.id
+		INNER JOIN objectives AS o ON gr.objective_id = o.id
+		INNER JOIN test_blocks AS tb ON gr.test_block_id = tb.id
+		WHERE tb.laboratory_id = $1 AND o.rubric_id = $2 AND gr.objective_id IN (
+			SELECT objective_id
+			FROM grading_rules
+			WHERE test_block_id = $3
+		)
+	`
+
+	// Run the query
+	rows, err := repository.Connection.QueryContext(ctx, query, laboratoryUUID, rubricUUID, testBlockUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Parse the results
+	rules := []*dtos.WeightedGradingRuleDTO{}
+	for rows.Next() {
+		var rule dtos.WeightedGradingRuleDTO
+
+		if err := rows.Scan(
+			&rule.UUID,
+			&rule.TestBlockUUID,
+			&rule.ObjectiveUUID,
+			&rule.CriteriaUUID,
+			&rule.Condition,
+			&rule.MinTestsRatio,
+			&rule.CriteriaWeight,
+		); err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+// GetStudentTestBlocksResults returns the result of the latest submission of a student
+// to each test block of a laboratory
+func (repository *GradesPostgresRepository) GetStudentTestBlocksResults(studentUUID, laboratoryUUID string) ([]*dtos.StudentTestBlockResultDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT
+			s.test_block_id,
+			s.status = 'ready',
+			s.passing,
+			COUNT(str.id),
+			COUNT(str.id) FILTER (WHERE str.status = 'passed')
+		FROM latest_submissions AS s
+		INNER JOIN test_blocks AS tb ON s.test_block_id = tb.id
+		LEFT JOIN submission_test_results AS str ON str.submission_id = s.id
+		WHERE s.student_id = $1 AND tb.laboratory_id = $2
+		GROUP BY s.test_block_id, s.status, s.passing
+	`
+
+	// Run the query
+	rows, err := repository.Connection.QueryContext(ctx, query, studentUUID, laboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Parse the results
+	results := []*dtos.StudentTestBlockResultDTO{}
+	for rows.Next() {
+		var result dtos.StudentTestBlockResultDTO
+
+		if err := rows.Scan(
+			&result.TestBlockUUID,
+			&result.IsReady,
+			&result.IsPassing,
+			&result.TotalTests,
+			&result.PassedTests,
+		); err != nil {
+			return nil, err
+		}
+
+		results = append(results, &result)
+	}
+
+	return results, nil
+}
+
+// SetAutomaticCriteriaToGrade sets a criteria selected by the automatic grading to a student's grade.
+// Criteria previously selected by the teacher are not overridden
+func (repository *GradesPostgresRepository) SetAutomaticCriteriaToGrade(dto *dtos.SetAutomaticCriteriaToGradeDTO) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	// Get the UUID of the grade of the student in the laboratory with the given rubric
+	studentGradeUUID, err := repository.getOrCreateStudentGradeUUID(&dtos.CheckIfStudentHasGradeDTO{
+		StudentUUID:    dto.StudentUUID,
+		LaboratoryUUID: dto.LaboratoryUUID,
+		RubricUUID:     dto.RubricUUID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// UPSERT the criteria to the grade only if it was not selected by the teacher
+	query := `
+		INSERT INTO grade_has_criteria (grade_id, criteria_id, objective_id, is_automatic)
+		VALUES ($1, $2, $3, TRUE)
+		ON CONFLICT (grade_id, objective_id) DO
+		UPDATE SET
+			criteria_id = $2
+		WHERE grade_has_criteria.is_automatic = TRUE
+	`
+
+	// Run the query
+	_, err = repository.Connection.ExecContext(
+		ctx,
+		query,
+		studentGradeUUID,
+		dto.CriteriaUUID,
+		dto.ObjectiveUUID,
+	)
+	return err
+}
@@ -18,3 +18,12 @@ type GetStudentGradeInLaboratoryWithRubricRequest struct {
 type SetCommentToGradeRequest struct {
 	Comment string `json:"comment" validate:"required,min=8,max=510"`
 }
+
+// CreateGradingRuleRequest request to link a criteria of a rubric objective to the result of a test block
+type CreateGradingRuleRequest struct {
+	TestBlockUUID string   `json:"test_block_uuid" validate:"required,uuid4"`
+	ObjectiveUUID string   `json:"objective_uuid" validate:"required,uuid4"`
+	CriteriaUUID  string   `json:"criteria_uuid" validate:"required,uuid4"`
+	Condition     string   `json:"condition" validate:"required,oneof=passing failing min_tests_ratio"`
+	MinTestsRatio *float64 `json:"min_tests_ratio" validate:"required_if=Condition min_tests_ratio,omitempty,min=0,max=1"`
+}
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
+	rubricsEntities "github.com/UPB-Code-Labs/main-api/src/rubrics/domain/entities"
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+)
+
+// Folder of the bundle where the test archives are placed
+const laboratoryBundleTestsFolder = "tests"
+
+// getLaboratoryBundleTestArchivePath returns the `tests/<position>_<test_block_name>.zip` path of the test
+// archive of a block. The position keeps the paths of blocks with the same name apart
+func getLaboratoryBundleTestArchivePath(position int, testBlockName string) string {
+	return path.Join(
+		laboratoryBundleTestsFolder,
+		fmt.Sprintf("%d_%s.zip", position, sanitizeArchivePathComponent(testBlockName)),
+	)
+}
+
+// newLaboratoryBundleRubric returns the rubric without its UUIDs, so it can be added to a bundle
+func newLaboratoryBundleRubric(rubric *rubricsEntities.Rubric) *entities.LaboratoryBundleRubric {
+	bundleRubric := &entities.LaboratoryBundleRubric{
+		Name:       rubric.Name,
+		Objectives: []entities.LaboratoryBundleRubricObjective{},
+	}
+
+	for _, objective := range rubric.Objectives {
+		bundleObjective := entities.LaboratoryBundleRubricObjective{
+			Description: objective.Description,
+			Criteria:    []entities.LaboratoryBundleRubricCriteria{},
+		}
+
+		for _, criteria := range objective.Criteria {
+			bundleObjective.Criteria = append(bundleObjective.Criteria, entities.LaboratoryBundleRubricCriteria{
+				Description: criteria.Description,
+				Weight:      float64(criteria.Weight),
+			})
+		}
+
+		bundleRubric.Objectives = append(bundleRubric.Objectives, bundleObjective)
+	}
+
+	return bundleRubric
+}
+
+// getBundleExecutionLimits returns nil if the test block does not override any limit, so the limits are omitted
+// from the manifest
+func getBundleExecutionLimits(limits *sharedEntities.ExecutionLimitsOverrides) *sharedEntities.ExecutionLimitsOverrides {
+	if limits == nil {
+		return nil
+	}
+
+	if limits.CPUTimeMs == nil && limits.WallTimeMs == nil && limits.MemoryMb == nil && limits.OutputSizeKb == nil {
+		return nil
+	}
+
+	return limits
+}
+
+// readLaboratoryBundleTestArchive returns the content of a test archive of the bundle
+func readLaboratoryBundleTestArchive(bundle *zip.Reader, archivePath string, maxSizeKb int64) ([]byte, error) {
+	file, err := bundle.Open(archivePath)
+	if err != nil {
+		return nil, laboratoriesErrors.InvalidLaboratoryBundleError{
+			Reason: fmt.Sprintf("the test archive %s is missing", archivePath),
+		}
+	}
+	defer file.Close()
+
+	tooLargeError := laboratoriesErrors.InvalidLaboratoryBundleError{
+		Reason: fmt.Sprintf("the test archive %s must be less than %d KB", archivePath, maxSizeKb),
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() > maxSizeKb*1024 {
+		return nil, tooLargeError
+	}
+
+	// Do not trust the size declared in the bundle
+	content, err := io.ReadAll(io.LimitReader(file, maxSizeKb*1024+1))
+	if err != nil {
+		return nil, laboratoriesErrors.InvalidLaboratoryBundleError{
+			Reason: fmt.Sprintf("the test archive %s can not be read", archivePath),
+		}
+	}
+
+	if int64(len(content)) > maxSizeKb*1024 {
+		return nil, tooLargeError
+	}
+
+	return content, nil
+}
+
+// laboratoryBundleArchiveFile test archive of a bundle, read as the archives uploaded by the teachers
+type laboratoryBundleArchiveFile struct {
+	*bytes.Reader
+}
+
+func (file laboratoryBundleArchiveFile) Close() error {
+	return nil
+}
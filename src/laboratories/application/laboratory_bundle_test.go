@@ -0,0 +1,397 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	coursesErrors "github.com/UPB-Code-Labs/main-api/src/courses/domain/errors"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
+	languagesDefinitions "github.com/UPB-Code-Labs/main-api/src/languages/domain/definitions"
+	languagesEntities "github.com/UPB-Code-Labs/main-api/src/languages/domain/entities"
+	languagesErrors "github.com/UPB-Code-Labs/main-api/src/languages/domain/errors"
+	rubricsDefinitions "github.com/UPB-Code-Labs/main-api/src/rubrics/domain/definitions"
+	rubricsEntities "github.com/UPB-Code-Labs/main-api/src/rubrics/domain/entities"
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+)
+
+type languagesRepositoryStub struct {
+	languagesDefinitions.LanguagesRepository
+
+	languages map[string]*languagesEntities.Language
+}
+
+func (stub *languagesRepositoryStub) GetByName(name string) (*languagesEntities.Language, error) {
+	language, exists := stub.languages[name]
+	if !exists {
+		return nil, &languagesErrors.LangNotFoundError{}
+	}
+
+	return language, nil
+}
+
+type rubricsRepositoryStub struct {
+	rubricsDefinitions.RubricsRepository
+
+	rubric *rubricsEntities.Rubric
+}
+
+func (stub *rubricsRepositoryStub) GetByUUID(uuid string) (*rubricsEntities.Rubric, error) {
+	return stub.rubric, nil
+}
+
+// archivesValidatorStub rejects every archive if it has an error
+type archivesValidatorStub struct {
+	err error
+}
+
+func (stub *archivesValidatorStub) ValidateArchive(dto *staticFilesDTOs.ValidateArchiveDTO) error {
+	return stub.err
+}
+
+// bundleRepositoryStub owns a single laboratory with the blocks of its bundle and records the imports
+type bundleRepositoryStub struct {
+	definitions.LaboratoriesRepository
+
+	laboratory   *dtos.LaboratoryDetailsDTO
+	bundleBlocks []*dtos.LaboratoryBundleBlockDTO
+	savedImports []*dtos.SaveLaboratoryImportDTO
+	importError  error
+}
+
+func (stub *bundleRepositoryStub) DoesTeacherOwnLaboratory(teacherUUID string, laboratoryUUID string) (bool, error) {
+	return teacherUUID == "teacher", nil
+}
+
+func (stub *bundleRepositoryStub) GetLaboratoryInformationByUUID(uuid string) (*dtos.LaboratoryDetailsDTO, error) {
+	return stub.laboratory, nil
+}
+
+func (stub *bundleRepositoryStub) GetLaboratoryBundleBlocks(laboratoryUUID string) ([]*dtos.LaboratoryBundleBlockDTO, error) {
+	return stub.bundleBlocks, nil
+}
+
+func (stub *bundleRepositoryStub) SaveLaboratoryImport(dto *dtos.SaveLaboratoryImportDTO) (string, error) {
+	if stub.importError != nil {
+		return "", stub.importError
+	}
+
+	stub.savedImports = append(stub.savedImports, dto)
+	return "imported", nil
+}
+
+func (stub *bundleRepositoryStub) GetLaboratoryByUUID(dto *dtos.GetLaboratoryDTO) (*entities.Laboratory, error) {
+	return &entities.Laboratory{UUID: dto.LaboratoryUUID}, nil
+}
+
+func newLaboratoryBundleUseCases(t *testing.T) (*LaboratoriesUseCases, *bundleRepositoryStub, *staticFilesRepositoryStub) {
+	rubricUUID := "rubric"
+	hour := "hour"
+	cpuTimeMs := 2000
+
+	laboratoriesRepository := &bundleRepositoryStub{
+		laboratory: &dtos.LaboratoryDetailsDTO{
+			UUID:        "laboratory",
+			CourseUUID:  "course",
+			RubricUUID:  &rubricUUID,
+			Name:        "Linked lists",
+			OpeningDate: "2026-10-01T08:00:00Z",
+			DueDate:     "2026-10-08T08:00:00Z",
+			LatePolicy:  entities.LatePolicy{Policy: "late_window", WindowMinutes: 600, PenaltyPercentage: 10, PenaltyUnit: &hour},
+		},
+		bundleBlocks: []*dtos.LaboratoryBundleBlockDTO{
+			{Type: "markdown", MarkdownContent: "# Linked lists"},
+			{
+				Type:            "test",
+				TestBlockName:   "Unit tests",
+				LanguageName:    "Java JDK 17",
+				ExecutionLimits: &sharedEntities.ExecutionLimitsOverrides{CPUTimeMs: &cpuTimeMs},
+				TestArchiveUUID: "first-tests",
+			},
+			{
+				Type:            "test",
+				TestBlockName:   "Unit tests",
+				LanguageName:    "Java JDK 17",
+				ExecutionLimits: &sharedEntities.ExecutionLimitsOverrides{},
+				TestArchiveUUID: "second-tests",
+			},
+		},
+	}
+
+	staticFilesRepository := &staticFilesRepositoryStub{
+		archives: map[string][]byte{
+			"first-tests":  createTestZip(t, map[string]string{"src/test/java/FirstTest.java": "class FirstTest {}"}),
+			"second-tests": createTestZip(t, map[string]string{"src/test/java/SecondTest.java": "class SecondTest {}"}),
+		},
+	}
+
+	useCases := &LaboratoriesUseCases{
+		LaboratoriesRepository: laboratoriesRepository,
+		StaticFilesRepository:  staticFilesRepository,
+		ArchivesValidator:      &archivesValidatorStub{},
+		CoursesRepository: &coursesRepositoryStub{
+			ownedCourses: map[string]bool{"another course": true},
+		},
+		LanguagesRepository: &languagesRepositoryStub{
+			languages: map[string]*languagesEntities.Language{
+				"Java JDK 17": {UUID: "java"},
+				"Python 2":    {UUID: "python", IsDeprecated: true},
+			},
+		},
+		RubricsRepository: &rubricsRepositoryStub{
+			rubric: &rubricsEntities.Rubric{
+				UUID: rubricUUID,
+				Name: "Data structures",
+				Objectives: []rubricsEntities.RubricObjective{
+					{
+						Description: "Implements the operations",
+						Criteria: []rubricsEntities.RubricObjectiveCriteria{
+							{Description: "Every operation works", Weight: 5},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return useCases, laboratoriesRepository, staticFilesRepository
+}
+
+// exportTestBundle returns the bundle of the laboratory of `newLaboratoryBundleUseCases`
+func exportTestBundle(t *testing.T, useCases *LaboratoriesUseCases) []byte {
+	t.Helper()
+
+	bundle, err := useCases.GetLaboratoryBundle(&dtos.ExportLaboratoryDTO{
+		TeacherUUID:    "teacher",
+		LaboratoryUUID: "laboratory",
+		IncludeRubric:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := useCases.WriteLaboratoryBundle(bundle, buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func newImportTestDTO(t *testing.T, bundleBytes []byte, editManifest func(*entities.LaboratoryBundleManifest)) *dtos.ImportLaboratoryDTO {
+	t.Helper()
+
+	bundle, err := zip.NewReader(bytes.NewReader(bundleBytes), int64(len(bundleBytes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifestFile, err := bundle.Open(entities.LaboratoryBundleManifestName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manifestFile.Close()
+
+	manifest := &entities.LaboratoryBundleManifest{}
+	if err := json.NewDecoder(manifestFile).Decode(manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	if editManifest != nil {
+		editManifest(manifest)
+	}
+
+	return &dtos.ImportLaboratoryDTO{
+		TeacherUUID:      "teacher",
+		CourseUUID:       "another course",
+		OpeningDate:      *parseTestDate(t, "2027-03-01T08:00:00Z"),
+		Manifest:         manifest,
+		Bundle:           bundle,
+		ArchiveMaxSizeKb: 1024,
+	}
+}
+
+func TestWriteLaboratoryBundle(t *testing.T) {
+	useCases, _, staticFilesRepository := newLaboratoryBundleUseCases(t)
+
+	files := readTestZip(t, exportTestBundle(t, useCases))
+	if len(files) != 3 {
+		t.Fatalf("expected the manifest and two test archives, got %d files", len(files))
+	}
+
+	// Blocks with the same name do not share their archive
+	expectedArchives := map[string]string{
+		"tests/2_Unit tests.zip": "first-tests",
+		"tests/3_Unit tests.zip": "second-tests",
+	}
+	for archivePath, archiveUUID := range expectedArchives {
+		if files[archivePath] != string(staticFilesRepository.archives[archiveUUID]) {
+			t.Errorf("expected %s to contain the archive %s", archivePath, archiveUUID)
+		}
+	}
+
+	manifest := &entities.LaboratoryBundleManifest{}
+	if err := json.Unmarshal([]byte(files[entities.LaboratoryBundleManifestName]), manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	if manifest.Version != entities.LaboratoryBundleVersion || manifest.Name != "Linked lists" {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+
+	if manifest.DurationMinutes != 7*24*60 {
+		t.Errorf("expected the duration of the laboratory to be kept, got %d minutes", manifest.DurationMinutes)
+	}
+
+	if len(manifest.Blocks) != 3 || manifest.Blocks[0].Content != "# Linked lists" || manifest.Blocks[1].Language != "Java JDK 17" {
+		t.Fatalf("unexpected blocks %+v", manifest.Blocks)
+	}
+
+	if manifest.Blocks[1].ExecutionLimits == nil || *manifest.Blocks[1].ExecutionLimits.CPUTimeMs != 2000 {
+		t.Errorf("expected the limits of the block to be kept")
+	}
+
+	if manifest.Blocks[2].ExecutionLimits != nil {
+		t.Errorf("expected the limits of a block without overrides to be omitted")
+	}
+
+	if manifest.Rubric == nil || manifest.Rubric.Objectives[0].Criteria[0].Weight != 5 {
+		t.Errorf("expected the rubric to be included, got %+v", manifest.Rubric)
+	}
+}
+
+func TestImportLaboratory(t *testing.T) {
+	useCases, repository, staticFilesRepository := newLaboratoryBundleUseCases(t)
+
+	laboratory, err := useCases.ImportLaboratory(newImportTestDTO(t, exportTestBundle(t, useCases), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if laboratory.UUID != "imported" || len(repository.savedImports) != 1 {
+		t.Fatalf("expected the laboratory to be imported once")
+	}
+
+	saved := repository.savedImports[0]
+	if saved.Name != "Linked lists" || saved.CourseUUID != "another course" || saved.Rubric == nil {
+		t.Errorf("unexpected imported laboratory %+v", saved)
+	}
+
+	if !saved.DueDate.Equal(saved.OpeningDate.Add(7 * 24 * time.Hour)) {
+		t.Errorf("expected the duration to be kept, got %v - %v", saved.OpeningDate, saved.DueDate)
+	}
+
+	if saved.LanguagesUUIDs["Java JDK 17"] != "java" {
+		t.Errorf("expected the language to be found by its name, got %v", saved.LanguagesUUIDs)
+	}
+
+	// Each test block references a new archive with the same content
+	expectedArchives := map[int]string{1: "first-tests", 2: "second-tests"}
+	if len(saved.TestArchivesUUIDs) != len(expectedArchives) {
+		t.Fatalf("expected %d archives to be saved, got %v", len(expectedArchives), saved.TestArchivesUUIDs)
+	}
+
+	for index, originalUUID := range expectedArchives {
+		savedContent := staticFilesRepository.archives[saved.TestArchivesUUIDs[index]]
+		if !bytes.Equal(savedContent, staticFilesRepository.archives[originalUUID]) {
+			t.Errorf("expected the block %d to reference a copy of %s", index, originalUUID)
+		}
+	}
+}
+
+func TestImportLaboratoryRejectsInvalidBundles(t *testing.T) {
+	validatorError := errors.New("forbidden file")
+
+	// Errors of the bundle are only checked by their type, as they describe the reason
+	testCases := []struct {
+		name              string
+		courseUUID        string
+		editManifest      func(*entities.LaboratoryBundleManifest)
+		validatorError    error
+		expectedError     error
+		expectBundleError bool
+	}{
+		{
+			name:          "teacher does not own the course",
+			courseUUID:    "course",
+			expectedError: coursesErrors.TeacherDoesNotOwnsCourseError{},
+		},
+		{
+			name:              "unsupported version",
+			editManifest:      func(manifest *entities.LaboratoryBundleManifest) { manifest.Version = 2 },
+			expectBundleError: true,
+		},
+		{
+			name:              "unknown language",
+			editManifest:      func(manifest *entities.LaboratoryBundleManifest) { manifest.Blocks[2].Language = "Rust" },
+			expectBundleError: true,
+		},
+		{
+			name:              "deprecated language",
+			editManifest:      func(manifest *entities.LaboratoryBundleManifest) { manifest.Blocks[2].Language = "Python 2" },
+			expectBundleError: true,
+		},
+		{
+			name: "missing test archive",
+			editManifest: func(manifest *entities.LaboratoryBundleManifest) {
+				manifest.Blocks[2].TestArchive = "tests/missing.zip"
+			},
+			expectBundleError: true,
+		},
+		{
+			name:           "test archive rejected by the validator",
+			validatorError: validatorError,
+			expectedError:  validatorError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			useCases, repository, staticFilesRepository := newLaboratoryBundleUseCases(t)
+			dto := newImportTestDTO(t, exportTestBundle(t, useCases), testCase.editManifest)
+			if testCase.courseUUID != "" {
+				dto.CourseUUID = testCase.courseUUID
+			}
+			useCases.ArchivesValidator = &archivesValidatorStub{err: testCase.validatorError}
+
+			_, err := useCases.ImportLaboratory(dto)
+			if testCase.expectBundleError {
+				if _, ok := err.(laboratoriesErrors.InvalidLaboratoryBundleError); !ok {
+					t.Errorf("expected the bundle to be invalid, got %v", err)
+				}
+			} else if err != testCase.expectedError {
+				t.Errorf("expected %v, got %v", testCase.expectedError, err)
+			}
+
+			if len(repository.savedImports) != 0 {
+				t.Errorf("expected the laboratory not to be imported")
+			}
+
+			// The archives saved before the bundle was rejected are deleted
+			if len(staticFilesRepository.archives) != 2 {
+				t.Errorf("expected only the original archives to be kept, got %d", len(staticFilesRepository.archives))
+			}
+		})
+	}
+}
+
+func TestImportLaboratoryDeletesTheArchivesOnFailure(t *testing.T) {
+	useCases, repository, staticFilesRepository := newLaboratoryBundleUseCases(t)
+	repository.importError = errors.New("database is down")
+
+	_, err := useCases.ImportLaboratory(newImportTestDTO(t, exportTestBundle(t, useCases), nil))
+	if err == nil {
+		t.Fatal("expected the error of the repository")
+	}
+
+	if len(staticFilesRepository.archives) != 2 {
+		t.Errorf("expected the saved archives to be deleted, got %d archives", len(staticFilesRepository.archives))
+	}
+}
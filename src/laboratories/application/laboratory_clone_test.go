@@ -0,0 +1,156 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	coursesErrors "github.com/UPB-Code-Labs/main-api/src/courses/domain/errors"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+)
+
+// cloneRepositoryStub owns a single laboratory with test blocks and records the saved clones
+type cloneRepositoryStub struct {
+	definitions.LaboratoriesRepository
+
+	laboratory   *dtos.LaboratoryDetailsDTO
+	testArchives []*dtos.TestBlockArchiveDTO
+	savedClones  []*dtos.SaveLaboratoryCloneDTO
+	cloneError   error
+}
+
+func (stub *cloneRepositoryStub) DoesTeacherOwnLaboratory(teacherUUID string, laboratoryUUID string) (bool, error) {
+	return teacherUUID == "teacher", nil
+}
+
+func (stub *cloneRepositoryStub) GetLaboratoryInformationByUUID(uuid string) (*dtos.LaboratoryDetailsDTO, error) {
+	return stub.laboratory, nil
+}
+
+func (stub *cloneRepositoryStub) GetTestBlocksArchives(laboratoryUUID string) ([]*dtos.TestBlockArchiveDTO, error) {
+	return stub.testArchives, nil
+}
+
+func (stub *cloneRepositoryStub) SaveLaboratoryClone(dto *dtos.SaveLaboratoryCloneDTO) (string, error) {
+	if stub.cloneError != nil {
+		return "", stub.cloneError
+	}
+
+	stub.savedClones = append(stub.savedClones, dto)
+	return "clone", nil
+}
+
+func (stub *cloneRepositoryStub) GetLaboratoryByUUID(dto *dtos.GetLaboratoryDTO) (*entities.Laboratory, error) {
+	return &entities.Laboratory{UUID: dto.LaboratoryUUID}, nil
+}
+
+func newCloneLaboratoryUseCases() (*LaboratoriesUseCases, *cloneRepositoryStub, *staticFilesRepositoryStub) {
+	laboratoriesRepository := &cloneRepositoryStub{
+		laboratory: &dtos.LaboratoryDetailsDTO{
+			UUID:        "laboratory",
+			CourseUUID:  "course",
+			Name:        "Linked lists",
+			OpeningDate: "2026-10-01T08:00:00Z",
+			DueDate:     "2026-10-08T08:00:00Z",
+		},
+		testArchives: []*dtos.TestBlockArchiveDTO{
+			{TestBlockUUID: "first block", ArchiveUUID: "first archive"},
+			{TestBlockUUID: "second block", ArchiveUUID: "second archive"},
+		},
+	}
+
+	staticFilesRepository := &staticFilesRepositoryStub{
+		archives: map[string][]byte{
+			"first archive":  []byte("first tests"),
+			"second archive": []byte("second tests"),
+		},
+	}
+
+	useCases := &LaboratoriesUseCases{
+		LaboratoriesRepository: laboratoriesRepository,
+		StaticFilesRepository:  staticFilesRepository,
+		CoursesRepository: &coursesRepositoryStub{
+			ownedCourses: map[string]bool{"course": true, "another section": true},
+		},
+	}
+
+	return useCases, laboratoriesRepository, staticFilesRepository
+}
+
+func TestCloneLaboratory(t *testing.T) {
+	useCases, laboratoriesRepository, staticFilesRepository := newCloneLaboratoryUseCases()
+
+	laboratory, err := useCases.CloneLaboratory(&dtos.CloneLaboratoryDTO{
+		TeacherUUID:    "teacher",
+		LaboratoryUUID: "laboratory",
+		CourseUUID:     "another section",
+		OpeningDate:    *parseTestDate(t, "2027-03-01T08:00:00Z"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if laboratory.UUID != "clone" || len(laboratoriesRepository.savedClones) != 1 {
+		t.Fatalf("expected the clone to be saved once, got %d", len(laboratoriesRepository.savedClones))
+	}
+
+	clone := laboratoriesRepository.savedClones[0]
+	if clone.Name != "Linked lists" || clone.CourseUUID != "another section" {
+		t.Errorf("expected the name to be kept in the target course, got %q in %q", clone.Name, clone.CourseUUID)
+	}
+
+	// The laboratory lasts one week
+	if !clone.DueDate.Equal(*parseTestDate(t, "2027-03-08T08:00:00Z")) {
+		t.Errorf("expected the due date to be shifted, got %v", clone.DueDate)
+	}
+
+	// Each test block references its own copy of the archive
+	for _, archive := range laboratoriesRepository.testArchives {
+		copyUUID := clone.TestArchivesCopies[archive.TestBlockUUID]
+		if copyUUID == "" || copyUUID == archive.ArchiveUUID {
+			t.Errorf("expected the archive of %q to be duplicated, got %q", archive.TestBlockUUID, copyUUID)
+		}
+
+		if string(staticFilesRepository.archives[copyUUID]) != string(staticFilesRepository.archives[archive.ArchiveUUID]) {
+			t.Errorf("expected the copy of %q to have the same content", archive.ArchiveUUID)
+		}
+	}
+}
+
+func TestCloneLaboratoryDeletesTheCopiesOnFailure(t *testing.T) {
+	useCases, laboratoriesRepository, staticFilesRepository := newCloneLaboratoryUseCases()
+	laboratoriesRepository.cloneError = errors.New("unable to save the clone")
+
+	_, err := useCases.CloneLaboratory(&dtos.CloneLaboratoryDTO{
+		TeacherUUID:    "teacher",
+		LaboratoryUUID: "laboratory",
+		CourseUUID:     "another section",
+		OpeningDate:    *parseTestDate(t, "2027-03-01T08:00:00Z"),
+	})
+	if err != laboratoriesRepository.cloneError {
+		t.Fatalf("expected the error of the repository, got %v", err)
+	}
+
+	if len(staticFilesRepository.archives) != 2 {
+		t.Errorf("expected only the original archives to be kept, got %d archives", len(staticFilesRepository.archives))
+	}
+}
+
+func TestCloneLaboratoryRequiresTheTargetCourse(t *testing.T) {
+	useCases, laboratoriesRepository, _ := newCloneLaboratoryUseCases()
+
+	_, err := useCases.CloneLaboratory(&dtos.CloneLaboratoryDTO{
+		TeacherUUID:    "teacher",
+		LaboratoryUUID: "laboratory",
+		CourseUUID:     "course of another teacher",
+		OpeningDate:    *parseTestDate(t, "2027-03-01T08:00:00Z"),
+	})
+	if err != (coursesErrors.TeacherDoesNotOwnsCourseError{}) {
+		t.Errorf("expected a TeacherDoesNotOwnsCourseError, got %v", err)
+	}
+
+	if len(laboratoriesRepository.savedClones) != 0 {
+		t.Error("expected the clone not to be saved")
+	}
+}
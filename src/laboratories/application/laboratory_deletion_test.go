@@ -0,0 +1,85 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
+)
+
+// deletionRepositoryStub owns a single laboratory and records whether it is deleted
+type deletionRepositoryStub struct {
+	definitions.LaboratoriesRepository
+
+	isDeleted bool
+}
+
+func (stub *deletionRepositoryStub) DoesTeacherOwnLaboratory(teacherUUID string, laboratoryUUID string) (bool, error) {
+	return teacherUUID == "teacher", nil
+}
+
+func (stub *deletionRepositoryStub) DeleteLaboratory(laboratoryUUID string) error {
+	stub.isDeleted = true
+	return nil
+}
+
+// DoesTeacherOwnDeletedLaboratory ignores the restore window, the repository is in charge of it
+func (stub *deletionRepositoryStub) DoesTeacherOwnDeletedLaboratory(teacherUUID string, laboratoryUUID string, restoreWindowSeconds int) (bool, error) {
+	if !stub.isDeleted {
+		return false, laboratoriesErrors.LaboratoryNotFoundError{}
+	}
+
+	return teacherUUID == "teacher", nil
+}
+
+func (stub *deletionRepositoryStub) RestoreLaboratory(laboratoryUUID string) error {
+	stub.isDeleted = false
+	return nil
+}
+
+func TestDeleteAndRestoreLaboratory(t *testing.T) {
+	repository := &deletionRepositoryStub{}
+	useCases := &LaboratoriesUseCases{LaboratoriesRepository: repository}
+
+	restoreDTO := &dtos.RestoreLaboratoryDTO{TeacherUUID: "teacher", LaboratoryUUID: "laboratory", RestoreWindowSeconds: 60}
+
+	// Only the deleted laboratories can be restored
+	err := useCases.RestoreLaboratory(restoreDTO)
+	if !errors.Is(err, laboratoriesErrors.LaboratoryNotFoundError{}) {
+		t.Fatalf("Expected the laboratory not to be found, got %v", err)
+	}
+
+	// Only the owner of the laboratory can delete it
+	err = useCases.DeleteLaboratory(&dtos.DeleteLaboratoryDTO{TeacherUUID: "another teacher", LaboratoryUUID: "laboratory"})
+	if !errors.Is(err, laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}) {
+		t.Fatalf("Expected the deletion to be rejected because of its owner, got %v", err)
+	}
+
+	if repository.isDeleted {
+		t.Fatal("The laboratory was deleted by another teacher")
+	}
+
+	if err := useCases.DeleteLaboratory(&dtos.DeleteLaboratoryDTO{TeacherUUID: "teacher", LaboratoryUUID: "laboratory"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !repository.isDeleted {
+		t.Fatal("The laboratory was not deleted")
+	}
+
+	// Only the owner of the laboratory can restore it
+	err = useCases.RestoreLaboratory(&dtos.RestoreLaboratoryDTO{TeacherUUID: "another teacher", LaboratoryUUID: "laboratory", RestoreWindowSeconds: 60})
+	if !errors.Is(err, laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}) {
+		t.Fatalf("Expected the restoration to be rejected because of its owner, got %v", err)
+	}
+
+	if err := useCases.RestoreLaboratory(restoreDTO); err != nil {
+		t.Fatal(err)
+	}
+
+	if repository.isDeleted {
+		t.Fatal("The laboratory was not restored")
+	}
+}
@@ -0,0 +1,47 @@
+package application
+
+import (
+	"fmt"
+	"math"
+
+	rubricsEntities "github.com/UPB-Code-Labs/main-api/src/rubrics/domain/entities"
+)
+
+// Sum of the highest criteria weight of each objective of a complete rubric
+const completeRubricWeight = 100
+
+// getRubricPublicationIssues returns the reasons why the rubric can not grade the students of a published
+// laboratory: every objective needs criteria and the best criteria of all the objectives must add up to
+// the complete weight
+func getRubricPublicationIssues(rubric *rubricsEntities.Rubric) []string {
+	if len(rubric.Objectives) == 0 {
+		return []string{fmt.Sprintf("The rubric %s does not have objectives", rubric.Name)}
+	}
+
+	issues := []string{}
+	totalWeight := 0.0
+	for _, objective := range rubric.Objectives {
+		if len(objective.Criteria) == 0 {
+			issues = append(issues, fmt.Sprintf("The objective %s of the rubric does not have criteria", objective.Description))
+			continue
+		}
+
+		highestWeight := 0.0
+		for _, criteria := range objective.Criteria {
+			highestWeight = math.Max(highestWeight, float64(criteria.Weight))
+		}
+
+		totalWeight += highestWeight
+	}
+
+	// The weights are stored as decimals, so they are compared with a tolerance
+	if math.Abs(totalWeight-completeRubricWeight) > 0.01 {
+		issues = append(issues, fmt.Sprintf(
+			"The highest criteria of the objectives of the rubric add up to %.2f instead of %d",
+			totalWeight,
+			completeRubricWeight,
+		))
+	}
+
+	return issues
+}
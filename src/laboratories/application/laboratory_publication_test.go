@@ -0,0 +1,198 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
+	rubricsEntities "github.com/UPB-Code-Labs/main-api/src/rubrics/domain/entities"
+)
+
+// publicationRepositoryStub owns a single laboratory with the given blocks and records its publication
+type publicationRepositoryStub struct {
+	definitions.LaboratoriesRepository
+
+	rubricUUID     *string
+	markdownBlocks []entities.MarkdownBlock
+	testBlocks     []entities.TestBlock
+	testArchives   []*dtos.TestBlockArchiveDTO
+	isPublished    *bool
+}
+
+func (stub *publicationRepositoryStub) DoesTeacherOwnLaboratory(teacherUUID string, laboratoryUUID string) (bool, error) {
+	return teacherUUID == "teacher", nil
+}
+
+func (stub *publicationRepositoryStub) GetLaboratoryByUUID(dto *dtos.GetLaboratoryDTO) (*entities.Laboratory, error) {
+	return &entities.Laboratory{
+		UUID:           dto.LaboratoryUUID,
+		RubricUUID:     stub.rubricUUID,
+		MarkdownBlocks: stub.markdownBlocks,
+		TestBlocks:     stub.testBlocks,
+	}, nil
+}
+
+func (stub *publicationRepositoryStub) GetTestBlocksArchives(laboratoryUUID string) ([]*dtos.TestBlockArchiveDTO, error) {
+	return stub.testArchives, nil
+}
+
+func (stub *publicationRepositoryStub) UpdateLaboratoryPublication(laboratoryUUID string, isPublished bool) error {
+	stub.isPublished = &isPublished
+	return nil
+}
+
+// newLaboratoryPublicationUseCases returns a laboratory that is ready to be published
+func newLaboratoryPublicationUseCases() (*LaboratoriesUseCases, *publicationRepositoryStub, *staticFilesRepositoryStub, *rubricsEntities.Rubric) {
+	rubricUUID := "rubric"
+
+	laboratoriesRepository := &publicationRepositoryStub{
+		rubricUUID:     &rubricUUID,
+		markdownBlocks: []entities.MarkdownBlock{{UUID: "statement", Content: "# Linked lists"}},
+		testBlocks:     []entities.TestBlock{{UUID: "unit-tests", Name: "Unit tests"}},
+		testArchives:   []*dtos.TestBlockArchiveDTO{{TestBlockUUID: "unit-tests", ArchiveUUID: "tests"}},
+	}
+
+	staticFilesRepository := &staticFilesRepositoryStub{
+		archives: map[string][]byte{"tests": []byte("archive")},
+	}
+
+	rubric := &rubricsEntities.Rubric{
+		UUID: rubricUUID,
+		Name: "Data structures",
+		Objectives: []rubricsEntities.RubricObjective{
+			{
+				Description: "Implements the operations",
+				Criteria: []rubricsEntities.RubricObjectiveCriteria{
+					{Description: "Every operation works", Weight: 60},
+					{Description: "Some operations work", Weight: 30},
+				},
+			},
+			{
+				Description: "Documents the code",
+				Criteria: []rubricsEntities.RubricObjectiveCriteria{
+					{Description: "Every method is documented", Weight: 40},
+				},
+			},
+		},
+	}
+
+	useCases := &LaboratoriesUseCases{
+		LaboratoriesRepository: laboratoriesRepository,
+		StaticFilesRepository:  staticFilesRepository,
+		RubricsRepository:      &rubricsRepositoryStub{rubric: rubric},
+	}
+
+	return useCases, laboratoriesRepository, staticFilesRepository, rubric
+}
+
+func TestPublishLaboratory(t *testing.T) {
+	useCases, repository, _, _ := newLaboratoryPublicationUseCases()
+	dto := &dtos.SetLaboratoryPublicationDTO{TeacherUUID: "teacher", LaboratoryUUID: "laboratory"}
+
+	if err := useCases.PublishLaboratory(dto); err != nil {
+		t.Fatal(err)
+	}
+
+	if repository.isPublished == nil || !*repository.isPublished {
+		t.Fatal("The laboratory was not published")
+	}
+
+	if err := useCases.UnpublishLaboratory(dto); err != nil {
+		t.Fatal(err)
+	}
+
+	if *repository.isPublished {
+		t.Fatal("The laboratory was not unpublished")
+	}
+
+	// Only the owner of the laboratory can change its publication
+	err := useCases.PublishLaboratory(&dtos.SetLaboratoryPublicationDTO{TeacherUUID: "another teacher", LaboratoryUUID: "laboratory"})
+	if !errors.Is(err, laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}) {
+		t.Fatalf("Expected the laboratory to be rejected because of its owner, got %v", err)
+	}
+}
+
+func TestPublishLaboratoryRejectsIncompleteLaboratories(t *testing.T) {
+	testCases := []struct {
+		name           string
+		editLaboratory func(*publicationRepositoryStub, *staticFilesRepositoryStub, *rubricsEntities.Rubric)
+		expectedIssues []string
+	}{
+		{
+			name: "Without blocks",
+			editLaboratory: func(repository *publicationRepositoryStub, _ *staticFilesRepositoryStub, _ *rubricsEntities.Rubric) {
+				repository.markdownBlocks = []entities.MarkdownBlock{}
+				repository.testBlocks = []entities.TestBlock{}
+				repository.testArchives = []*dtos.TestBlockArchiveDTO{}
+			},
+			expectedIssues: []string{"The laboratory does not have blocks"},
+		},
+		{
+			name: "Missing test archive",
+			editLaboratory: func(_ *publicationRepositoryStub, staticFiles *staticFilesRepositoryStub, _ *rubricsEntities.Rubric) {
+				delete(staticFiles.archives, "tests")
+			},
+			expectedIssues: []string{"The test archive of the test block Unit tests was not found"},
+		},
+		{
+			name: "Objective without criteria",
+			editLaboratory: func(_ *publicationRepositoryStub, _ *staticFilesRepositoryStub, rubric *rubricsEntities.Rubric) {
+				rubric.Objectives[1].Criteria = []rubricsEntities.RubricObjectiveCriteria{}
+			},
+			expectedIssues: []string{
+				"The objective Documents the code of the rubric does not have criteria",
+				"The highest criteria of the objectives of the rubric add up to 60.00 instead of 100",
+			},
+		},
+		{
+			name: "Incomplete weights",
+			editLaboratory: func(_ *publicationRepositoryStub, _ *staticFilesRepositoryStub, rubric *rubricsEntities.Rubric) {
+				rubric.Objectives[1].Criteria[0].Weight = 20
+			},
+			expectedIssues: []string{"The highest criteria of the objectives of the rubric add up to 80.00 instead of 100"},
+		},
+		{
+			name: "Every issue at once",
+			editLaboratory: func(repository *publicationRepositoryStub, staticFiles *staticFilesRepositoryStub, rubric *rubricsEntities.Rubric) {
+				repository.markdownBlocks = []entities.MarkdownBlock{}
+				delete(staticFiles.archives, "tests")
+				rubric.Objectives = []rubricsEntities.RubricObjective{}
+			},
+			expectedIssues: []string{
+				"The test archive of the test block Unit tests was not found",
+				"The rubric Data structures does not have objectives",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			useCases, repository, staticFiles, rubric := newLaboratoryPublicationUseCases()
+			testCase.editLaboratory(repository, staticFiles, rubric)
+
+			err := useCases.PublishLaboratory(&dtos.SetLaboratoryPublicationDTO{TeacherUUID: "teacher", LaboratoryUUID: "laboratory"})
+
+			publicationError, ok := err.(laboratoriesErrors.LaboratoryCannotBePublishedError)
+			if !ok {
+				t.Fatalf("Expected the laboratory to be rejected, got %v", err)
+			}
+
+			if len(publicationError.Details()) != len(testCase.expectedIssues) {
+				t.Fatalf("Expected the issues %v, got %v", testCase.expectedIssues, publicationError.Details())
+			}
+
+			for index, issue := range testCase.expectedIssues {
+				if publicationError.Details()[index] != issue {
+					t.Errorf("Expected the issue %q, got %q", issue, publicationError.Details()[index])
+				}
+			}
+
+			if repository.isPublished != nil {
+				t.Error("The laboratory was published")
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+)
+
+// Name of the file summarizing the submissions included in the archive
+const submissionsArchiveManifestName = "manifest.csv"
+
+var submissionsArchiveManifestHeader = []string{
+	"institutional_id",
+	"full_name",
+	"test_block",
+	"submission_uuid",
+	"status",
+	"is_passing",
+	"submitted_at",
+	"path",
+	"is_archive_included",
+}
+
+// submissionsArchiveWriter bundles the archives of many submissions in a single .zip archive
+type submissionsArchiveWriter struct {
+	zipWriter *zip.Writer
+
+	// Rows of the manifest, written when the archive is closed
+	manifestRows [][]string
+
+	// Number of times each folder was requested, used to avoid collisions between test blocks with the same name
+	usedFolders map[string]int
+}
+
+func newSubmissionsArchiveWriter(writer io.Writer) *submissionsArchiveWriter {
+	return &submissionsArchiveWriter{
+		zipWriter:    zip.NewWriter(writer),
+		manifestRows: [][]string{},
+		usedFolders:  make(map[string]int),
+	}
+}
+
+// AddSubmission copies the files of the archive of a submission to its folder in the bundle.
+// Submissions whose archive is not a valid .zip file are reported as missing in the manifest
+func (writer *submissionsArchiveWriter) AddSubmission(submission *dtos.LaboratorySubmissionArchiveDTO, archiveBytes []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(archiveBytes), int64(len(archiveBytes)))
+	if err != nil {
+		log.Printf(
+			"[Submissions archive]: The archive of the submission %s is not valid: %s",
+			submission.SubmissionUUID,
+			err.Error(),
+		)
+		writer.AddMissingSubmission(submission)
+		return nil
+	}
+
+	folder := writer.getSubmissionFolder(submission)
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		entryPath, isValid := sanitizeArchiveEntryPath(file.Name)
+		if !isValid {
+			continue
+		}
+
+		// Copy the compressed data as is, only the name of the entry changes
+		file.Name = path.Join(folder, entryPath)
+		if err := writer.zipWriter.Copy(file); err != nil {
+			return err
+		}
+	}
+
+	writer.addManifestRow(submission, folder, true)
+	return nil
+}
+
+// AddMissingSubmission reports a submission whose archive could not be included in the bundle
+func (writer *submissionsArchiveWriter) AddMissingSubmission(submission *dtos.LaboratorySubmissionArchiveDTO) {
+	writer.addManifestRow(submission, "", false)
+}
+
+// Close writes the manifest and finishes the .zip archive
+func (writer *submissionsArchiveWriter) Close() error {
+	manifestFile, err := writer.zipWriter.Create(submissionsArchiveManifestName)
+	if err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(manifestFile)
+	if err := csvWriter.Write(submissionsArchiveManifestHeader); err != nil {
+		return err
+	}
+	if err := csvWriter.WriteAll(writer.manifestRows); err != nil {
+		return err
+	}
+
+	return writer.zipWriter.Close()
+}
+
+func (writer *submissionsArchiveWriter) addManifestRow(submission *dtos.LaboratorySubmissionArchiveDTO, folder string, isArchiveIncluded bool) {
+	writer.manifestRows = append(writer.manifestRows, []string{
+		submission.StudentInstitutionalID,
+		submission.StudentFullName,
+		submission.TestBlockName,
+		submission.SubmissionUUID,
+		submission.SubmissionStatus,
+		strconv.FormatBool(submission.IsSubmissionPassing),
+		submission.SubmittedAt,
+		folder,
+		strconv.FormatBool(isArchiveIncluded),
+	})
+}
+
+// getSubmissionFolder returns the `<institutional_id>_<full_name>/<test_block_name>` folder of a submission
+func (writer *submissionsArchiveWriter) getSubmissionFolder(submission *dtos.LaboratorySubmissionArchiveDTO) string {
+	// Fallback to the UUID of the student to keep the folders of different students apart
+	studentID := submission.StudentInstitutionalID
+	if studentID == "" {
+		studentID = submission.StudentUUID
+	}
+
+	folder := path.Join(
+		sanitizeArchivePathComponent(fmt.Sprintf("%s_%s", studentID, submission.StudentFullName)),
+		sanitizeArchivePathComponent(submission.TestBlockName),
+	)
+
+	writer.usedFolders[folder]++
+	if times := writer.usedFolders[folder]; times > 1 {
+		folder = fmt.Sprintf("%s_%d", folder, times)
+	}
+
+	return folder
+}
+
+// sanitizeArchivePathComponent replaces the characters that are not allowed in file names by most
+// operating systems, so the name can be used as a single folder of the archive
+func sanitizeArchivePathComponent(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	sanitized = strings.Trim(sanitized, " .")
+	if sanitized == "" {
+		return "_"
+	}
+
+	return sanitized
+}
+
+// sanitizeArchiveEntryPath returns a relative path that can not escape the folder it is placed in
+func sanitizeArchiveEntryPath(name string) (string, bool) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(name, `\`, "/"))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+
+	return cleaned, cleaned != ""
+}
@@ -0,0 +1,274 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	staticFilesErrors "github.com/UPB-Code-Labs/main-api/src/static-files/domain/errors"
+)
+
+// staticFilesRepositoryStub serves the archives stored in memory
+type staticFilesRepositoryStub struct {
+	archives map[string][]byte
+}
+
+func (stub *staticFilesRepositoryStub) SaveArchive(dto *staticFilesDTOs.SaveStaticFileDTO) (string, error) {
+	archive, err := io.ReadAll(*dto.File)
+	if err != nil {
+		return "", err
+	}
+
+	archiveUUID := fmt.Sprintf("saved %d", len(stub.archives))
+	stub.archives[archiveUUID] = archive
+	return archiveUUID, nil
+}
+
+func (stub *staticFilesRepositoryStub) OverwriteArchive(dto *staticFilesDTOs.OverwriteStaticFileDTO) error {
+	return errors.New("not implemented")
+}
+
+func (stub *staticFilesRepositoryStub) DuplicateArchive(dto *staticFilesDTOs.StaticFileArchiveDTO) (string, error) {
+	archive, exists := stub.archives[dto.FileUUID]
+	if !exists {
+		return "", errors.New("archive not found")
+	}
+
+	copyUUID := "copy of " + dto.FileUUID
+	stub.archives[copyUUID] = archive
+	return copyUUID, nil
+}
+
+func (stub *staticFilesRepositoryStub) GetArchiveBytes(dto *staticFilesDTOs.StaticFileArchiveDTO) ([]byte, error) {
+	archive, exists := stub.archives[dto.FileUUID]
+	if !exists {
+		return nil, errors.New("archive not found")
+	}
+
+	return archive, nil
+}
+
+func (stub *staticFilesRepositoryStub) GetLanguageTemplateArchiveBytes(languageUUID string) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (stub *staticFilesRepositoryStub) GetArchiveStream(dto *staticFilesDTOs.StaticFileArchiveDTO) (*staticFilesDTOs.StaticFileStreamDTO, error) {
+	archive, exists := stub.archives[dto.FileUUID]
+	if !exists {
+		return nil, staticFilesErrors.ArchiveNotFoundError{}
+	}
+
+	return &staticFilesDTOs.StaticFileStreamDTO{
+		Content:       io.NopCloser(bytes.NewReader(archive)),
+		ContentLength: int64(len(archive)),
+	}, nil
+}
+
+func (stub *staticFilesRepositoryStub) GetLanguageTemplateArchiveStream(languageUUID string) (*staticFilesDTOs.StaticFileStreamDTO, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (stub *staticFilesRepositoryStub) DeleteArchive(dto *staticFilesDTOs.StaticFileArchiveDTO) error {
+	delete(stub.archives, dto.FileUUID)
+	return nil
+}
+
+func createTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func readTestZip(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		fileReader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(fileReader)
+		fileReader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files[file.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestWriteLaboratorySubmissionsArchive(t *testing.T) {
+	useCases := LaboratoriesUseCases{
+		StaticFilesRepository: &staticFilesRepositoryStub{
+			archives: map[string][]byte{
+				"first-archive": createTestZip(t, map[string]string{
+					"src/main.py":   "print('first')",
+					"../escape.py":  "print('escape')",
+					`windows\fn.py`: "print('windows')",
+				}),
+				"second-archive": createTestZip(t, map[string]string{
+					"main.py": "print('second')",
+				}),
+				"invalid-archive": []byte("not a zip"),
+			},
+		},
+	}
+
+	submissions := []*dtos.LaboratorySubmissionArchiveDTO{
+		{
+			SubmissionUUID:         "first-submission",
+			ArchiveUUID:            "first-archive",
+			StudentUUID:            "student",
+			StudentInstitutionalID: "000123",
+			StudentFullName:        "Jane Doe",
+			TestBlockName:          "Loops / Part 1",
+			SubmissionStatus:       "ready",
+			IsSubmissionPassing:    true,
+			SubmittedAt:            "2026-10-18T10:00:00Z",
+		},
+		{
+			SubmissionUUID:         "second-submission",
+			ArchiveUUID:            "second-archive",
+			StudentUUID:            "student",
+			StudentInstitutionalID: "000123",
+			StudentFullName:        "Jane Doe",
+			TestBlockName:          "Loops / Part 1",
+			SubmissionStatus:       "ready",
+			IsSubmissionPassing:    false,
+			SubmittedAt:            "2026-10-18T11:00:00Z",
+		},
+		{
+			SubmissionUUID:   "missing-submission",
+			ArchiveUUID:      "missing-archive",
+			StudentUUID:      "other-student",
+			StudentFullName:  "John Doe",
+			TestBlockName:    "Recursion",
+			SubmissionStatus: "pending",
+		},
+		{
+			SubmissionUUID:   "invalid-submission",
+			ArchiveUUID:      "invalid-archive",
+			StudentUUID:      "other-student",
+			StudentFullName:  "John Doe",
+			TestBlockName:    "Arrays",
+			SubmissionStatus: "ready",
+		},
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := useCases.WriteLaboratorySubmissionsArchive(submissions, buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readTestZip(t, buffer.Bytes())
+
+	expectedFiles := map[string]string{
+		"000123_Jane Doe/Loops _ Part 1/src/main.py":   "print('first')",
+		"000123_Jane Doe/Loops _ Part 1/escape.py":     "print('escape')",
+		"000123_Jane Doe/Loops _ Part 1/windows/fn.py": "print('windows')",
+		"000123_Jane Doe/Loops _ Part 1_2/main.py":     "print('second')",
+		submissionsArchiveManifestName:                 "",
+	}
+
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(files) != len(expectedFiles) {
+		t.Fatalf("expected %d files, got %v", len(expectedFiles), names)
+	}
+
+	for name, content := range expectedFiles {
+		actualContent, exists := files[name]
+		if !exists {
+			t.Fatalf("expected the file %s in the archive, got %v", name, names)
+		}
+
+		if name != submissionsArchiveManifestName && actualContent != content {
+			t.Errorf("expected %s to contain %q, got %q", name, content, actualContent)
+		}
+	}
+
+	// Every submission is listed in the manifest, including the ones without archive
+	rows, err := csv.NewReader(bytes.NewReader([]byte(files[submissionsArchiveManifestName]))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != len(submissions)+1 {
+		t.Fatalf("expected %d rows in the manifest, got %d", len(submissions)+1, len(rows))
+	}
+
+	expectedRows := map[string][]string{
+		"first-submission":   {"000123_Jane Doe/Loops _ Part 1", "true", "true"},
+		"second-submission":  {"000123_Jane Doe/Loops _ Part 1_2", "false", "true"},
+		"missing-submission": {"", "false", "false"},
+		"invalid-submission": {"", "false", "false"},
+	}
+
+	for _, row := range rows[1:] {
+		expected, exists := expectedRows[row[3]]
+		if !exists {
+			t.Fatalf("unexpected submission in the manifest: %v", row)
+		}
+
+		if row[7] != expected[0] || row[5] != expected[1] || row[8] != expected[2] {
+			t.Errorf("unexpected manifest row for %s: %v", row[3], row)
+		}
+	}
+}
+
+func TestSanitizeArchiveEntryPath(t *testing.T) {
+	testCases := map[string]string{
+		"main.py":             "main.py",
+		"/absolute/main.py":   "absolute/main.py",
+		"../../etc/passwd":    "etc/passwd",
+		"src/../../main.py":   "main.py",
+		`src\windows\main.py`: "src/windows/main.py",
+	}
+
+	for name, expected := range testCases {
+		sanitized, isValid := sanitizeArchiveEntryPath(name)
+		if !isValid || sanitized != expected {
+			t.Errorf("expected %s to be sanitized to %s, got %s", name, expected, sanitized)
+		}
+	}
+
+	if _, isValid := sanitizeArchiveEntryPath("../"); isValid {
+		t.Error("expected an entry pointing outside the folder to be invalid")
+	}
+}
@@ -1,6 +1,15 @@
 package application
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"time"
+
 	blocksDefinitions "github.com/UPB-Code-Labs/main-api/src/blocks/domain/definitions"
 	coursesDefinitions "github.com/UPB-Code-Labs/main-api/src/courses/domain/definitions"
 	coursesErrors "github.com/UPB-Code-Labs/main-api/src/courses/domain/errors"
@@ -9,10 +18,14 @@ import (
 	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
 	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
 	languagesDefinitions "github.com/UPB-Code-Labs/main-api/src/languages/domain/definitions"
+	languagesEntities "github.com/UPB-Code-Labs/main-api/src/languages/domain/entities"
+	languagesErrors "github.com/UPB-Code-Labs/main-api/src/languages/domain/errors"
 	rubricsDefinitions "github.com/UPB-Code-Labs/main-api/src/rubrics/domain/definitions"
 	rubricsErrors "github.com/UPB-Code-Labs/main-api/src/rubrics/domain/errors"
+	staticFilesApplication "github.com/UPB-Code-Labs/main-api/src/static-files/application"
 	staticFilesDefinitions "github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
 	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	staticFilesErrors "github.com/UPB-Code-Labs/main-api/src/static-files/domain/errors"
 )
 
 type LaboratoriesUseCases struct {
@@ -22,6 +35,7 @@ type LaboratoriesUseCases struct {
 	LanguagesRepository    languagesDefinitions.LanguagesRepository
 	BlocksRepository       blocksDefinitions.BlockRepository
 	StaticFilesRepository  staticFilesDefinitions.StaticFilesRepository
+	ArchivesValidator      staticFilesDefinitions.ArchivesValidator
 }
 
 func (useCases *LaboratoriesUseCases) CreateLaboratory(dto *dtos.CreateLaboratoryDTO) (laboratory *entities.Laboratory, err error) {
@@ -39,6 +53,378 @@ func (useCases *LaboratoriesUseCases) CreateLaboratory(dto *dtos.CreateLaborator
 	return useCases.LaboratoriesRepository.SaveLaboratory(dto)
 }
 
+// CloneLaboratory copies a laboratory into a course the teacher owns. The test archives are duplicated, so
+// editing the blocks of a copy does not change the other ones
+func (useCases *LaboratoriesUseCases) CloneLaboratory(dto *dtos.CloneLaboratoryDTO) (laboratory *entities.Laboratory, err error) {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return nil, err
+	}
+
+	// Check that the teacher owns the target course
+	ownsCourse, err := useCases.CoursesRepository.DoesTeacherOwnsCourse(dto.TeacherUUID, dto.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ownsCourse {
+		return nil, coursesErrors.TeacherDoesNotOwnsCourseError{}
+	}
+
+	// Shift the due date to keep the duration of the laboratory
+	sourceLaboratory, err := useCases.LaboratoriesRepository.GetLaboratoryInformationByUUID(dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceOpeningDate, err := time.Parse(time.RFC3339, sourceLaboratory.OpeningDate)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDueDate, err := time.Parse(time.RFC3339, sourceLaboratory.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	name := sourceLaboratory.Name
+	if dto.Name != nil {
+		name = *dto.Name
+	}
+
+	// Duplicate the test archives
+	archivesCopies, err := useCases.duplicateTestBlocksArchives(dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	laboratoryUUID, err := useCases.LaboratoriesRepository.SaveLaboratoryClone(&dtos.SaveLaboratoryCloneDTO{
+		SourceLaboratoryUUID: dto.LaboratoryUUID,
+		CourseUUID:           dto.CourseUUID,
+		Name:                 name,
+		OpeningDate:          dto.OpeningDate,
+		DueDate:              dto.OpeningDate.Add(sourceDueDate.Sub(sourceOpeningDate)),
+		TestArchivesCopies:   archivesCopies,
+	})
+	if err != nil {
+		// The copies are not referenced by any row, so they would never be collected
+		useCases.deleteTestArchives(getMapValues(archivesCopies))
+		return nil, err
+	}
+
+	return useCases.LaboratoriesRepository.GetLaboratoryByUUID(&dtos.GetLaboratoryDTO{
+		LaboratoryUUID: laboratoryUUID,
+		UserUUID:       dto.TeacherUUID,
+		UserRole:       "teacher",
+	})
+}
+
+// duplicateTestBlocksArchives saves a copy of the test archive of each test block of the laboratory. Returns
+// the UUIDs of the copies by the UUID of the test block
+func (useCases *LaboratoriesUseCases) duplicateTestBlocksArchives(laboratoryUUID string) (map[string]string, error) {
+	archives, err := useCases.LaboratoriesRepository.GetTestBlocksArchives(laboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	archivesCopies := map[string]string{}
+	for _, archive := range archives {
+		copyUUID, err := useCases.StaticFilesRepository.DuplicateArchive(&staticFilesDTOs.StaticFileArchiveDTO{
+			FileUUID: archive.ArchiveUUID,
+			FileType: "test",
+		})
+		if err != nil {
+			useCases.deleteTestArchives(getMapValues(archivesCopies))
+			return nil, err
+		}
+
+		archivesCopies[archive.TestBlockUUID] = copyUUID
+	}
+
+	return archivesCopies, nil
+}
+
+// deleteTestArchives deletes the given test archives, the errors are only logged
+func (useCases *LaboratoriesUseCases) deleteTestArchives(archivesUUIDs []string) {
+	for _, archiveUUID := range archivesUUIDs {
+		staticFilesApplication.DeleteUnreferencedArchive(useCases.StaticFilesRepository, archiveUUID, "test", "Test archives")
+	}
+}
+
+func getMapValues[K comparable](values map[K]string) []string {
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		list = append(list, value)
+	}
+
+	return list
+}
+
+// GetLaboratoryBundle describes the laboratory as a portable bundle, so it can be written with
+// `WriteLaboratoryBundle`. The rubric is only included if it is requested
+func (useCases *LaboratoriesUseCases) GetLaboratoryBundle(dto *dtos.ExportLaboratoryDTO) (bundle *dtos.LaboratoryBundleDTO, err error) {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return nil, err
+	}
+
+	laboratory, err := useCases.LaboratoriesRepository.GetLaboratoryInformationByUUID(dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the duration is kept, the dates are chosen when the bundle is imported
+	openingDate, err := time.Parse(time.RFC3339, laboratory.OpeningDate)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate, err := time.Parse(time.RFC3339, laboratory.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := useCases.LaboratoriesRepository.GetLaboratoryBundleBlocks(dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle = &dtos.LaboratoryBundleDTO{
+		Manifest: &entities.LaboratoryBundleManifest{
+			Version:         entities.LaboratoryBundleVersion,
+			Name:            laboratory.Name,
+			DurationMinutes: int(dueDate.Sub(openingDate).Minutes()),
+			LatePolicy:      laboratory.LatePolicy,
+			Blocks:          []entities.LaboratoryBundleBlock{},
+		},
+		TestArchives: map[string]string{},
+	}
+
+	for index, block := range blocks {
+		if block.Type == entities.LaboratoryBundleMarkdownBlock {
+			bundle.Manifest.Blocks = append(bundle.Manifest.Blocks, entities.LaboratoryBundleBlock{
+				Type:    entities.LaboratoryBundleMarkdownBlock,
+				Content: block.MarkdownContent,
+			})
+			continue
+		}
+
+		archivePath := getLaboratoryBundleTestArchivePath(index+1, block.TestBlockName)
+		bundle.TestArchives[archivePath] = block.TestArchiveUUID
+		bundle.Manifest.Blocks = append(bundle.Manifest.Blocks, entities.LaboratoryBundleBlock{
+			Type:            entities.LaboratoryBundleTestBlock,
+			Name:            block.TestBlockName,
+			Language:        block.LanguageName,
+			ExecutionLimits: getBundleExecutionLimits(block.ExecutionLimits),
+			TestArchive:     archivePath,
+		})
+	}
+
+	if dto.IncludeRubric && laboratory.RubricUUID != nil {
+		rubric, err := useCases.RubricsRepository.GetByUUID(*laboratory.RubricUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Manifest.Rubric = newLaboratoryBundleRubric(rubric)
+	}
+
+	return bundle, nil
+}
+
+// WriteLaboratoryBundle writes a .zip archive with the manifest of the bundle and its test archives to the writer
+func (useCases *LaboratoriesUseCases) WriteLaboratoryBundle(bundle *dtos.LaboratoryBundleDTO, writer io.Writer) error {
+	zipWriter := zip.NewWriter(writer)
+
+	manifestFile, err := zipWriter.Create(entities.LaboratoryBundleManifestName)
+	if err != nil {
+		return err
+	}
+
+	// The manifest is indented, so the changes of the laboratories can be followed in a repository
+	encoder := json.NewEncoder(manifestFile)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle.Manifest); err != nil {
+		return err
+	}
+
+	for _, block := range bundle.Manifest.Blocks {
+		if block.Type != entities.LaboratoryBundleTestBlock {
+			continue
+		}
+
+		stream, err := useCases.StaticFilesRepository.GetArchiveStream(&staticFilesDTOs.StaticFileArchiveDTO{
+			FileUUID: bundle.TestArchives[block.TestArchive],
+			FileType: "test",
+		})
+		if err != nil {
+			return err
+		}
+
+		// The archives are already compressed
+		archiveFile, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:   block.TestArchive,
+			Method: zip.Store,
+		})
+		if err != nil {
+			stream.Content.Close()
+			return err
+		}
+
+		_, err = io.Copy(archiveFile, stream.Content)
+		stream.Content.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+// ImportLaboratory creates the laboratory of a bundle in a course the teacher owns. The test archives of the
+// bundle are validated against the rules of their language before being saved
+func (useCases *LaboratoriesUseCases) ImportLaboratory(dto *dtos.ImportLaboratoryDTO) (laboratory *entities.Laboratory, err error) {
+	ownsCourse, err := useCases.CoursesRepository.DoesTeacherOwnsCourse(dto.TeacherUUID, dto.CourseUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ownsCourse {
+		return nil, coursesErrors.TeacherDoesNotOwnsCourseError{}
+	}
+
+	if dto.Manifest.Version != entities.LaboratoryBundleVersion {
+		return nil, laboratoriesErrors.InvalidLaboratoryBundleError{
+			Reason: fmt.Sprintf("the version %d is not supported", dto.Manifest.Version),
+		}
+	}
+
+	// The languages are referenced by their name, as their UUIDs change between deployments
+	languages, err := useCases.getLaboratoryBundleLanguages(dto.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	testArchivesUUIDs, err := useCases.saveLaboratoryBundleTestArchives(dto, languages)
+	if err != nil {
+		return nil, err
+	}
+
+	languagesUUIDs := map[string]string{}
+	for name, language := range languages {
+		languagesUUIDs[name] = language.UUID
+	}
+
+	name := dto.Manifest.Name
+	if dto.Name != nil {
+		name = *dto.Name
+	}
+
+	laboratoryUUID, err := useCases.LaboratoriesRepository.SaveLaboratoryImport(&dtos.SaveLaboratoryImportDTO{
+		TeacherUUID:       dto.TeacherUUID,
+		CourseUUID:        dto.CourseUUID,
+		Name:              name,
+		OpeningDate:       dto.OpeningDate,
+		DueDate:           dto.OpeningDate.Add(time.Duration(dto.Manifest.DurationMinutes) * time.Minute),
+		LatePolicy:        dto.Manifest.LatePolicy,
+		Rubric:            dto.Manifest.Rubric,
+		Blocks:            dto.Manifest.Blocks,
+		LanguagesUUIDs:    languagesUUIDs,
+		TestArchivesUUIDs: testArchivesUUIDs,
+	})
+	if err != nil {
+		// The archives are not referenced by any row, so they would never be collected
+		useCases.deleteTestArchives(getMapValues(testArchivesUUIDs))
+		return nil, err
+	}
+
+	return useCases.LaboratoriesRepository.GetLaboratoryByUUID(&dtos.GetLaboratoryDTO{
+		LaboratoryUUID: laboratoryUUID,
+		UserUUID:       dto.TeacherUUID,
+		UserRole:       "teacher",
+	})
+}
+
+// getLaboratoryBundleLanguages returns the languages of the test blocks of the bundle by their name. All of them
+// must exist and can not be deprecated
+func (useCases *LaboratoriesUseCases) getLaboratoryBundleLanguages(manifest *entities.LaboratoryBundleManifest) (map[string]*languagesEntities.Language, error) {
+	languages := map[string]*languagesEntities.Language{}
+
+	for _, block := range manifest.Blocks {
+		if block.Type != entities.LaboratoryBundleTestBlock {
+			continue
+		}
+
+		if _, ok := languages[block.Language]; ok {
+			continue
+		}
+
+		language, err := useCases.LanguagesRepository.GetByName(block.Language)
+		if err != nil {
+			if _, ok := err.(*languagesErrors.LangNotFoundError); ok {
+				return nil, laboratoriesErrors.InvalidLaboratoryBundleError{
+					Reason: fmt.Sprintf("the language %s is not available", block.Language),
+				}
+			}
+
+			return nil, err
+		}
+
+		if language.IsDeprecated {
+			return nil, laboratoriesErrors.InvalidLaboratoryBundleError{
+				Reason: fmt.Sprintf("the language %s is deprecated", block.Language),
+			}
+		}
+
+		languages[block.Language] = language
+	}
+
+	return languages, nil
+}
+
+// saveLaboratoryBundleTestArchives validates and saves the test archive of each test block of the bundle, even
+// if many blocks share the same archive. Returns the UUIDs of the saved archives by the index of their block
+func (useCases *LaboratoriesUseCases) saveLaboratoryBundleTestArchives(dto *dtos.ImportLaboratoryDTO, languages map[string]*languagesEntities.Language) (map[int]string, error) {
+	testArchivesUUIDs := map[int]string{}
+
+	for index, block := range dto.Manifest.Blocks {
+		if block.Type != entities.LaboratoryBundleTestBlock {
+			continue
+		}
+
+		archiveUUID, err := useCases.saveLaboratoryBundleTestArchive(dto, &block, languages[block.Language])
+		if err != nil {
+			useCases.deleteTestArchives(getMapValues(testArchivesUUIDs))
+			return nil, err
+		}
+
+		testArchivesUUIDs[index] = archiveUUID
+	}
+
+	return testArchivesUUIDs, nil
+}
+
+func (useCases *LaboratoriesUseCases) saveLaboratoryBundleTestArchive(dto *dtos.ImportLaboratoryDTO, block *entities.LaboratoryBundleBlock, language *languagesEntities.Language) (archiveUUID string, err error) {
+	content, err := readLaboratoryBundleTestArchive(dto.Bundle, block.TestArchive, dto.ArchiveMaxSizeKb)
+	if err != nil {
+		return "", err
+	}
+
+	// Check the content of the archive
+	var validatedFile multipart.File = laboratoryBundleArchiveFile{bytes.NewReader(content)}
+	err = useCases.ArchivesValidator.ValidateArchive(&staticFilesDTOs.ValidateArchiveDTO{
+		File:          &validatedFile,
+		RequiredPaths: language.RequiredPaths,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var savedFile multipart.File = laboratoryBundleArchiveFile{bytes.NewReader(content)}
+	return useCases.StaticFilesRepository.SaveArchive(&staticFilesDTOs.SaveStaticFileDTO{
+		File:     &savedFile,
+		FileType: "test",
+	})
+}
+
 func (useCases *LaboratoriesUseCases) GetLaboratory(dto *dtos.GetLaboratoryDTO) (laboratory *entities.Laboratory, err error) {
 	// Get the laboratory
 	laboratory, err = useCases.LaboratoriesRepository.GetLaboratoryByUUID(dto)
@@ -56,6 +442,16 @@ func (useCases *LaboratoriesUseCases) GetLaboratory(dto *dtos.GetLaboratoryDTO)
 		return nil, coursesErrors.UserNotInCourseError{}
 	}
 
+	// Students see the dates they were granted
+	if dto.UserRole == "student" {
+		override, err := useCases.LaboratoriesRepository.GetStudentDateOverride(dto.LaboratoryUUID, dto.UserUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		laboratory.OpeningDate, laboratory.DueDate = override.Apply(laboratory.OpeningDate, laboratory.DueDate)
+	}
+
 	return laboratory, nil
 }
 
@@ -79,6 +475,23 @@ func (useCases *LaboratoriesUseCases) GetLaboratoryInformation(dto *dtos.GetLabo
 		return nil, coursesErrors.UserNotInCourseError{}
 	}
 
+	// Draft laboratories do not exist for the students, who see the dates they were granted
+	if dto.UserRole == "student" {
+		if !laboratoryInformation.IsPublished {
+			return nil, laboratoriesErrors.LaboratoryNotFoundError{}
+		}
+
+		override, err := useCases.LaboratoriesRepository.GetStudentDateOverride(dto.LaboratoryUUID, dto.UserUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		laboratoryInformation.OpeningDate, laboratoryInformation.DueDate = override.Apply(
+			laboratoryInformation.OpeningDate,
+			laboratoryInformation.DueDate,
+		)
+	}
+
 	return laboratoryInformation, nil
 }
 
@@ -108,6 +521,207 @@ func (useCases *LaboratoriesUseCases) UpdateLaboratory(dto *dtos.UpdateLaborator
 	return useCases.LaboratoriesRepository.UpdateLaboratory(dto)
 }
 
+// SetLatePolicy replaces the rules applied to the submissions sent after the due date of the laboratory
+func (useCases *LaboratoriesUseCases) SetLatePolicy(dto *dtos.SetLatePolicyDTO) error {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return err
+	}
+
+	return useCases.LaboratoriesRepository.UpdateLatePolicy(dto)
+}
+
+// PublishLaboratory shows the laboratory to the students of the course. The laboratory must have blocks, the
+// test archives of its test blocks must exist and the weights of its rubric, if any, must be complete
+func (useCases *LaboratoriesUseCases) PublishLaboratory(dto *dtos.SetLaboratoryPublicationDTO) error {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return err
+	}
+
+	issues, err := useCases.getLaboratoryPublicationIssues(dto)
+	if err != nil {
+		return err
+	}
+
+	if len(issues) > 0 {
+		return laboratoriesErrors.LaboratoryCannotBePublishedError{Issues: issues}
+	}
+
+	return useCases.LaboratoriesRepository.UpdateLaboratoryPublication(dto.LaboratoryUUID, true)
+}
+
+// getLaboratoryPublicationIssues returns all the reasons why the laboratory can not be published, so the
+// teacher can fix them at once
+func (useCases *LaboratoriesUseCases) getLaboratoryPublicationIssues(dto *dtos.SetLaboratoryPublicationDTO) ([]string, error) {
+	laboratory, err := useCases.LaboratoriesRepository.GetLaboratoryByUUID(&dtos.GetLaboratoryDTO{
+		LaboratoryUUID: dto.LaboratoryUUID,
+		UserUUID:       dto.TeacherUUID,
+		UserRole:       "teacher",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []string{}
+	if len(laboratory.MarkdownBlocks) == 0 && len(laboratory.TestBlocks) == 0 {
+		issues = append(issues, "The laboratory does not have blocks")
+	}
+
+	// Check the test archives were not lost from the static files storage
+	archives, err := useCases.LaboratoriesRepository.GetTestBlocksArchives(dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	testBlocksNames := map[string]string{}
+	for _, testBlock := range laboratory.TestBlocks {
+		testBlocksNames[testBlock.UUID] = testBlock.Name
+	}
+
+	for _, archive := range archives {
+		stream, err := useCases.StaticFilesRepository.GetArchiveStream(&staticFilesDTOs.StaticFileArchiveDTO{
+			FileUUID: archive.ArchiveUUID,
+			FileType: "test",
+		})
+		if err != nil {
+			if _, isNotFound := err.(staticFilesErrors.ArchiveNotFoundError); isNotFound {
+				issues = append(issues, fmt.Sprintf(
+					"The test archive of the test block %s was not found",
+					testBlocksNames[archive.TestBlockUUID],
+				))
+				continue
+			}
+
+			return nil, err
+		}
+
+		stream.Content.Close()
+	}
+
+	if laboratory.RubricUUID != nil {
+		rubric, err := useCases.RubricsRepository.GetByUUID(*laboratory.RubricUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, getRubricPublicationIssues(rubric)...)
+	}
+
+	return issues, nil
+}
+
+// UnpublishLaboratory hides the laboratory from the students of the course again. Their submissions are kept
+func (useCases *LaboratoriesUseCases) UnpublishLaboratory(dto *dtos.SetLaboratoryPublicationDTO) error {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return err
+	}
+
+	return useCases.LaboratoriesRepository.UpdateLaboratoryPublication(dto.LaboratoryUUID, false)
+}
+
+// DeleteLaboratory hides the laboratory until it is restored or purged
+func (useCases *LaboratoriesUseCases) DeleteLaboratory(dto *dtos.DeleteLaboratoryDTO) error {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return err
+	}
+
+	return useCases.LaboratoriesRepository.DeleteLaboratory(dto.LaboratoryUUID)
+}
+
+// RestoreLaboratory shows a deleted laboratory again, if its restore window has not passed
+func (useCases *LaboratoriesUseCases) RestoreLaboratory(dto *dtos.RestoreLaboratoryDTO) error {
+	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnDeletedLaboratory(
+		dto.TeacherUUID, dto.LaboratoryUUID, dto.RestoreWindowSeconds,
+	)
+	if err != nil {
+		return err
+	}
+
+	if !teacherOwnsLaboratory {
+		return laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
+	}
+
+	return useCases.LaboratoriesRepository.RestoreLaboratory(dto.LaboratoryUUID)
+}
+
+// SetDateOverrides grants the given students different opening and / or due dates, replacing the ones they
+// were granted before
+func (useCases *LaboratoriesUseCases) SetDateOverrides(dto *dtos.SetDateOverridesDTO) error {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return err
+	}
+
+	laboratory, err := useCases.LaboratoriesRepository.GetLaboratoryInformationByUUID(dto.LaboratoryUUID)
+	if err != nil {
+		return err
+	}
+
+	// The dates that are not overridden are the ones of the laboratory
+	openingDate, err := time.Parse(time.RFC3339, laboratory.OpeningDate)
+	if err != nil {
+		return err
+	}
+	if dto.OpeningDate != nil {
+		openingDate = *dto.OpeningDate
+	}
+
+	dueDate, err := time.Parse(time.RFC3339, laboratory.DueDate)
+	if err != nil {
+		return err
+	}
+	if dto.DueDate != nil {
+		dueDate = *dto.DueDate
+	}
+
+	if !dueDate.After(openingDate) {
+		return laboratoriesErrors.InvalidDateOverrideError{}
+	}
+
+	// Check all the students belong to the course
+	for _, studentUUID := range dto.StudentsUUIDs {
+		isEnrolled, err := useCases.CoursesRepository.IsUserInCourse(studentUUID, laboratory.CourseUUID)
+		if err != nil {
+			return err
+		}
+
+		if !isEnrolled {
+			return laboratoriesErrors.StudentNotInLaboratoryCourseError{}
+		}
+	}
+
+	return useCases.LaboratoriesRepository.SaveDateOverrides(dto)
+}
+
+// GetDateOverrides returns the dates granted to the students of the laboratory
+func (useCases *LaboratoriesUseCases) GetDateOverrides(dto *dtos.GetDateOverridesDTO) ([]*entities.DateOverride, error) {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return nil, err
+	}
+
+	return useCases.LaboratoriesRepository.GetDateOverrides(dto.LaboratoryUUID)
+}
+
+// DeleteDateOverride removes the dates granted to a student, so the ones of the laboratory are applied again
+func (useCases *LaboratoriesUseCases) DeleteDateOverride(dto *dtos.DeleteDateOverrideDTO) error {
+	if err := useCases.checkTeacherOwnsLaboratory(dto.TeacherUUID, dto.LaboratoryUUID); err != nil {
+		return err
+	}
+
+	return useCases.LaboratoriesRepository.DeleteDateOverride(dto.LaboratoryUUID, dto.StudentUUID)
+}
+
+func (useCases *LaboratoriesUseCases) checkTeacherOwnsLaboratory(teacherUUID string, laboratoryUUID string) error {
+	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnLaboratory(teacherUUID, laboratoryUUID)
+	if err != nil {
+		return err
+	}
+
+	if !teacherOwnsLaboratory {
+		return laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
+	}
+
+	return nil
+}
+
 func (useCases *LaboratoriesUseCases) CreateMarkdownBlock(dto *dtos.CreateMarkdownBlockDTO) (blockUUID string, err error) {
 	// Check that the teacher owns the laboratory
 	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnLaboratory(dto.TeacherUUID, dto.LaboratoryUUID)
@@ -134,8 +748,21 @@ func (useCases *LaboratoriesUseCases) CreateTestBlock(reqDTO *dtos.CreateTestBlo
 		return "", laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
 	}
 
-	// Check that the language exists
-	_, err = useCases.LanguagesRepository.GetByUUID(reqDTO.LanguageUUID)
+	// Check that the language exists and can be chosen
+	language, err := useCases.LanguagesRepository.GetByUUID(reqDTO.LanguageUUID)
+	if err != nil {
+		return "", err
+	}
+
+	if language.IsDeprecated {
+		return "", &languagesErrors.LangIsDeprecatedError{}
+	}
+
+	// Check the content of the archive
+	err = useCases.ArchivesValidator.ValidateArchive(&staticFilesDTOs.ValidateArchiveDTO{
+		File:          reqDTO.MultipartFile,
+		RequiredPaths: language.RequiredPaths,
+	})
 	if err != nil {
 		return "", err
 	}
@@ -153,7 +780,14 @@ func (useCases *LaboratoriesUseCases) CreateTestBlock(reqDTO *dtos.CreateTestBlo
 	reqDTO.TestArchiveUUID = savedArchiveUUID
 
 	// Save the information in the database
-	return useCases.LaboratoriesRepository.CreateTestBlock(reqDTO)
+	testBlockUUID, err := useCases.LaboratoriesRepository.CreateTestBlock(reqDTO)
+	if err != nil {
+		staticFilesApplication.DeleteUnreferencedArchive(useCases.StaticFilesRepository, savedArchiveUUID, "test", "Test blocks")
+
+		return "", err
+	}
+
+	return testBlockUUID, nil
 }
 
 func (useCases *LaboratoriesUseCases) GetLaboratoryProgress(dto *dtos.GetLaboratoryProgressDTO) (progress *dtos.LaboratoryProgressDTO, err error) {
@@ -221,3 +855,51 @@ func (useCases *LaboratoriesUseCases) GetProgressOfStudentInLaboratory(dto *dtos
 		StudentSubmissions: submissions,
 	}, nil
 }
+
+// GetLaboratorySubmissionsArchives returns the latest submission of each student in each test block of
+// the laboratory, so their archives can be bundled with `WriteLaboratorySubmissionsArchive`
+func (useCases *LaboratoriesUseCases) GetLaboratorySubmissionsArchives(dto *dtos.GetLaboratorySubmissionsArchiveDTO) (submissions []*dtos.LaboratorySubmissionArchiveDTO, err error) {
+	// Check that the teacher owns the laboratory
+	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnLaboratory(dto.TeacherUUID, dto.LaboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !teacherOwnsLaboratory {
+		return nil, laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
+	}
+
+	return useCases.LaboratoriesRepository.GetLatestSubmissionsArchives(dto.LaboratoryUUID)
+}
+
+// WriteLaboratorySubmissionsArchive writes a .zip archive with the code of the given submissions to the writer.
+// The code of each submission is placed under `<institutional_id>_<full_name>/<test_block_name>/` and a
+// `manifest.csv` file summarizes the status of every submission
+func (useCases *LaboratoriesUseCases) WriteLaboratorySubmissionsArchive(submissions []*dtos.LaboratorySubmissionArchiveDTO, writer io.Writer) error {
+	archiveWriter := newSubmissionsArchiveWriter(writer)
+
+	for _, submission := range submissions {
+		archiveBytes, err := useCases.StaticFilesRepository.GetArchiveBytes(&staticFilesDTOs.StaticFileArchiveDTO{
+			FileUUID: submission.ArchiveUUID,
+			FileType: "submission",
+		})
+
+		// The response is already being streamed, so a missing archive is reported in the manifest
+		// instead of aborting the whole download
+		if err != nil {
+			log.Printf(
+				"[Submissions archive]: Unable to get the archive of the submission %s: %s",
+				submission.SubmissionUUID,
+				err.Error(),
+			)
+			archiveWriter.AddMissingSubmission(submission)
+			continue
+		}
+
+		if err := archiveWriter.AddSubmission(submission, archiveBytes); err != nil {
+			return err
+		}
+	}
+
+	return archiveWriter.Close()
+}
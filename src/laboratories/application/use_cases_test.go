@@ -0,0 +1,148 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	coursesDefinitions "github.com/UPB-Code-Labs/main-api/src/courses/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
+)
+
+// dateOverridesRepositoryStub owns a single laboratory and records the saved date overrides
+type dateOverridesRepositoryStub struct {
+	definitions.LaboratoriesRepository
+
+	laboratory     *dtos.LaboratoryDetailsDTO
+	savedOverrides []*dtos.SetDateOverridesDTO
+}
+
+func (stub *dateOverridesRepositoryStub) DoesTeacherOwnLaboratory(teacherUUID string, laboratoryUUID string) (bool, error) {
+	return teacherUUID == "teacher", nil
+}
+
+func (stub *dateOverridesRepositoryStub) GetLaboratoryInformationByUUID(uuid string) (*dtos.LaboratoryDetailsDTO, error) {
+	return stub.laboratory, nil
+}
+
+func (stub *dateOverridesRepositoryStub) SaveDateOverrides(dto *dtos.SetDateOverridesDTO) error {
+	stub.savedOverrides = append(stub.savedOverrides, dto)
+	return nil
+}
+
+type coursesRepositoryStub struct {
+	coursesDefinitions.CoursesRepository
+
+	enrolledUsers map[string]bool
+	ownedCourses  map[string]bool
+}
+
+func (stub *coursesRepositoryStub) IsUserInCourse(userUUID, courseUUID string) (bool, error) {
+	return stub.enrolledUsers[userUUID], nil
+}
+
+func (stub *coursesRepositoryStub) DoesTeacherOwnsCourse(teacherUUID, courseUUID string) (bool, error) {
+	return stub.ownedCourses[courseUUID], nil
+}
+
+func newDateOverridesUseCases() (*LaboratoriesUseCases, *dateOverridesRepositoryStub) {
+	laboratoriesRepository := &dateOverridesRepositoryStub{
+		laboratory: &dtos.LaboratoryDetailsDTO{
+			UUID:        "laboratory",
+			CourseUUID:  "course",
+			OpeningDate: "2026-10-01T08:00:00Z",
+			DueDate:     "2026-10-08T08:00:00Z",
+		},
+	}
+
+	useCases := &LaboratoriesUseCases{
+		LaboratoriesRepository: laboratoriesRepository,
+		CoursesRepository: &coursesRepositoryStub{
+			enrolledUsers: map[string]bool{"student": true, "classmate": true},
+		},
+	}
+
+	return useCases, laboratoriesRepository
+}
+
+func parseTestDate(t *testing.T, date string) *time.Time {
+	t.Helper()
+
+	parsedDate, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &parsedDate
+}
+
+func TestSetDateOverrides(t *testing.T) {
+	useCases, repository := newDateOverridesUseCases()
+
+	err := useCases.SetDateOverrides(&dtos.SetDateOverridesDTO{
+		TeacherUUID:    "teacher",
+		LaboratoryUUID: "laboratory",
+		StudentsUUIDs:  []string{"student", "classmate"},
+		DueDate:        parseTestDate(t, "2026-10-15T08:00:00Z"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repository.savedOverrides) != 1 {
+		t.Errorf("expected the overrides to be saved once, got %d", len(repository.savedOverrides))
+	}
+}
+
+func TestSetDateOverridesRejectsInvalidOverrides(t *testing.T) {
+	testCases := []struct {
+		name          string
+		dto           *dtos.SetDateOverridesDTO
+		expectedError error
+	}{
+		{
+			name: "teacher does not own the laboratory",
+			dto: &dtos.SetDateOverridesDTO{
+				TeacherUUID:   "another teacher",
+				StudentsUUIDs: []string{"student"},
+				DueDate:       parseTestDate(t, "2026-10-15T08:00:00Z"),
+			},
+			expectedError: laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{},
+		},
+		{
+			// The opening date of the laboratory is kept, so the new due date is before it
+			name: "due date before the opening date of the laboratory",
+			dto: &dtos.SetDateOverridesDTO{
+				TeacherUUID:   "teacher",
+				StudentsUUIDs: []string{"student"},
+				DueDate:       parseTestDate(t, "2026-09-15T08:00:00Z"),
+			},
+			expectedError: laboratoriesErrors.InvalidDateOverrideError{},
+		},
+		{
+			name: "student not enrolled in the course",
+			dto: &dtos.SetDateOverridesDTO{
+				TeacherUUID:   "teacher",
+				StudentsUUIDs: []string{"student", "stranger"},
+				OpeningDate:   parseTestDate(t, "2026-10-03T08:00:00Z"),
+			},
+			expectedError: laboratoriesErrors.StudentNotInLaboratoryCourseError{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			useCases, repository := newDateOverridesUseCases()
+
+			err := useCases.SetDateOverrides(testCase.dto)
+			if err != testCase.expectedError {
+				t.Errorf("expected %v, got %v", testCase.expectedError, err)
+			}
+
+			if len(repository.savedOverrides) > 0 {
+				t.Error("expected the overrides not to be saved")
+			}
+		})
+	}
+}
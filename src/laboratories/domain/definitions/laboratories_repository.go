@@ -10,6 +10,16 @@ type LaboratoriesRepository interface {
 	GetLaboratoryInformationByUUID(uuid string) (laboratory *dtos.LaboratoryDetailsDTO, err error)
 	SaveLaboratory(dto *dtos.CreateLaboratoryDTO) (laboratory *entities.Laboratory, err error)
 	UpdateLaboratory(dto *dtos.UpdateLaboratoryDTO) error
+	UpdateLatePolicy(dto *dtos.SetLatePolicyDTO) error
+	UpdateLaboratoryPublication(laboratoryUUID string, isPublished bool) error
+
+	// Copies of laboratories. The copies of the test archives must be saved before the laboratory
+	GetTestBlocksArchives(laboratoryUUID string) (archives []*dtos.TestBlockArchiveDTO, err error)
+	SaveLaboratoryClone(dto *dtos.SaveLaboratoryCloneDTO) (laboratoryUUID string, err error)
+
+	// Portable bundles of laboratories. The test archives must be saved before the imported laboratory
+	GetLaboratoryBundleBlocks(laboratoryUUID string) (blocks []*dtos.LaboratoryBundleBlockDTO, err error)
+	SaveLaboratoryImport(dto *dtos.SaveLaboratoryImportDTO) (laboratoryUUID string, err error)
 
 	CreateMarkdownBlock(laboratoryUUID string) (blockUUID string, err error)
 	CreateTestBlock(dto *dtos.CreateTestBlockDTO) (blockUUID string, err error)
@@ -21,6 +31,21 @@ type LaboratoriesRepository interface {
 	GetStudentSubmissions(laboratoryUUID string, studentUUID string) (
 		submissions []*dtos.SummarizedStudentSubmissionDTO, err error,
 	)
+	GetLatestSubmissionsArchives(laboratoryUUID string) (
+		submissions []*dtos.LaboratorySubmissionArchiveDTO, err error,
+	)
 
 	DoesTeacherOwnLaboratory(teacherUUID string, laboratoryUUID string) (bool, error)
+
+	// Soft deletions. The deleted laboratories can be restored within the restore window, as long as their
+	// course was not deleted
+	DeleteLaboratory(laboratoryUUID string) error
+	DoesTeacherOwnDeletedLaboratory(teacherUUID string, laboratoryUUID string, restoreWindowSeconds int) (bool, error)
+	RestoreLaboratory(laboratoryUUID string) error
+
+	// Dates granted to single students. `GetStudentDateOverride` returns nil if the student has no override
+	SaveDateOverrides(dto *dtos.SetDateOverridesDTO) error
+	GetDateOverrides(laboratoryUUID string) (overrides []*entities.DateOverride, err error)
+	GetStudentDateOverride(laboratoryUUID string, studentUUID string) (override *entities.DateOverride, err error)
+	DeleteDateOverride(laboratoryUUID string, studentUUID string) error
 }
@@ -1,8 +1,13 @@
 package dtos
 
 import (
+	"archive/zip"
 	"mime/multipart"
 	"time"
+
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+	submissionsEntities "github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
 )
 
 type CreateLaboratoryDTO struct {
@@ -28,6 +33,128 @@ type UpdateLaboratoryDTO struct {
 	DueDate        time.Time
 }
 
+type SetDateOverridesDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+	StudentsUUIDs  []string
+
+	// Dates that replace the ones of the laboratory, nil dates are not overridden
+	OpeningDate *time.Time
+	DueDate     *time.Time
+}
+
+type GetDateOverridesDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+}
+
+type DeleteDateOverrideDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+	StudentUUID    string
+}
+
+type CloneLaboratoryDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+	CourseUUID     string
+
+	// The name of the laboratory is kept if it is nil. The due date is shifted to keep the duration
+	Name        *string
+	OpeningDate time.Time
+}
+
+type SaveLaboratoryCloneDTO struct {
+	SourceLaboratoryUUID string
+	CourseUUID           string
+	Name                 string
+	OpeningDate          time.Time
+	DueDate              time.Time
+
+	// UUIDs of the copies of the test archives, by the UUID of the test block they belong to
+	TestArchivesCopies map[string]string
+}
+
+type TestBlockArchiveDTO struct {
+	TestBlockUUID string
+	ArchiveUUID   string
+}
+
+type ExportLaboratoryDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+	IncludeRubric  bool
+}
+
+// LaboratoryBundleDTO manifest of a laboratory bundle and the UUIDs of the test archives to include, by
+// their path in the bundle
+type LaboratoryBundleDTO struct {
+	Manifest     *entities.LaboratoryBundleManifest
+	TestArchives map[string]string
+}
+
+// LaboratoryBundleBlockDTO block of a laboratory being exported. Only the fields of its type are set
+type LaboratoryBundleBlockDTO struct {
+	Type            string
+	MarkdownContent string
+	TestBlockName   string
+	LanguageName    string
+	ExecutionLimits *sharedEntities.ExecutionLimitsOverrides
+	TestArchiveUUID string
+}
+
+type ImportLaboratoryDTO struct {
+	TeacherUUID string
+	CourseUUID  string
+
+	// The name of the manifest is kept if it is nil. The due date keeps the duration of the manifest
+	Name        *string
+	OpeningDate time.Time
+
+	Manifest *entities.LaboratoryBundleManifest
+	Bundle   *zip.Reader
+
+	// Maximum size of each test archive of the bundle
+	ArchiveMaxSizeKb int64
+}
+
+type SaveLaboratoryImportDTO struct {
+	TeacherUUID string
+	CourseUUID  string
+	Name        string
+	OpeningDate time.Time
+	DueDate     time.Time
+	LatePolicy  entities.LatePolicy
+	Rubric      *entities.LaboratoryBundleRubric
+	Blocks      []entities.LaboratoryBundleBlock
+
+	// UUIDs of the languages by their name and of the saved test archives by the index of their block
+	LanguagesUUIDs    map[string]string
+	TestArchivesUUIDs map[int]string
+}
+
+type SetLaboratoryPublicationDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+}
+
+type DeleteLaboratoryDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+}
+
+type RestoreLaboratoryDTO struct {
+	TeacherUUID          string
+	LaboratoryUUID       string
+	RestoreWindowSeconds int
+}
+
+type SetLatePolicyDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+	LatePolicy     entities.LatePolicy
+}
+
 type CreateMarkdownBlockDTO struct {
 	TeacherUUID    string
 	LaboratoryUUID string
@@ -40,6 +167,9 @@ type CreateTestBlockDTO struct {
 	TestArchiveUUID string
 	Name            string
 	MultipartFile   *multipart.File
+
+	// Limits that replace the defaults of the language
+	ExecutionLimits *sharedEntities.ExecutionLimitsOverrides
 }
 
 type GetLaboratoryProgressDTO struct {
@@ -79,6 +209,9 @@ type SummarizedStudentSubmissionDTO struct {
 	TestBlockName         string `json:"test_block_name"`
 	SubmissionStatus      string `json:"status"`
 	IsSubmissionPassing   bool   `json:"is_passing"`
+	LateMinutes           int    `json:"late_minutes"`
+
+	TestResults []*submissionsEntities.SubmissionTestResult `json:"test_results"`
 }
 
 type LaboratoryDetailsDTO struct {
@@ -88,4 +221,25 @@ type LaboratoryDetailsDTO struct {
 	Name        string  `json:"name"`
 	OpeningDate string  `json:"opening_date"`
 	DueDate     string  `json:"due_date"`
+
+	LatePolicy  entities.LatePolicy `json:"late_policy"`
+	IsPublished bool                `json:"is_published"`
+}
+
+type GetLaboratorySubmissionsArchiveDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+}
+
+type LaboratorySubmissionArchiveDTO struct {
+	SubmissionUUID         string
+	ArchiveUUID            string
+	StudentUUID            string
+	StudentInstitutionalID string
+	StudentFullName        string
+	TestBlockUUID          string
+	TestBlockName          string
+	SubmissionStatus       string
+	IsSubmissionPassing    bool
+	SubmittedAt            string
 }
@@ -0,0 +1,27 @@
+package entities
+
+// DateOverride opening and due dates of a laboratory granted to a single student. The dates that are not
+// overridden are nil
+type DateOverride struct {
+	StudentUUID     string  `json:"student_uuid"`
+	StudentFullName string  `json:"student_full_name"`
+	OpeningDate     *string `json:"opening_date"`
+	DueDate         *string `json:"due_date"`
+}
+
+// Apply returns the given dates of the laboratory replaced by the overridden ones. A nil override keeps
+// the dates of the laboratory
+func (override *DateOverride) Apply(openingDate string, dueDate string) (string, string) {
+	if override == nil {
+		return openingDate, dueDate
+	}
+
+	if override.OpeningDate != nil {
+		openingDate = *override.OpeningDate
+	}
+	if override.DueDate != nil {
+		dueDate = *override.DueDate
+	}
+
+	return openingDate, dueDate
+}
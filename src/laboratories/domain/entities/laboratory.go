@@ -7,6 +7,8 @@ type Laboratory struct {
 	Name           string          `json:"name"`
 	OpeningDate    string          `json:"opening_date"`
 	DueDate        string          `json:"due_date"`
+	LatePolicy     LatePolicy      `json:"late_policy"`
+	IsPublished    bool            `json:"is_published"`
 	MarkdownBlocks []MarkdownBlock `json:"markdown_blocks"`
 	TestBlocks     []TestBlock     `json:"test_blocks"`
 }
@@ -0,0 +1,60 @@
+package entities
+
+import sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+
+const (
+	// LaboratoryBundleVersion version of the format of the bundles written by this deployment
+	LaboratoryBundleVersion = 1
+
+	// LaboratoryBundleManifestName name of the file describing the laboratory in the bundle
+	LaboratoryBundleManifestName = "manifest.json"
+
+	// Types of the blocks of a bundle
+	LaboratoryBundleMarkdownBlock = "markdown"
+	LaboratoryBundleTestBlock     = "test"
+)
+
+// LaboratoryBundleManifest self-contained description of a laboratory, so it can be recreated in any course of
+// any deployment. It does not reference any UUID: the languages are referenced by their name and the test
+// archives by their path in the bundle
+type LaboratoryBundleManifest struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+
+	// The dates are chosen when the laboratory is imported, only its duration is kept
+	DurationMinutes int        `json:"duration_minutes"`
+	LatePolicy      LatePolicy `json:"late_policy"`
+
+	Blocks []LaboratoryBundleBlock `json:"blocks"`
+	Rubric *LaboratoryBundleRubric `json:"rubric,omitempty"`
+}
+
+// LaboratoryBundleBlock markdown or test block of a bundle, in the order they are shown
+type LaboratoryBundleBlock struct {
+	Type string `json:"type"`
+
+	// Markdown blocks
+	Content string `json:"content,omitempty"`
+
+	// Test blocks
+	Name            string                                   `json:"name,omitempty"`
+	Language        string                                   `json:"language,omitempty"`
+	ExecutionLimits *sharedEntities.ExecutionLimitsOverrides `json:"execution_limits,omitempty"`
+	TestArchive     string                                   `json:"test_archive,omitempty"`
+}
+
+// LaboratoryBundleRubric rubric of a bundle, a new one is created for the teacher that imports it
+type LaboratoryBundleRubric struct {
+	Name       string                            `json:"name"`
+	Objectives []LaboratoryBundleRubricObjective `json:"objectives"`
+}
+
+type LaboratoryBundleRubricObjective struct {
+	Description string                           `json:"description"`
+	Criteria    []LaboratoryBundleRubricCriteria `json:"criteria"`
+}
+
+type LaboratoryBundleRubricCriteria struct {
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight"`
+}
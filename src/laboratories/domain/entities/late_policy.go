@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// LatePolicyNone submissions sent after the due date are rejected
+	LatePolicyNone = "none"
+
+	// LatePolicyGracePeriod submissions sent during the window are accepted without a penalty
+	LatePolicyGracePeriod = "grace_period"
+
+	// LatePolicyLateWindow submissions sent during the window are accepted with a penalty for
+	// each started hour or day
+	LatePolicyLateWindow = "late_window"
+)
+
+// LatePolicy rules applied to the submissions sent after the due date of a laboratory
+type LatePolicy struct {
+	Policy            string  `json:"policy"`
+	WindowMinutes     int     `json:"window_minutes"`
+	PenaltyPercentage float64 `json:"penalty_percentage"`
+	PenaltyUnit       *string `json:"penalty_unit"`
+}
+
+// GetLateMinutes returns the started minutes between the due date and the date the work was submitted
+func GetLateMinutes(dueDate time.Time, submittedAt time.Time) int {
+	if !submittedAt.After(dueDate) {
+		return 0
+	}
+
+	return int(math.Ceil(submittedAt.Sub(dueDate).Minutes()))
+}
+
+// AcceptsSubmission returns whether a submission sent the given minutes after the due date is accepted
+func (policy *LatePolicy) AcceptsSubmission(lateMinutes int) bool {
+	if lateMinutes <= 0 {
+		return true
+	}
+
+	if policy.Policy == LatePolicyNone {
+		return false
+	}
+
+	return lateMinutes <= policy.WindowMinutes
+}
+
+// GetPenaltyPercentage returns the percentage of the grade that is deducted for work submitted the given
+// minutes after the due date. Only the late window has a penalty and it never exceeds the whole grade
+func (policy *LatePolicy) GetPenaltyPercentage(lateMinutes int) float64 {
+	if lateMinutes <= 0 || policy.Policy != LatePolicyLateWindow || policy.PenaltyUnit == nil {
+		return 0
+	}
+
+	unitMinutes := 60
+	if *policy.PenaltyUnit == "day" {
+		unitMinutes = 24 * 60
+	}
+
+	// Every started unit is penalized
+	startedUnits := (lateMinutes + unitMinutes - 1) / unitMinutes
+	return math.Min(100, float64(startedUnits)*policy.PenaltyPercentage)
+}
@@ -1,5 +1,7 @@
 package entities
 
+import sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+
 type TestBlock struct {
 	UUID            string  `json:"uuid"`
 	LanguageUUID    string  `json:"language_uuid"`
@@ -7,4 +9,9 @@ type TestBlock struct {
 	SubmissionUUID  *string `json:"submission_uuid"`
 	Name            string  `json:"name"`
 	Index           int     `json:"index"`
+
+	// Limits applied to the submissions and the ones that replace the defaults of the language. Only
+	// included when the block is requested by its UUID
+	ExecutionLimits          *sharedEntities.ExecutionLimits          `json:"execution_limits,omitempty"`
+	ExecutionLimitsOverrides *sharedEntities.ExecutionLimitsOverrides `json:"execution_limits_overrides,omitempty"`
 }
@@ -33,3 +33,63 @@ func (err UserCannotAccessProgressSummaryError) Error() string {
 func (err UserCannotAccessProgressSummaryError) StatusCode() int {
 	return http.StatusForbidden
 }
+
+type DateOverrideNotFoundError struct{}
+
+func (err DateOverrideNotFoundError) Error() string {
+	return "The student does not have a date override in the laboratory"
+}
+
+func (err DateOverrideNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type StudentNotInLaboratoryCourseError struct{}
+
+func (err StudentNotInLaboratoryCourseError) Error() string {
+	return "The dates can only be overridden for the students of the course of the laboratory"
+}
+
+func (err StudentNotInLaboratoryCourseError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+type InvalidDateOverrideError struct{}
+
+func (err InvalidDateOverrideError) Error() string {
+	return "The overridden due date must be after the opening date the student would have"
+}
+
+func (err InvalidDateOverrideError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// InvalidLaboratoryBundleError the imported bundle can not be turned into a laboratory
+type InvalidLaboratoryBundleError struct {
+	Reason string
+}
+
+func (err InvalidLaboratoryBundleError) Error() string {
+	return "The laboratory bundle is not valid: " + err.Reason
+}
+
+func (err InvalidLaboratoryBundleError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// LaboratoryCannotBePublishedError the laboratory is not ready to be shown to the students
+type LaboratoryCannotBePublishedError struct {
+	Issues []string
+}
+
+func (err LaboratoryCannotBePublishedError) Error() string {
+	return "The laboratory can not be published, please fix the listed issues and try again"
+}
+
+func (err LaboratoryCannotBePublishedError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (err LaboratoryCannotBePublishedError) Details() []string {
+	return err.Issues
+}
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"archive/zip"
+	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
@@ -98,7 +101,8 @@ func (controller *LaboratoriesController) HandleGetLaboratory(c *gin.Context) {
 }
 
 func (controller *LaboratoriesController) HandleGetLaboratoryInformation(c *gin.Context) {
-	teacherUUID := c.GetString("session_uuid")
+	userUUID := c.GetString("session_uuid")
+	userRole := c.GetString("session_role")
 	laboratoryUUID := c.Param("laboratory_uuid")
 
 	// Validate the laboratory UUID
@@ -112,7 +116,8 @@ func (controller *LaboratoriesController) HandleGetLaboratoryInformation(c *gin.
 	// Get laboratory information
 	dto := dtos.GetLaboratoryDTO{
 		LaboratoryUUID: laboratoryUUID,
-		UserUUID:       teacherUUID,
+		UserUUID:       userUUID,
+		UserRole:       userRole,
 	}
 
 	information, err := controller.UseCases.GetLaboratoryInformation(&dto)
@@ -211,6 +216,48 @@ func (controller *LaboratoriesController) HandleGetLaboratoryProgress(c *gin.Con
 	c.JSON(http.StatusOK, progressDTO)
 }
 
+func (controller *LaboratoriesController) HandleGetLaboratorySubmissionsArchive(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Get the latest submissions of the laboratory
+	dto := dtos.GetLaboratorySubmissionsArchiveDTO{
+		LaboratoryUUID: laboratoryUUID,
+		TeacherUUID:    teacherUUID,
+	}
+
+	submissions, err := controller.UseCases.GetLaboratorySubmissionsArchives(&dto)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Stream the archive as it is being built
+	c.Header("Content-Type", "application/zip")
+	c.Header(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=\"%s-submissions.zip\"", laboratoryUUID),
+	)
+	c.Status(http.StatusOK)
+
+	// The status code was already sent, so errors can only be logged
+	if err := controller.UseCases.WriteLaboratorySubmissionsArchive(submissions, c.Writer); err != nil {
+		log.Printf(
+			"[Submissions archive]: Unable to write the archive of the laboratory %s: %s",
+			laboratoryUUID,
+			err.Error(),
+		)
+	}
+}
+
 func (controller *LaboratoriesController) HandleCreateMarkdownBlock(c *gin.Context) {
 	teacherUUID := c.GetString("session_uuid")
 	laboratoryUUID := c.Param("laboratory_uuid")
@@ -248,10 +295,20 @@ func (controller *LaboratoriesController) HandleCreateTestBlock(c *gin.Context)
 	languageUUID := c.PostForm("language_uuid")
 	name := c.PostForm("block_name")
 
+	executionLimits, err := infrastructure.GetExecutionLimitsRequestFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
 	req := requests.CreateTestBlockRequest{
-		LaboratoryUUID: laboratoryUUID,
-		LanguageUUID:   languageUUID,
-		Name:           name,
+		LaboratoryUUID:  laboratoryUUID,
+		LanguageUUID:    languageUUID,
+		Name:            name,
+		ExecutionLimits: executionLimits,
 	}
 
 	if err := infrastructure.GetValidator().Struct(req); err != nil {
@@ -287,11 +344,12 @@ func (controller *LaboratoriesController) HandleCreateTestBlock(c *gin.Context)
 	}
 
 	dto := dtos.CreateTestBlockDTO{
-		LaboratoryUUID: laboratoryUUID,
-		TeacherUUID:    teacherUUID,
-		LanguageUUID:   languageUUID,
-		Name:           name,
-		MultipartFile:  &multipartFile,
+		LaboratoryUUID:  laboratoryUUID,
+		TeacherUUID:     teacherUUID,
+		LanguageUUID:    languageUUID,
+		Name:            name,
+		MultipartFile:   &multipartFile,
+		ExecutionLimits: executionLimits.ToOverrides(),
 	}
 
 	// Create the block
@@ -340,3 +398,406 @@ func (controller *LaboratoriesController) HandleGetProgressOfStudentInLaboratory
 
 	c.JSON(http.StatusOK, progress)
 }
+
+func (controller *LaboratoriesController) HandleCloneLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Parse request body
+	var request requests.CloneLaboratoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Request body is not valid",
+		})
+		return
+	}
+
+	// Validate request body
+	if err := infrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Copy the laboratory
+	laboratory, err := controller.UseCases.CloneLaboratory(request.ToDTO(laboratoryUUID, teacherUUID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"uuid": laboratory.UUID,
+	})
+}
+
+func (controller *LaboratoriesController) HandleExportLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Describe the laboratory
+	bundle, err := controller.UseCases.GetLaboratoryBundle(&dtos.ExportLaboratoryDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+		IncludeRubric:  c.Query("include_rubric") == "true",
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Stream the bundle as it is being built
+	c.Header("Content-Type", "application/zip")
+	c.Header(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=\"%s-bundle.zip\"", laboratoryUUID),
+	)
+	c.Status(http.StatusOK)
+
+	// The status code was already sent, so errors can only be logged
+	if err := controller.UseCases.WriteLaboratoryBundle(bundle, c.Writer); err != nil {
+		log.Printf(
+			"[Laboratory bundles]: Unable to write the bundle of the laboratory %s: %s",
+			laboratoryUUID,
+			err.Error(),
+		)
+	}
+}
+
+func (controller *LaboratoriesController) HandleImportLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+
+	// Validate the request struct
+	request := requests.ImportLaboratoryRequest{
+		CourseUUID:  c.PostForm("course_uuid"),
+		OpeningDate: c.PostForm("opening_date"),
+	}
+	if name, ok := c.GetPostForm("name"); ok && name != "" {
+		request.Name = &name
+	}
+
+	if err := infrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Open the bundle
+	multipartHeader, err := c.FormFile("bundle")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Please, make sure to send the bundle",
+		})
+		return
+	}
+
+	maxSizeKb := infrastructure.GetEnvironment().LaboratoryBundleMaxSizeKb
+	if multipartHeader.Size > maxSizeKb*1024 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("The bundle must be less than %d KB", maxSizeKb),
+		})
+		return
+	}
+
+	multipartFile, err := multipartHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "There was an error while reading the bundle",
+		})
+		return
+	}
+	defer multipartFile.Close()
+
+	bundle, err := zip.NewReader(multipartFile, multipartHeader.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The bundle is not a valid .zip archive",
+		})
+		return
+	}
+
+	// Validate the manifest
+	manifestRequest, err := requests.GetLaboratoryBundleManifestRequest(bundle)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The manifest of the bundle is missing, is too large or is not valid JSON",
+		})
+		return
+	}
+
+	if err := infrastructure.GetValidator().Struct(manifestRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Create the laboratory
+	laboratory, err := controller.UseCases.ImportLaboratory(
+		request.ToDTO(teacherUUID, manifestRequest.ToEntity(), bundle),
+	)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"uuid": laboratory.UUID,
+	})
+}
+
+func (controller *LaboratoriesController) HandlePublishLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Publish the laboratory
+	err := controller.UseCases.PublishLaboratory(&dtos.SetLaboratoryPublicationDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LaboratoriesController) HandleUnpublishLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Hide the laboratory from the students
+	err := controller.UseCases.UnpublishLaboratory(&dtos.SetLaboratoryPublicationDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LaboratoriesController) HandleDeleteLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Hide the laboratory until it is restored or purged
+	err := controller.UseCases.DeleteLaboratory(&dtos.DeleteLaboratoryDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LaboratoriesController) HandleRestoreLaboratory(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	err := controller.UseCases.RestoreLaboratory(&dtos.RestoreLaboratoryDTO{
+		TeacherUUID:          teacherUUID,
+		LaboratoryUUID:       laboratoryUUID,
+		RestoreWindowSeconds: infrastructure.GetEnvironment().DeletionsRestoreWindowSeconds,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LaboratoriesController) HandleSetLatePolicy(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Parse request body
+	var request requests.SetLatePolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Request body is not valid",
+		})
+		return
+	}
+
+	// Validate request body
+	if err := infrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Replace the policy
+	err := controller.UseCases.SetLatePolicy(request.ToDTO(laboratoryUUID, teacherUUID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LaboratoriesController) HandleSetDateOverrides(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Parse request body
+	var request requests.SetDateOverridesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Request body is not valid",
+		})
+		return
+	}
+
+	// Validate request body
+	if err := infrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Grant the dates
+	err := controller.UseCases.SetDateOverrides(request.ToDTO(laboratoryUUID, teacherUUID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LaboratoriesController) HandleGetDateOverrides(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratory UUID
+	if err := infrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Laboratory UUID is not valid",
+		})
+		return
+	}
+
+	overrides, err := controller.UseCases.GetDateOverrides(&dtos.GetDateOverridesDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date_overrides": overrides,
+	})
+}
+
+func (controller *LaboratoriesController) HandleDeleteDateOverride(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+	studentUUID := c.Param("student_uuid")
+
+	// Validate the laboratory and student UUIDs
+	uuids := []string{laboratoryUUID, studentUUID}
+	for _, uuid := range uuids {
+		if err := infrastructure.GetValidator().Var(uuid, "uuid4"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "Please, make sure you are sending valid UUIDs",
+			})
+			return
+		}
+	}
+
+	err := controller.UseCases.DeleteDateOverride(&dtos.DeleteDateOverrideDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+		StudentUUID:    studentUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -16,12 +16,13 @@ func StartLaboratoriesRoutes(g *gin.RouterGroup) {
 	laboratoriesGroup := g.Group("/laboratories")
 
 	useCases := application.LaboratoriesUseCases{
-		StaticFilesRepository:  &staticFilesImplementations.StaticFilesMicroserviceImplementation{},
+		StaticFilesRepository:  staticFilesImplementations.GetStaticFilesRepositoryInstance(),
 		LaboratoriesRepository: implementations.GetLaboratoriesPostgresRepositoryInstance(),
 		CoursesRepository:      coursesImplementation.GetCoursesPgRepository(),
 		RubricsRepository:      rubricImplementation.GetRubricsPgRepository(),
 		LanguagesRepository:    languagesImplementation.GetLanguagesRepositoryInstance(),
 		BlocksRepository:       blocksImplementation.GetBlocksPostgresRepositoryInstance(),
+		ArchivesValidator:      staticFilesImplementations.GetZipArchivesValidatorInstance(),
 	}
 
 	controller := LaboratoriesController{
@@ -35,6 +36,13 @@ func StartLaboratoriesRoutes(g *gin.RouterGroup) {
 		controller.HandleCreateLaboratory,
 	)
 
+	laboratoriesGroup.POST(
+		"/import",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleImportLaboratory,
+	)
+
 	laboratoriesGroup.GET(
 		"/:laboratory_uuid",
 		infrastructure.WithAuthenticationMiddleware(),
@@ -63,6 +71,13 @@ func StartLaboratoriesRoutes(g *gin.RouterGroup) {
 		controller.HandleGetLaboratoryProgress,
 	)
 
+	laboratoriesGroup.GET(
+		"/:laboratory_uuid/submissions/archive",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleGetLaboratorySubmissionsArchive,
+	)
+
 	laboratoriesGroup.GET(
 		"/:laboratory_uuid/students/:student_uuid/progress",
 		infrastructure.WithAuthenticationMiddleware(),
@@ -70,6 +85,76 @@ func StartLaboratoriesRoutes(g *gin.RouterGroup) {
 		controller.HandleGetProgressOfStudentInLaboratory,
 	)
 
+	laboratoriesGroup.POST(
+		"/:laboratory_uuid/clone",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleCloneLaboratory,
+	)
+
+	laboratoriesGroup.GET(
+		"/:laboratory_uuid/bundle",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleExportLaboratory,
+	)
+
+	laboratoriesGroup.POST(
+		"/:laboratory_uuid/publish",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandlePublishLaboratory,
+	)
+
+	laboratoriesGroup.POST(
+		"/:laboratory_uuid/unpublish",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleUnpublishLaboratory,
+	)
+
+	laboratoriesGroup.DELETE(
+		"/:laboratory_uuid",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleDeleteLaboratory,
+	)
+
+	laboratoriesGroup.POST(
+		"/:laboratory_uuid/restore",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleRestoreLaboratory,
+	)
+
+	laboratoriesGroup.PUT(
+		"/:laboratory_uuid/late_policy",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleSetLatePolicy,
+	)
+
+	laboratoriesGroup.GET(
+		"/:laboratory_uuid/date_overrides",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleGetDateOverrides,
+	)
+
+	laboratoriesGroup.PUT(
+		"/:laboratory_uuid/date_overrides",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleSetDateOverrides,
+	)
+
+	laboratoriesGroup.DELETE(
+		"/:laboratory_uuid/date_overrides/:student_uuid",
+		infrastructure.WithAuthenticationMiddleware(),
+		infrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleDeleteDateOverride,
+	)
+
 	laboratoriesGroup.POST(
 		"/markdown_blocks/:laboratory_uuid",
 		infrastructure.WithAuthenticationMiddleware(),
@@ -3,11 +3,14 @@ package implementations
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
 	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
 	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
 	"github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 )
 
@@ -34,15 +37,23 @@ func (repository *LaboratoriesPostgresRepository) GetLaboratoryByUUID(dto *dtos.
 
 	// Get base laboratory data
 	query := `
-		SELECT id, course_id, rubric_id, name, opening_date, due_date
+		SELECT id, course_id, rubric_id, name, opening_date, due_date,
+			late_policy, late_window_minutes, late_penalty_percentage, late_penalty_unit, is_published
 		FROM laboratories
-		WHERE id = $1
+		WHERE id = $1 AND (is_published OR $2 <> 'student') AND deleted_at IS NULL
+		AND course_id NOT IN (SELECT id FROM courses WHERE deleted_at IS NOT NULL)
 	`
 
-	row := repository.Connection.QueryRowContext(ctx, query, dto.LaboratoryUUID)
+	// Draft laboratories do not exist for the students, deleted laboratories and the ones of deleted courses
+	// do not exist for anyone
+	row := repository.Connection.QueryRowContext(ctx, query, dto.LaboratoryUUID, dto.UserRole)
 	laboratory = &entities.Laboratory{}
 	rubricUUID := sql.NullString{}
-	if err := row.Scan(&laboratory.UUID, &laboratory.CourseUUID, &rubricUUID, &laboratory.Name, &laboratory.OpeningDate, &laboratory.DueDate); err != nil {
+	if err := row.Scan(
+		&laboratory.UUID, &laboratory.CourseUUID, &rubricUUID, &laboratory.Name, &laboratory.OpeningDate, &laboratory.DueDate,
+		&laboratory.LatePolicy.Policy, &laboratory.LatePolicy.WindowMinutes, &laboratory.LatePolicy.PenaltyPercentage, &laboratory.LatePolicy.PenaltyUnit,
+		&laboratory.IsPublished,
+	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.LaboratoryNotFoundError{}
 		}
@@ -81,9 +92,11 @@ func (repository *LaboratoriesPostgresRepository) GetLaboratoryInformationByUUID
 
 	// Get base laboratory data
 	query := `
-		SELECT id, rubric_id, course_id, name, opening_date, due_date
+		SELECT id, rubric_id, course_id, name, opening_date, due_date,
+			late_policy, late_window_minutes, late_penalty_percentage, late_penalty_unit, is_published
 		FROM laboratories
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
+		AND course_id NOT IN (SELECT id FROM courses WHERE deleted_at IS NOT NULL)
 	`
 
 	row := repository.Connection.QueryRowContext(ctx, query, uuid)
@@ -98,6 +111,11 @@ func (repository *LaboratoriesPostgresRepository) GetLaboratoryInformationByUUID
 		&laboratoryDetails.Name,
 		&laboratoryDetails.OpeningDate,
 		&laboratoryDetails.DueDate,
+		&laboratoryDetails.LatePolicy.Policy,
+		&laboratoryDetails.LatePolicy.WindowMinutes,
+		&laboratoryDetails.LatePolicy.PenaltyPercentage,
+		&laboratoryDetails.LatePolicy.PenaltyUnit,
+		&laboratoryDetails.IsPublished,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.LaboratoryNotFoundError{}
@@ -153,7 +171,7 @@ func (repository *LaboratoriesPostgresRepository) getTestBlocks(dto *dtos.GetLab
 		SELECT tb.id, tb.language_id, tb.test_archive_id, tb.name, bi.block_position, s.id
 		FROM test_blocks tb
 		RIGHT JOIN blocks_index bi ON tb.block_index_id = bi.id
-		LEFT JOIN submissions s ON tb.id = s.test_block_id AND s.student_id = $2
+		LEFT JOIN latest_submissions s ON tb.id = s.test_block_id AND s.student_id = $2
 		WHERE tb.laboratory_id = $1
 		ORDER BY bi.block_position ASC
 	`
@@ -234,6 +252,472 @@ func (repository *LaboratoriesPostgresRepository) UpdateLaboratory(dto *dtos.Upd
 	return err
 }
 
+// UpdateLatePolicy replaces the rules applied to the submissions sent after the due date of the laboratory
+func (repository *LaboratoriesPostgresRepository) UpdateLatePolicy(dto *dtos.SetLatePolicyDTO) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE laboratories
+		SET late_policy = $1, late_window_minutes = $2, late_penalty_percentage = $3, late_penalty_unit = $4
+		WHERE id = $5
+	`
+
+	_, err := repository.Connection.ExecContext(
+		ctx,
+		query,
+		dto.LatePolicy.Policy,
+		dto.LatePolicy.WindowMinutes,
+		dto.LatePolicy.PenaltyPercentage,
+		dto.LatePolicy.PenaltyUnit,
+		dto.LaboratoryUUID,
+	)
+	return err
+}
+
+// UpdateLaboratoryPublication publishes or hides the laboratory from the students of the course
+func (repository *LaboratoriesPostgresRepository) UpdateLaboratoryPublication(laboratoryUUID string, isPublished bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE laboratories
+		SET is_published = $1
+		WHERE id = $2
+	`
+
+	_, err := repository.Connection.ExecContext(ctx, query, isPublished, laboratoryUUID)
+	return err
+}
+
+// GetTestBlocksArchives returns the UUIDs of the test archives of the test blocks of the laboratory
+func (repository *LaboratoriesPostgresRepository) GetTestBlocksArchives(laboratoryUUID string) (archives []*dtos.TestBlockArchiveDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT tb.id, a.file_id
+		FROM test_blocks AS tb
+		INNER JOIN archives AS a ON tb.test_archive_id = a.id
+		WHERE tb.laboratory_id = $1
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, laboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	archives = []*dtos.TestBlockArchiveDTO{}
+	for rows.Next() {
+		archive := &dtos.TestBlockArchiveDTO{}
+		if err := rows.Scan(&archive.TestBlockUUID, &archive.ArchiveUUID); err != nil {
+			return nil, err
+		}
+
+		archives = append(archives, archive)
+	}
+
+	return archives, nil
+}
+
+// laboratoryCloneBlock block of the laboratory being cloned. Only one of the markdown content or the test
+// block UUID is set
+type laboratoryCloneBlock struct {
+	position        int
+	markdownContent *string
+	testBlockUUID   *string
+}
+
+// SaveLaboratoryClone copies the laboratory, its late policy, rubric and blocks (in the same order), along with
+// the grading rules of the test blocks, into the given course
+func (repository *LaboratoriesPostgresRepository) SaveLaboratoryClone(dto *dtos.SaveLaboratoryCloneDTO) (laboratoryUUID string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Start transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	// Copy the laboratory
+	query := `
+		INSERT INTO laboratories (
+			course_id, rubric_id, name, opening_date, due_date,
+			late_policy, late_window_minutes, late_penalty_percentage, late_penalty_unit
+		)
+		SELECT
+			$1, rubric_id, $2, $3, $4,
+			late_policy, late_window_minutes, late_penalty_percentage, late_penalty_unit
+		FROM laboratories
+		WHERE id = $5
+		RETURNING id
+	`
+
+	row := tx.QueryRowContext(ctx, query, dto.CourseUUID, dto.Name, dto.OpeningDate, dto.DueDate, dto.SourceLaboratoryUUID)
+	if err := row.Scan(&laboratoryUUID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.LaboratoryNotFoundError{}
+		}
+
+		return "", err
+	}
+
+	// Get the blocks of the laboratory. They are read before being copied, as the rows must be closed
+	// before running other queries in the transaction
+	query = `
+		SELECT bi.block_position, mb.content, tb.id
+		FROM blocks_index AS bi
+		LEFT JOIN markdown_blocks AS mb ON mb.block_index_id = bi.id
+		LEFT JOIN test_blocks AS tb ON tb.block_index_id = bi.id
+		WHERE bi.laboratory_id = $1
+		ORDER BY bi.block_position ASC
+	`
+
+	rows, err := tx.QueryContext(ctx, query, dto.SourceLaboratoryUUID)
+	if err != nil {
+		return "", err
+	}
+
+	blocks := []*laboratoryCloneBlock{}
+	for rows.Next() {
+		block := &laboratoryCloneBlock{}
+		if err := rows.Scan(&block.position, &block.markdownContent, &block.testBlockUUID); err != nil {
+			rows.Close()
+			return "", err
+		}
+
+		blocks = append(blocks, block)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	// Copy the blocks
+	for _, block := range blocks {
+		query = `
+			INSERT INTO blocks_index (laboratory_id, block_position)
+			VALUES ($1, $2)
+			RETURNING id
+		`
+
+		var blockIndexUUID string
+		if err := tx.QueryRowContext(ctx, query, laboratoryUUID, block.position).Scan(&blockIndexUUID); err != nil {
+			return "", err
+		}
+
+		if block.markdownContent != nil {
+			query = `
+				INSERT INTO markdown_blocks (laboratory_id, block_index_id, content)
+				VALUES ($1, $2, $3)
+			`
+
+			if _, err := tx.ExecContext(ctx, query, laboratoryUUID, blockIndexUUID, *block.markdownContent); err != nil {
+				return "", err
+			}
+
+			continue
+		}
+
+		if block.testBlockUUID == nil {
+			continue
+		}
+
+		// Test blocks reference the copy of their archive
+		archiveCopyUUID, ok := dto.TestArchivesCopies[*block.testBlockUUID]
+		if !ok {
+			return "", fmt.Errorf("the test archive of the block %s was not copied", *block.testBlockUUID)
+		}
+
+		query = `
+			INSERT INTO archives (file_id, archive_type)
+			VALUES ($1, 'test')
+			RETURNING id
+		`
+
+		var testArchiveUUID string
+		if err := tx.QueryRowContext(ctx, query, archiveCopyUUID).Scan(&testArchiveUUID); err != nil {
+			return "", err
+		}
+
+		query = `
+			INSERT INTO test_blocks (
+				language_id, test_archive_id, laboratory_id, block_index_id, name,
+				cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
+			)
+			SELECT
+				language_id, $1, $2, $3, name,
+				cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
+			FROM test_blocks
+			WHERE id = $4
+			RETURNING id
+		`
+
+		var testBlockUUID string
+		row := tx.QueryRowContext(ctx, query, testArchiveUUID, laboratoryUUID, blockIndexUUID, *block.testBlockUUID)
+		if err := row.Scan(&testBlockUUID); err != nil {
+			return "", err
+		}
+
+		// Copy the grading rules of the block, the clone keeps the rubric so the objectives and criteria
+		// are still valid
+		query = `
+			INSERT INTO grading_rules (test_block_id, objective_id, criteria_id, condition, min_tests_ratio)
+			SELECT $1, objective_id, criteria_id, condition, min_tests_ratio
+			FROM grading_rules
+			WHERE test_block_id = $2
+		`
+
+		if _, err := tx.ExecContext(ctx, query, testBlockUUID, *block.testBlockUUID); err != nil {
+			return "", err
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return laboratoryUUID, nil
+}
+
+// GetLaboratoryBundleBlocks returns the blocks of the laboratory in the order they are shown, along with the
+// name of the language and the archive of the test blocks
+func (repository *LaboratoriesPostgresRepository) GetLaboratoryBundleBlocks(laboratoryUUID string) (blocks []*dtos.LaboratoryBundleBlockDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			mb.content, tb.name, l.name, a.file_id,
+			tb.cpu_time_limit_ms, tb.wall_time_limit_ms, tb.memory_limit_mb, tb.output_size_limit_kb
+		FROM blocks_index AS bi
+		LEFT JOIN markdown_blocks AS mb ON mb.block_index_id = bi.id
+		LEFT JOIN test_blocks AS tb ON tb.block_index_id = bi.id
+		LEFT JOIN languages AS l ON tb.language_id = l.id
+		LEFT JOIN archives AS a ON tb.test_archive_id = a.id
+		WHERE bi.laboratory_id = $1
+		ORDER BY bi.block_position ASC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, laboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks = []*dtos.LaboratoryBundleBlockDTO{}
+	for rows.Next() {
+		var markdownContent, testBlockName, languageName, testArchiveUUID *string
+		limits := &sharedEntities.ExecutionLimitsOverrides{}
+
+		if err := rows.Scan(
+			&markdownContent,
+			&testBlockName,
+			&languageName,
+			&testArchiveUUID,
+			&limits.CPUTimeMs,
+			&limits.WallTimeMs,
+			&limits.MemoryMb,
+			&limits.OutputSizeKb,
+		); err != nil {
+			return nil, err
+		}
+
+		if markdownContent != nil {
+			blocks = append(blocks, &dtos.LaboratoryBundleBlockDTO{
+				Type:            entities.LaboratoryBundleMarkdownBlock,
+				MarkdownContent: *markdownContent,
+			})
+			continue
+		}
+
+		if testBlockName == nil || languageName == nil || testArchiveUUID == nil {
+			continue
+		}
+
+		blocks = append(blocks, &dtos.LaboratoryBundleBlockDTO{
+			Type:            entities.LaboratoryBundleTestBlock,
+			TestBlockName:   *testBlockName,
+			LanguageName:    *languageName,
+			ExecutionLimits: limits,
+			TestArchiveUUID: *testArchiveUUID,
+		})
+	}
+
+	return blocks, rows.Err()
+}
+
+// SaveLaboratoryImport creates the laboratory of a bundle in the given course, along with its blocks (in the
+// same order) and a new rubric for the teacher, if the bundle has one
+func (repository *LaboratoriesPostgresRepository) SaveLaboratoryImport(dto *dtos.SaveLaboratoryImportDTO) (laboratoryUUID string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Start transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	// Create the rubric
+	var rubricUUID *string
+	if dto.Rubric != nil {
+		query := `
+			INSERT INTO rubrics (teacher_id, name)
+			VALUES ($1, $2)
+			RETURNING id
+		`
+
+		rubricUUID = new(string)
+		if err := tx.QueryRowContext(ctx, query, dto.TeacherUUID, dto.Rubric.Name).Scan(rubricUUID); err != nil {
+			return "", err
+		}
+
+		for _, objective := range dto.Rubric.Objectives {
+			query = `
+				INSERT INTO objectives (rubric_id, description)
+				VALUES ($1, $2)
+				RETURNING id
+			`
+
+			var objectiveUUID string
+			if err := tx.QueryRowContext(ctx, query, *rubricUUID, objective.Description).Scan(&objectiveUUID); err != nil {
+				return "", err
+			}
+
+			for _, criteria := range objective.Criteria {
+				query = `
+					INSERT INTO criteria (objective_id, description, weight)
+					VALUES ($1, $2, $3)
+				`
+
+				if _, err := tx.ExecContext(ctx, query, objectiveUUID, criteria.Description, criteria.Weight); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+
+	// Create the laboratory
+	query := `
+		INSERT INTO laboratories (
+			course_id, rubric_id, name, opening_date, due_date,
+			late_policy, late_window_minutes, late_penalty_percentage, late_penalty_unit
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	row := tx.QueryRowContext(
+		ctx,
+		query,
+		dto.CourseUUID,
+		rubricUUID,
+		dto.Name,
+		dto.OpeningDate,
+		dto.DueDate,
+		dto.LatePolicy.Policy,
+		dto.LatePolicy.WindowMinutes,
+		dto.LatePolicy.PenaltyPercentage,
+		dto.LatePolicy.PenaltyUnit,
+	)
+	if err := row.Scan(&laboratoryUUID); err != nil {
+		return "", err
+	}
+
+	// Create the blocks
+	for index, block := range dto.Blocks {
+		query = `
+			INSERT INTO blocks_index (laboratory_id, block_position)
+			VALUES ($1, $2)
+			RETURNING id
+		`
+
+		var blockIndexUUID string
+		if err := tx.QueryRowContext(ctx, query, laboratoryUUID, index+1).Scan(&blockIndexUUID); err != nil {
+			return "", err
+		}
+
+		if block.Type == entities.LaboratoryBundleMarkdownBlock {
+			query = `
+				INSERT INTO markdown_blocks (laboratory_id, block_index_id, content)
+				VALUES ($1, $2, $3)
+			`
+
+			if _, err := tx.ExecContext(ctx, query, laboratoryUUID, blockIndexUUID, block.Content); err != nil {
+				return "", err
+			}
+
+			continue
+		}
+
+		// Test blocks reference the archive saved for them
+		archiveUUID, ok := dto.TestArchivesUUIDs[index]
+		if !ok {
+			return "", fmt.Errorf("the test archive of the block %d was not saved", index)
+		}
+
+		languageUUID, ok := dto.LanguagesUUIDs[block.Language]
+		if !ok {
+			return "", fmt.Errorf("the language %s of the block %d was not found", block.Language, index)
+		}
+
+		query = `
+			INSERT INTO archives (file_id, archive_type)
+			VALUES ($1, 'test')
+			RETURNING id
+		`
+
+		var testArchiveUUID string
+		if err := tx.QueryRowContext(ctx, query, archiveUUID).Scan(&testArchiveUUID); err != nil {
+			return "", err
+		}
+
+		limits := block.ExecutionLimits
+		if limits == nil {
+			limits = &sharedEntities.ExecutionLimitsOverrides{}
+		}
+
+		query = `
+			INSERT INTO test_blocks (
+				language_id, test_archive_id, laboratory_id, block_index_id, name,
+				cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`
+
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			languageUUID,
+			testArchiveUUID,
+			laboratoryUUID,
+			blockIndexUUID,
+			block.Name,
+			limits.CPUTimeMs,
+			limits.WallTimeMs,
+			limits.MemoryMb,
+			limits.OutputSizeKb,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return laboratoryUUID, nil
+}
+
 func (repository *LaboratoriesPostgresRepository) CreateMarkdownBlock(laboratoryUUID string) (blockUUID string, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
@@ -311,8 +795,8 @@ func (repository *LaboratoriesPostgresRepository) CreateTestBlock(dto *dtos.Crea
 
 	// Save the archive metadata
 	query = `
-		INSERT INTO archives (file_id)
-		VALUES ($1)
+		INSERT INTO archives (file_id, archive_type)
+		VALUES ($1, 'test')
 		RETURNING id
 	`
 
@@ -324,9 +808,17 @@ func (repository *LaboratoriesPostgresRepository) CreateTestBlock(dto *dtos.Crea
 	}
 
 	// Create test block
+	limits := dto.ExecutionLimits
+	if limits == nil {
+		limits = &sharedEntities.ExecutionLimitsOverrides{}
+	}
+
 	query = `
-		INSERT INTO test_blocks (language_id, test_archive_id, laboratory_id, block_index_id, name)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO test_blocks (
+			language_id, test_archive_id, laboratory_id, block_index_id, name,
+			cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
 	`
 
@@ -338,6 +830,10 @@ func (repository *LaboratoriesPostgresRepository) CreateTestBlock(dto *dtos.Crea
 		dto.LaboratoryUUID,
 		dbBlockIndexUUID,
 		dto.Name,
+		limits.CPUTimeMs,
+		limits.WallTimeMs,
+		limits.MemoryMb,
+		limits.OutputSizeKb,
 	)
 
 	var createdTestBlockUUID string
@@ -427,7 +923,7 @@ func (repository *LaboratoriesPostgresRepository) DoesTeacherOwnLaboratory(teach
 		SELECT l.id, c.teacher_id
 		FROM laboratories AS l
 		INNER JOIN courses AS c ON l.course_id = c.id
-		WHERE l.id = $1
+		WHERE l.id = $1 AND l.deleted_at IS NULL AND c.deleted_at IS NULL
 	`
 
 	row := repository.Connection.QueryRowContext(ctx, query, laboratoryUUID)
@@ -444,14 +940,87 @@ func (repository *LaboratoriesPostgresRepository) DoesTeacherOwnLaboratory(teach
 	return teacherID == teacherUUID, nil
 }
 
+// DeleteLaboratory hides the laboratory until it is restored or purged
+func (repository *LaboratoriesPostgresRepository) DeleteLaboratory(laboratoryUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE laboratories
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	_, err := repository.Connection.ExecContext(ctx, query, laboratoryUUID)
+	return err
+}
+
+// DoesTeacherOwnDeletedLaboratory returns true if the teacher owns the deleted laboratory and throws an error
+// if the laboratory can not be restored: it was not deleted, its restore window passed or its course is deleted
+func (repository *LaboratoriesPostgresRepository) DoesTeacherOwnDeletedLaboratory(teacherUUID string, laboratoryUUID string, restoreWindowSeconds int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT c.teacher_id
+		FROM laboratories AS l
+		INNER JOIN courses AS c ON l.course_id = c.id
+		WHERE l.id = $1
+		AND l.deleted_at > NOW() - make_interval(secs => $2)
+		AND c.deleted_at IS NULL
+	`
+
+	row := repository.Connection.QueryRowContext(ctx, query, laboratoryUUID, restoreWindowSeconds)
+
+	var teacherID string
+	if err := row.Scan(&teacherID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, errors.LaboratoryNotFoundError{}
+		}
+
+		return false, err
+	}
+
+	return teacherID == teacherUUID, nil
+}
+
+// RestoreLaboratory shows the laboratory again
+func (repository *LaboratoriesPostgresRepository) RestoreLaboratory(laboratoryUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE laboratories
+		SET deleted_at = NULL
+		WHERE id = $1
+	`
+
+	_, err := repository.Connection.ExecContext(ctx, query, laboratoryUUID)
+	return err
+}
+
 // GetStudentSubmissions returns the submissions of a student in a laboratory
 func (repository *LaboratoriesPostgresRepository) GetStudentSubmissions(laboratoryUUID string, studentUUID string) (submissions []*dtos.SummarizedStudentSubmissionDTO, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	query := `
-		SELECT s.id, s.archive_id, tb.name, s.status, s.passing
-		FROM submissions AS s
+		SELECT s.id, s.archive_id, tb.name, s.status, s.passing, s.late_minutes, COALESCE(
+			(
+				SELECT json_agg(
+					json_build_object(
+						'name', str.name,
+						'status', str.status,
+						'duration_ms', str.duration_ms,
+						'failure_message', str.failure_message
+					) ORDER BY str.position ASC
+				)
+				FROM submission_test_results AS str
+				WHERE str.submission_id = s.id
+			),
+			'[]'
+		)
+		FROM latest_submissions AS s
 		INNER JOIN test_blocks AS tb ON s.test_block_id = tb.id
 		WHERE tb.laboratory_id = $1 AND s.student_id = $2
 	`
@@ -469,6 +1038,7 @@ func (repository *LaboratoriesPostgresRepository) GetStudentSubmissions(laborato
 	submissions = []*dtos.SummarizedStudentSubmissionDTO{}
 	for rows.Next() {
 		submission := dtos.SummarizedStudentSubmissionDTO{}
+		var testResultsJSON []byte
 
 		if err := rows.Scan(
 			&submission.SubmissionUUID,
@@ -476,12 +1046,179 @@ func (repository *LaboratoriesPostgresRepository) GetStudentSubmissions(laborato
 			&submission.TestBlockName,
 			&submission.SubmissionStatus,
 			&submission.IsSubmissionPassing,
+			&submission.LateMinutes,
+			&testResultsJSON,
 		); err != nil {
 			return nil, err
 		}
 
+		if err := json.Unmarshal(testResultsJSON, &submission.TestResults); err != nil {
+			return nil, err
+		}
+
 		submissions = append(submissions, &submission)
 	}
 
 	return submissions, nil
 }
+
+// GetLatestSubmissionsArchives returns the information needed to download the archive of the latest
+// submission of each student in each test block of a laboratory
+func (repository *LaboratoriesPostgresRepository) GetLatestSubmissionsArchives(laboratoryUUID string) (submissions []*dtos.LaboratorySubmissionArchiveDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT s.id, a.file_id, u.id, u.institutional_id, u.full_name, tb.id, tb.name, s.status, s.passing, s.submitted_at
+		FROM latest_submissions AS s
+		INNER JOIN test_blocks AS tb ON s.test_block_id = tb.id
+		INNER JOIN users AS u ON s.student_id = u.id
+		INNER JOIN archives AS a ON s.archive_id = a.id
+		WHERE tb.laboratory_id = $1
+		ORDER BY u.full_name ASC, u.id ASC, tb.name ASC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, laboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submissions = []*dtos.LaboratorySubmissionArchiveDTO{}
+	for rows.Next() {
+		submission := dtos.LaboratorySubmissionArchiveDTO{}
+		var institutionalID sql.NullString
+
+		if err := rows.Scan(
+			&submission.SubmissionUUID,
+			&submission.ArchiveUUID,
+			&submission.StudentUUID,
+			&institutionalID,
+			&submission.StudentFullName,
+			&submission.TestBlockUUID,
+			&submission.TestBlockName,
+			&submission.SubmissionStatus,
+			&submission.IsSubmissionPassing,
+			&submission.SubmittedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		submission.StudentInstitutionalID = institutionalID.String
+		submissions = append(submissions, &submission)
+	}
+
+	return submissions, nil
+}
+
+// SaveDateOverrides grants the dates to every given student, replacing their previous overrides
+func (repository *LaboratoriesPostgresRepository) SaveDateOverrides(dto *dtos.SetDateOverridesDTO) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO laboratories_date_overrides (laboratory_id, student_id, opening_date, due_date)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (laboratory_id, student_id) DO UPDATE
+		SET opening_date = EXCLUDED.opening_date, due_date = EXCLUDED.due_date
+	`
+
+	for _, studentUUID := range dto.StudentsUUIDs {
+		_, err = tx.ExecContext(ctx, query, dto.LaboratoryUUID, studentUUID, dto.OpeningDate, dto.DueDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repository *LaboratoriesPostgresRepository) GetDateOverrides(laboratoryUUID string) (overrides []*entities.DateOverride, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT o.student_id, u.full_name, o.opening_date, o.due_date
+		FROM laboratories_date_overrides AS o
+		INNER JOIN users AS u ON o.student_id = u.id
+		WHERE o.laboratory_id = $1
+		ORDER BY u.full_name
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, laboratoryUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides = []*entities.DateOverride{}
+	for rows.Next() {
+		override := &entities.DateOverride{}
+		err := rows.Scan(&override.StudentUUID, &override.StudentFullName, &override.OpeningDate, &override.DueDate)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides = append(overrides, override)
+	}
+
+	return overrides, nil
+}
+
+func (repository *LaboratoriesPostgresRepository) GetStudentDateOverride(laboratoryUUID string, studentUUID string) (override *entities.DateOverride, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT o.student_id, u.full_name, o.opening_date, o.due_date
+		FROM laboratories_date_overrides AS o
+		INNER JOIN users AS u ON o.student_id = u.id
+		WHERE o.laboratory_id = $1 AND o.student_id = $2
+	`
+
+	override = &entities.DateOverride{}
+	err = repository.Connection.QueryRowContext(ctx, query, laboratoryUUID, studentUUID).Scan(
+		&override.StudentUUID, &override.StudentFullName, &override.OpeningDate, &override.DueDate,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return override, nil
+}
+
+func (repository *LaboratoriesPostgresRepository) DeleteDateOverride(laboratoryUUID string, studentUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		DELETE FROM laboratories_date_overrides
+		WHERE laboratory_id = $1 AND student_id = $2
+	`
+
+	result, err := repository.Connection.ExecContext(ctx, query, laboratoryUUID, studentUUID)
+	if err != nil {
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return errors.DateOverrideNotFoundError{}
+	}
+
+	return nil
+}
@@ -1,9 +1,15 @@
 package requests
 
 import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 )
 
 type CreateLaboratoryRequest struct {
@@ -47,8 +53,242 @@ func (request *UpdateLaboratoryRequest) ToDTO(laboratoryUUID string, teacherUUID
 	}
 }
 
+type CloneLaboratoryRequest struct {
+	CourseUUID  string  `json:"course_uuid" validate:"required,uuid4"`
+	Name        *string `json:"name" validate:"omitempty,min=4,max=255"`
+	OpeningDate string  `json:"opening_date" validate:"required,RFC3339_date"`
+}
+
+func (request *CloneLaboratoryRequest) ToDTO(laboratoryUUID string, teacherUUID string) *dtos.CloneLaboratoryDTO {
+	parsedOpeningDate, _ := time.Parse(time.RFC3339, request.OpeningDate)
+
+	return &dtos.CloneLaboratoryDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+		CourseUUID:     request.CourseUUID,
+		Name:           request.Name,
+		OpeningDate:    parsedOpeningDate,
+	}
+}
+
+type ImportLaboratoryRequest struct {
+	CourseUUID  string  `validate:"required,uuid4"`
+	Name        *string `validate:"omitempty,min=4,max=255"`
+	OpeningDate string  `validate:"required,RFC3339_date"`
+}
+
+func (request *ImportLaboratoryRequest) ToDTO(teacherUUID string, manifest *entities.LaboratoryBundleManifest, bundle *zip.Reader) *dtos.ImportLaboratoryDTO {
+	parsedOpeningDate, _ := time.Parse(time.RFC3339, request.OpeningDate)
+
+	return &dtos.ImportLaboratoryDTO{
+		TeacherUUID:      teacherUUID,
+		CourseUUID:       request.CourseUUID,
+		Name:             request.Name,
+		OpeningDate:      parsedOpeningDate,
+		Manifest:         manifest,
+		Bundle:           bundle,
+		ArchiveMaxSizeKb: sharedInfrastructure.GetEnvironment().ArchiveMaxSizeKb,
+	}
+}
+
+// LaboratoryBundleManifestRequest manifest of an imported bundle. See `entities.LaboratoryBundleManifest`
+type LaboratoryBundleManifestRequest struct {
+	Version         int                  `json:"version" validate:"required"`
+	Name            string               `json:"name" validate:"required,min=4,max=255"`
+	DurationMinutes int                  `json:"duration_minutes" validate:"required,min=1"`
+	LatePolicy      SetLatePolicyRequest `json:"late_policy"`
+
+	Blocks []LaboratoryBundleBlockRequest `json:"blocks" validate:"max=500,dive"`
+	Rubric *LaboratoryBundleRubricRequest `json:"rubric" validate:"omitempty"`
+}
+
+type LaboratoryBundleBlockRequest struct {
+	Type    string `json:"type" validate:"required,oneof=markdown test"`
+	Content string `json:"content" validate:"max=65535"`
+
+	Name            string                                       `json:"name" validate:"required_if=Type test,omitempty,min=4,max=255"`
+	Language        string                                       `json:"language" validate:"required_if=Type test"`
+	ExecutionLimits *sharedInfrastructure.ExecutionLimitsRequest `json:"execution_limits"`
+	TestArchive     string                                       `json:"test_archive" validate:"required_if=Type test"`
+}
+
+type LaboratoryBundleRubricRequest struct {
+	Name       string                                   `json:"name" validate:"required,min=4,max=96"`
+	Objectives []LaboratoryBundleRubricObjectiveRequest `json:"objectives" validate:"dive"`
+}
+
+type LaboratoryBundleRubricObjectiveRequest struct {
+	Description string                                  `json:"description" validate:"required,min=8,max=510"`
+	Criteria    []LaboratoryBundleRubricCriteriaRequest `json:"criteria" validate:"dive"`
+}
+
+type LaboratoryBundleRubricCriteriaRequest struct {
+	Description string  `json:"description" validate:"required,min=8,max=510"`
+	Weight      float64 `json:"weight" validate:"min=0,max=100"`
+}
+
+// Maximum size of the manifest of a bundle once uncompressed, in bytes
+const laboratoryBundleManifestMaxSize = 2 << 20
+
+// GetLaboratoryBundleManifestRequest reads the manifest of the bundle. The bundles without a late policy reject
+// the late submissions
+func GetLaboratoryBundleManifestRequest(bundle *zip.Reader) (*LaboratoryBundleManifestRequest, error) {
+	manifestFile, err := bundle.Open(entities.LaboratoryBundleManifestName)
+	if err != nil {
+		return nil, err
+	}
+	defer manifestFile.Close()
+
+	// Reject the manifests that declare a large size before decompressing them
+	manifestInfo, err := manifestFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if manifestInfo.Size() > laboratoryBundleManifestMaxSize {
+		return nil, fmt.Errorf("the manifest is larger than %d bytes", laboratoryBundleManifestMaxSize)
+	}
+
+	// The declared size may not match the content, so the manifest is never read past the limit
+	request := &LaboratoryBundleManifestRequest{
+		LatePolicy: SetLatePolicyRequest{Policy: entities.LatePolicyNone},
+	}
+	manifestReader := io.LimitReader(manifestFile, laboratoryBundleManifestMaxSize)
+	if err := json.NewDecoder(manifestReader).Decode(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (request *LaboratoryBundleManifestRequest) ToEntity() *entities.LaboratoryBundleManifest {
+	manifest := &entities.LaboratoryBundleManifest{
+		Version:         request.Version,
+		Name:            request.Name,
+		DurationMinutes: request.DurationMinutes,
+		LatePolicy:      request.LatePolicy.ToEntity(),
+		Blocks:          []entities.LaboratoryBundleBlock{},
+	}
+
+	// Only keep the fields that apply to the type of each block
+	for _, block := range request.Blocks {
+		if block.Type == entities.LaboratoryBundleMarkdownBlock {
+			manifest.Blocks = append(manifest.Blocks, entities.LaboratoryBundleBlock{
+				Type:    block.Type,
+				Content: block.Content,
+			})
+			continue
+		}
+
+		bundleBlock := entities.LaboratoryBundleBlock{
+			Type:        block.Type,
+			Name:        block.Name,
+			Language:    block.Language,
+			TestArchive: block.TestArchive,
+		}
+		if block.ExecutionLimits != nil {
+			bundleBlock.ExecutionLimits = block.ExecutionLimits.ToOverrides()
+		}
+
+		manifest.Blocks = append(manifest.Blocks, bundleBlock)
+	}
+
+	if request.Rubric != nil {
+		manifest.Rubric = &entities.LaboratoryBundleRubric{
+			Name:       request.Rubric.Name,
+			Objectives: []entities.LaboratoryBundleRubricObjective{},
+		}
+
+		for _, objective := range request.Rubric.Objectives {
+			bundleObjective := entities.LaboratoryBundleRubricObjective{
+				Description: objective.Description,
+				Criteria:    []entities.LaboratoryBundleRubricCriteria{},
+			}
+
+			for _, criteria := range objective.Criteria {
+				bundleObjective.Criteria = append(bundleObjective.Criteria, entities.LaboratoryBundleRubricCriteria{
+					Description: criteria.Description,
+					Weight:      criteria.Weight,
+				})
+			}
+
+			manifest.Rubric.Objectives = append(manifest.Rubric.Objectives, bundleObjective)
+		}
+	}
+
+	return manifest
+}
+
+type SetLatePolicyRequest struct {
+	Policy string `json:"policy" validate:"required,oneof=none grace_period late_window"`
+
+	// Length of the grace period or the late window, up to 30 days
+	WindowMinutes int `json:"window_minutes" validate:"required_unless=Policy none,min=0,max=43200"`
+
+	// Percentage deducted from the grade for each started hour or day of the late window
+	PenaltyPercentage float64 `json:"penalty_percentage" validate:"required_if=Policy late_window,min=0,max=100"`
+	PenaltyUnit       *string `json:"penalty_unit" validate:"required_if=Policy late_window,omitempty,oneof=hour day"`
+}
+
+func (request *SetLatePolicyRequest) ToDTO(laboratoryUUID string, teacherUUID string) *dtos.SetLatePolicyDTO {
+	return &dtos.SetLatePolicyDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+		LatePolicy:     request.ToEntity(),
+	}
+}
+
+func (request *SetLatePolicyRequest) ToEntity() entities.LatePolicy {
+	latePolicy := entities.LatePolicy{
+		Policy: request.Policy,
+	}
+
+	// Only keep the fields that apply to the policy
+	if request.Policy != entities.LatePolicyNone {
+		latePolicy.WindowMinutes = request.WindowMinutes
+	}
+
+	if request.Policy == entities.LatePolicyLateWindow {
+		latePolicy.PenaltyPercentage = request.PenaltyPercentage
+		latePolicy.PenaltyUnit = request.PenaltyUnit
+	}
+
+	return latePolicy
+}
+
+type SetDateOverridesRequest struct {
+	StudentsUUIDs []string `json:"students_uuids" validate:"required,min=1,max=500,unique,dive,uuid4"`
+
+	// At least one of the dates must be overridden
+	OpeningDate *string `json:"opening_date" validate:"required_without=DueDate,omitempty,RFC3339_date"`
+	DueDate     *string `json:"due_date" validate:"required_without=OpeningDate,omitempty,RFC3339_date"`
+}
+
+func (request *SetDateOverridesRequest) ToDTO(laboratoryUUID string, teacherUUID string) *dtos.SetDateOverridesDTO {
+	dto := &dtos.SetDateOverridesDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+		StudentsUUIDs:  request.StudentsUUIDs,
+	}
+
+	if request.OpeningDate != nil {
+		parsedOpeningDate, _ := time.Parse(time.RFC3339, *request.OpeningDate)
+		dto.OpeningDate = &parsedOpeningDate
+	}
+
+	if request.DueDate != nil {
+		parsedDueDate, _ := time.Parse(time.RFC3339, *request.DueDate)
+		dto.DueDate = &parsedDueDate
+	}
+
+	return dto
+}
+
 type CreateTestBlockRequest struct {
 	LaboratoryUUID string `validate:"required,uuid4"`
 	LanguageUUID   string `validate:"required,uuid4"`
 	Name           string `validate:"required,min=4,max=255"`
+
+	// Limits that replace the defaults of the language
+	ExecutionLimits *sharedInfrastructure.ExecutionLimitsRequest
 }
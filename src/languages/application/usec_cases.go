@@ -2,12 +2,17 @@ package application
 
 import (
 	"github.com/UPB-Code-Labs/main-api/src/languages/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/languages/domain/dtos"
 	"github.com/UPB-Code-Labs/main-api/src/languages/domain/entities"
+	"github.com/UPB-Code-Labs/main-api/src/languages/domain/errors"
+	staticFilesApplication "github.com/UPB-Code-Labs/main-api/src/static-files/application"
 	staticFilesDefinitions "github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
+	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
 )
 
 type LanguageUseCases struct {
 	StaticFilesRepository staticFilesDefinitions.StaticFilesRepository
+	ArchivesValidator     staticFilesDefinitions.ArchivesValidator
 	LanguageRepository    definitions.LanguagesRepository
 }
 
@@ -15,13 +20,124 @@ func (useCases *LanguageUseCases) GetLanguages() ([]*entities.Language, error) {
 	return useCases.LanguageRepository.GetAll()
 }
 
-func (useCases *LanguageUseCases) GetLanguageTemplate(uuid string) ([]byte, error) {
+func (useCases *LanguageUseCases) GetLanguageTemplate(uuid string) (*staticFilesDTOs.StaticFileStreamDTO, error) {
 	// Get the information of the language from the database
 	langTemplateUUID, err := useCases.LanguageRepository.GetTemplateArchiveUUIDByLanguageUUID(uuid)
 	if err != nil {
 		return nil, err
 	}
 
-	// Return an empty template bytes array
-	return useCases.StaticFilesRepository.GetLanguageTemplateArchiveBytes(langTemplateUUID)
+	// Return the content of the template archive
+	return useCases.StaticFilesRepository.GetLanguageTemplateArchiveStream(langTemplateUUID)
+}
+
+// CreateLanguage saves the template of a new language and creates it. Returns the UUID of the language
+func (useCases *LanguageUseCases) CreateLanguage(dto *dtos.CreateLanguageDTO) (string, error) {
+	// Check the name is not in use
+	err := useCases.checkLanguageNameIsAvailable(dto.Name, "")
+	if err != nil {
+		return "", err
+	}
+
+	// The template is the base of the students' projects, so it must follow the language layout too
+	err = useCases.ArchivesValidator.ValidateArchive(&staticFilesDTOs.ValidateArchiveDTO{
+		File:          dto.TemplateArchive,
+		RequiredPaths: dto.RequiredPaths,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Save the template archive
+	templateArchiveUUID, err := useCases.StaticFilesRepository.SaveArchive(&staticFilesDTOs.SaveStaticFileDTO{
+		FileType: "template",
+		File:     dto.TemplateArchive,
+	})
+	if err != nil {
+		return "", err
+	}
+	dto.TemplateArchiveUUID = templateArchiveUUID
+
+	// Save the language
+	languageUUID, err := useCases.LanguageRepository.CreateLanguage(dto)
+	if err != nil {
+		staticFilesApplication.DeleteUnreferencedArchive(useCases.StaticFilesRepository, templateArchiveUUID, "template", "Languages")
+
+		return "", err
+	}
+
+	return languageUUID, nil
+}
+
+// UpdateLanguageTemplate replaces the template archive of a language
+func (useCases *LanguageUseCases) UpdateLanguageTemplate(dto *dtos.UpdateLanguageTemplateDTO) error {
+	language, err := useCases.LanguageRepository.GetByUUID(dto.LanguageUUID)
+	if err != nil {
+		return err
+	}
+
+	err = useCases.ArchivesValidator.ValidateArchive(&staticFilesDTOs.ValidateArchiveDTO{
+		File:          dto.TemplateArchive,
+		RequiredPaths: language.RequiredPaths,
+	})
+	if err != nil {
+		return err
+	}
+
+	templateArchiveUUID, err := useCases.LanguageRepository.GetTemplateArchiveUUIDByLanguageUUID(dto.LanguageUUID)
+	if err != nil {
+		return err
+	}
+
+	return useCases.StaticFilesRepository.OverwriteArchive(&staticFilesDTOs.OverwriteStaticFileDTO{
+		FileUUID: templateArchiveUUID,
+		FileType: "template",
+		File:     dto.TemplateArchive,
+	})
+}
+
+// RenameLanguage changes the name of a language, which must be unique
+func (useCases *LanguageUseCases) RenameLanguage(dto *dtos.RenameLanguageDTO) error {
+	// Check the language exists
+	_, err := useCases.LanguageRepository.GetByUUID(dto.LanguageUUID)
+	if err != nil {
+		return err
+	}
+
+	// Check the name is not used by another language
+	err = useCases.checkLanguageNameIsAvailable(dto.NewName, dto.LanguageUUID)
+	if err != nil {
+		return err
+	}
+
+	return useCases.LanguageRepository.UpdateName(dto)
+}
+
+// SetLanguageDeprecation deprecates a language, so it can not be chosen for new test blocks, or restores it
+func (useCases *LanguageUseCases) SetLanguageDeprecation(dto *dtos.SetLanguageDeprecationDTO) error {
+	return useCases.LanguageRepository.SetDeprecation(dto)
+}
+
+// UpdateLanguageExecutionLimits updates the limits applied to the submissions of the test blocks that do
+// not override them
+func (useCases *LanguageUseCases) UpdateLanguageExecutionLimits(dto *dtos.UpdateLanguageExecutionLimitsDTO) error {
+	return useCases.LanguageRepository.UpdateExecutionLimits(dto)
+}
+
+// checkLanguageNameIsAvailable returns an error if the name is used by a language other than the given one
+func (useCases *LanguageUseCases) checkLanguageNameIsAvailable(name string, languageUUID string) error {
+	language, err := useCases.LanguageRepository.GetByName(name)
+	if err != nil {
+		if _, isNotFound := err.(*errors.LangNotFoundError); isNotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	if language.UUID != languageUUID {
+		return &errors.LangNameAlreadyInUseError{}
+	}
+
+	return nil
 }
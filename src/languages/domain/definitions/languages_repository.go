@@ -1,9 +1,20 @@
 package definitions
 
-import "github.com/UPB-Code-Labs/main-api/src/languages/domain/entities"
+import (
+	"github.com/UPB-Code-Labs/main-api/src/languages/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/languages/domain/entities"
+)
 
 type LanguagesRepository interface {
 	GetAll() (languages []*entities.Language, err error)
 	GetByUUID(uuid string) (language *entities.Language, err error)
+	GetByName(name string) (language *entities.Language, err error)
 	GetTemplateArchiveUUIDByLanguageUUID(uuid string) (templateUUID string, err error)
+
+	// Create a language along with the metadata of its template archive
+	CreateLanguage(dto *dtos.CreateLanguageDTO) (languageUUID string, err error)
+
+	UpdateName(dto *dtos.RenameLanguageDTO) (err error)
+	SetDeprecation(dto *dtos.SetLanguageDeprecationDTO) (err error)
+	UpdateExecutionLimits(dto *dtos.UpdateLanguageExecutionLimitsDTO) (err error)
 }
@@ -0,0 +1,41 @@
+package dtos
+
+import (
+	"mime/multipart"
+
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+)
+
+type CreateLanguageDTO struct {
+	Name          string
+	RequiredPaths []string
+
+	// Limits sent by the admin, the default ones are used for the rest
+	ExecutionLimits *sharedEntities.ExecutionLimitsOverrides
+
+	// Template archive uploaded by the admin and the UUID it was saved with in the static files storage
+	TemplateArchive     *multipart.File
+	TemplateArchiveUUID string
+}
+
+type UpdateLanguageTemplateDTO struct {
+	LanguageUUID    string
+	TemplateArchive *multipart.File
+}
+
+type RenameLanguageDTO struct {
+	LanguageUUID string
+	NewName      string
+}
+
+type SetLanguageDeprecationDTO struct {
+	LanguageUUID string
+	IsDeprecated bool
+}
+
+type UpdateLanguageExecutionLimitsDTO struct {
+	LanguageUUID string
+
+	// Only the limits that are not nil are updated
+	ExecutionLimits *sharedEntities.ExecutionLimitsOverrides
+}
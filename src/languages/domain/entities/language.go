@@ -1,7 +1,18 @@
 package entities
 
+import sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+
 type Language struct {
 	UUID                string `json:"uuid"`
 	TemplateArchiveUUID string `json:"-"`
 	Name                string `json:"name"`
+
+	// Paths the uploaded projects must contain (e.g. `src/main/java`)
+	RequiredPaths []string `json:"required_paths"`
+
+	// Deprecated languages can not be chosen for new test blocks
+	IsDeprecated bool `json:"is_deprecated"`
+
+	// Limits applied to the submissions of the test blocks that do not override them
+	ExecutionLimits sharedEntities.ExecutionLimits `json:"execution_limits"`
 }
@@ -11,3 +11,23 @@ func (err *LangNotFoundError) Error() string {
 func (err *LangNotFoundError) StatusCode() int {
 	return http.StatusNotFound
 }
+
+type LangNameAlreadyInUseError struct{}
+
+func (err *LangNameAlreadyInUseError) Error() string {
+	return "There is already a language with the given name"
+}
+
+func (err *LangNameAlreadyInUseError) StatusCode() int {
+	return http.StatusConflict
+}
+
+type LangIsDeprecatedError struct{}
+
+func (err *LangIsDeprecatedError) Error() string {
+	return "The language is deprecated, please choose another one"
+}
+
+func (err *LangIsDeprecatedError) StatusCode() int {
+	return http.StatusBadRequest
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/UPB-Code-Labs/main-api/src/languages/application"
+	"github.com/UPB-Code-Labs/main-api/src/languages/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/languages/infrastructure/requests"
 	"github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 	"github.com/gin-gonic/gin"
 )
@@ -44,5 +46,245 @@ func (controller *LanguagesController) HandleDownloadLanguageTemplate(c *gin.Con
 	}
 
 	// Return the template
-	c.Data(http.StatusOK, "application/zip", template)
+	infrastructure.SendArchive(c, template.Content, template.ContentLength, template.ContentDisposition)
+}
+
+func (controller *LanguagesController) HandleCreateLanguage(c *gin.Context) {
+	// Validate the request struct
+	executionLimits, err := infrastructure.GetExecutionLimitsRequestFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	req := requests.CreateLanguageRequest{
+		Name:            c.PostForm("name"),
+		RequiredPaths:   c.PostFormArray("required_paths"),
+		ExecutionLimits: executionLimits,
+	}
+
+	if err := infrastructure.GetValidator().Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Validate the template archive
+	multipartHeader, err := c.FormFile("template_archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Please, make sure to send the template archive",
+		})
+		return
+	}
+
+	err = infrastructure.ValidateMultipartFileHeader(multipartHeader)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	multipartFile, err := multipartHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "There was an error while reading the template archive",
+		})
+		return
+	}
+	defer multipartFile.Close()
+
+	// Create the language
+	languageUUID, err := controller.UseCases.CreateLanguage(&dtos.CreateLanguageDTO{
+		Name:            req.Name,
+		RequiredPaths:   req.RequiredPaths,
+		ExecutionLimits: req.ExecutionLimits.ToOverrides(),
+		TemplateArchive: &multipartFile,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"uuid": languageUUID,
+	})
+}
+
+func (controller *LanguagesController) HandleUpdateLanguageTemplate(c *gin.Context) {
+	languageUUID := c.Param("language_uuid")
+
+	// Validate the language UUID
+	if err := infrastructure.GetValidator().Var(languageUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Language UUID is not valid",
+		})
+		return
+	}
+
+	// Validate the template archive
+	multipartHeader, err := c.FormFile("template_archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Please, make sure to send the template archive",
+		})
+		return
+	}
+
+	err = infrastructure.ValidateMultipartFileHeader(multipartHeader)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	multipartFile, err := multipartHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "There was an error while reading the template archive",
+		})
+		return
+	}
+	defer multipartFile.Close()
+
+	// Replace the template
+	err = controller.UseCases.UpdateLanguageTemplate(&dtos.UpdateLanguageTemplateDTO{
+		LanguageUUID:    languageUUID,
+		TemplateArchive: &multipartFile,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LanguagesController) HandleRenameLanguage(c *gin.Context) {
+	languageUUID := c.Param("language_uuid")
+
+	// Validate the language UUID
+	if err := infrastructure.GetValidator().Var(languageUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Language UUID is not valid",
+		})
+		return
+	}
+
+	// Parse request body
+	var request requests.RenameLanguageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	// Validate request body
+	if err := infrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Rename the language
+	err := controller.UseCases.RenameLanguage(&dtos.RenameLanguageDTO{
+		LanguageUUID: languageUUID,
+		NewName:      request.Name,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LanguagesController) HandleSetLanguageDeprecation(c *gin.Context) {
+	languageUUID := c.Param("language_uuid")
+
+	// Validate the language UUID
+	if err := infrastructure.GetValidator().Var(languageUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Language UUID is not valid",
+		})
+		return
+	}
+
+	// Parse request body
+	var request requests.SetLanguageDeprecationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	// Validate request body
+	if err := infrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Deprecate or restore the language
+	err := controller.UseCases.SetLanguageDeprecation(&dtos.SetLanguageDeprecationDTO{
+		LanguageUUID: languageUUID,
+		IsDeprecated: *request.IsDeprecated,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (controller *LanguagesController) HandleUpdateLanguageExecutionLimits(c *gin.Context) {
+	languageUUID := c.Param("language_uuid")
+
+	// Validate the language UUID
+	if err := infrastructure.GetValidator().Var(languageUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Language UUID is not valid",
+		})
+		return
+	}
+
+	// Parse request body
+	var request infrastructure.ExecutionLimitsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	// Validate request body
+	if err := infrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	// Update the limits that were sent
+	err := controller.UseCases.UpdateLanguageExecutionLimits(&dtos.UpdateLanguageExecutionLimitsDTO{
+		LanguageUUID:    languageUUID,
+		ExecutionLimits: request.ToOverrides(),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
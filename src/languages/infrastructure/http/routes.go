@@ -12,7 +12,8 @@ func StartLanguagesRoutes(g *gin.RouterGroup) {
 	langGroup := g.Group("/languages")
 
 	useCases := application.LanguageUseCases{
-		StaticFilesRepository: &staticFilesImplementations.StaticFilesMicroserviceImplementation{},
+		StaticFilesRepository: staticFilesImplementations.GetStaticFilesRepositoryInstance(),
+		ArchivesValidator:     staticFilesImplementations.GetZipArchivesValidatorInstance(),
 		LanguageRepository:    implementations.GetLanguagesRepositoryInstance(),
 	}
 
@@ -23,13 +24,43 @@ func StartLanguagesRoutes(g *gin.RouterGroup) {
 	langGroup.GET(
 		"",
 		sharedInfrastructure.WithAuthenticationMiddleware(),
-		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher", "student"}),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher", "student", "admin"}),
 		controllers.HandleGetLanguages,
 	)
 	langGroup.GET(
 		"/:language_uuid/template",
 		sharedInfrastructure.WithAuthenticationMiddleware(),
-		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher", "student"}),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher", "student", "admin"}),
 		controllers.HandleDownloadLanguageTemplate,
 	)
+	langGroup.POST(
+		"",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controllers.HandleCreateLanguage,
+	)
+	langGroup.PUT(
+		"/:language_uuid/template",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controllers.HandleUpdateLanguageTemplate,
+	)
+	langGroup.PATCH(
+		"/:language_uuid/name",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controllers.HandleRenameLanguage,
+	)
+	langGroup.PATCH(
+		"/:language_uuid/deprecation",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controllers.HandleSetLanguageDeprecation,
+	)
+	langGroup.PATCH(
+		"/:language_uuid/execution_limits",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controllers.HandleUpdateLanguageExecutionLimits,
+	)
 }
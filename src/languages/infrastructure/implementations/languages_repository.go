@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/UPB-Code-Labs/main-api/src/languages/domain/dtos"
 	"github.com/UPB-Code-Labs/main-api/src/languages/domain/entities"
 	"github.com/UPB-Code-Labs/main-api/src/languages/domain/errors"
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/lib/pq"
 )
 
 type LanguagesRepository struct {
@@ -27,15 +30,42 @@ func GetLanguagesRepositoryInstance() *LanguagesRepository {
 	return langRepositoryInstance
 }
 
+// Columns scanned by `scanLanguage`
+const languageColumns = `
+	id, template_archive_id, name, required_paths, deprecated_at IS NOT NULL,
+	cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
+`
+
+// scanLanguage parses a row selected with the `languageColumns`
+func scanLanguage(row interface{ Scan(...any) error }) (*entities.Language, error) {
+	language := &entities.Language{}
+	err := row.Scan(
+		&language.UUID,
+		&language.TemplateArchiveUUID,
+		&language.Name,
+		pq.Array(&language.RequiredPaths),
+		&language.IsDeprecated,
+		&language.ExecutionLimits.CPUTimeMs,
+		&language.ExecutionLimits.WallTimeMs,
+		&language.ExecutionLimits.MemoryMb,
+		&language.ExecutionLimits.OutputSizeKb,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return language, nil
+}
+
 // Methods implementation
 func (repository *LanguagesRepository) GetAll() (languages []*entities.Language, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	query := `
-		SELECT 
-		id, template_archive_id, name 
+		SELECT ` + languageColumns + `
 		FROM languages
+		ORDER BY deprecated_at IS NOT NULL, name
 	`
 
 	rows, err := repository.Connection.QueryContext(ctx, query)
@@ -46,13 +76,12 @@ func (repository *LanguagesRepository) GetAll() (languages []*entities.Language,
 
 	// Parse the rows
 	for rows.Next() {
-		var language entities.Language
-		err := rows.Scan(&language.UUID, &language.TemplateArchiveUUID, &language.Name)
+		language, err := scanLanguage(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		languages = append(languages, &language)
+		languages = append(languages, language)
 	}
 
 	return languages, nil
@@ -63,8 +92,7 @@ func (repository *LanguagesRepository) GetByUUID(uuid string) (language *entitie
 	defer cancel()
 
 	query := `
-		SELECT 
-		id, template_archive_id, name 
+		SELECT ` + languageColumns + `
 		FROM languages
 		WHERE id = $1
 	`
@@ -72,8 +100,32 @@ func (repository *LanguagesRepository) GetByUUID(uuid string) (language *entitie
 	row := repository.Connection.QueryRowContext(ctx, query, uuid)
 
 	// Parse the row
-	language = &entities.Language{}
-	err = row.Scan(&language.UUID, &language.TemplateArchiveUUID, &language.Name)
+	language, err = scanLanguage(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &errors.LangNotFoundError{}
+		}
+
+		return nil, err
+	}
+
+	return language, nil
+}
+
+func (repository *LanguagesRepository) GetByName(name string) (language *entities.Language, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT ` + languageColumns + `
+		FROM languages
+		WHERE name = $1
+	`
+
+	row := repository.Connection.QueryRowContext(ctx, query, name)
+
+	// Parse the row
+	language, err = scanLanguage(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &errors.LangNotFoundError{}
@@ -114,3 +166,145 @@ func (repository *LanguagesRepository) GetTemplateArchiveUUIDByLanguageUUID(uuid
 
 	return templateUUID, nil
 }
+
+func (repository *LanguagesRepository) CreateLanguage(dto *dtos.CreateLanguageDTO) (languageUUID string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Start a transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	// Save the metadata of the template archive
+	query := `
+		INSERT INTO archives (file_id, archive_type)
+		VALUES ($1, 'template')
+		RETURNING id
+	`
+
+	var templateArchiveUUID string
+	err = tx.QueryRowContext(ctx, query, dto.TemplateArchiveUUID).Scan(&templateArchiveUUID)
+	if err != nil {
+		return "", err
+	}
+
+	// Create the language
+	query = `
+		INSERT INTO languages (
+			name, template_archive_id, required_paths,
+			cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	limits := sharedEntities.DefaultExecutionLimits.WithOverrides(dto.ExecutionLimits)
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		dto.Name,
+		templateArchiveUUID,
+		pq.Array(dto.RequiredPaths),
+		limits.CPUTimeMs,
+		limits.WallTimeMs,
+		limits.MemoryMb,
+		limits.OutputSizeKb,
+	).Scan(&languageUUID)
+	if err != nil {
+		return "", err
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return "", err
+	}
+
+	return languageUUID, nil
+}
+
+func (repository *LanguagesRepository) UpdateName(dto *dtos.RenameLanguageDTO) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE languages
+		SET name = $1
+		WHERE id = $2
+	`
+
+	result, err := repository.Connection.ExecContext(ctx, query, dto.NewName, dto.LanguageUUID)
+	if err != nil {
+		return err
+	}
+
+	return checkLanguageWasUpdated(result)
+}
+
+// SetDeprecation deprecates or restores a language. The date of the first deprecation is kept
+func (repository *LanguagesRepository) SetDeprecation(dto *dtos.SetLanguageDeprecationDTO) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE languages
+		SET deprecated_at = CASE WHEN $1 THEN COALESCE(deprecated_at, CURRENT_TIMESTAMP) ELSE NULL END
+		WHERE id = $2
+	`
+
+	result, err := repository.Connection.ExecContext(ctx, query, dto.IsDeprecated, dto.LanguageUUID)
+	if err != nil {
+		return err
+	}
+
+	return checkLanguageWasUpdated(result)
+}
+
+// UpdateExecutionLimits updates the default execution limits of a language. The limits that were not sent
+// are kept
+func (repository *LanguagesRepository) UpdateExecutionLimits(dto *dtos.UpdateLanguageExecutionLimitsDTO) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE languages
+		SET
+			cpu_time_limit_ms = COALESCE($1, cpu_time_limit_ms),
+			wall_time_limit_ms = COALESCE($2, wall_time_limit_ms),
+			memory_limit_mb = COALESCE($3, memory_limit_mb),
+			output_size_limit_kb = COALESCE($4, output_size_limit_kb)
+		WHERE id = $5
+	`
+
+	result, err := repository.Connection.ExecContext(
+		ctx,
+		query,
+		dto.ExecutionLimits.CPUTimeMs,
+		dto.ExecutionLimits.WallTimeMs,
+		dto.ExecutionLimits.MemoryMb,
+		dto.ExecutionLimits.OutputSizeKb,
+		dto.LanguageUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkLanguageWasUpdated(result)
+}
+
+// checkLanguageWasUpdated returns a not found error if the update did not match any language
+func checkLanguageWasUpdated(result sql.Result) error {
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return &errors.LangNotFoundError{}
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package requests
+
+import sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+
+type CreateLanguageRequest struct {
+	Name string `validate:"required,min=1,max=32"`
+
+	// Paths relative to the root of the projects
+	RequiredPaths []string `validate:"dive,required,max=255,excludes=..,startsnotwith=/"`
+
+	// Limits that replace the default ones
+	ExecutionLimits *sharedInfrastructure.ExecutionLimitsRequest
+}
+
+type RenameLanguageRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=32"`
+}
+
+type SetLanguageDeprecationRequest struct {
+	IsDeprecated *bool `json:"is_deprecated" validate:"required"`
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	config "github.com/UPB-Code-Labs/main-api/src/config/infrastructure"
+	coursesImplementations "github.com/UPB-Code-Labs/main-api/src/courses/infrastructure/implementations"
+	plagiarismImplementations "github.com/UPB-Code-Labs/main-api/src/plagiarism/infrastructure/implementations"
 	shared "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	staticFilesImplementations "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
 	submissionsImplementations "github.com/UPB-Code-Labs/main-api/src/submissions/infrastructure/implementations"
 )
 
@@ -23,10 +26,34 @@ func main() {
 	submissionsRealTimeUpdatesQueueMgr := submissionsImplementations.GetSubmissionsRealTimeUpdatesQueueMgrInstance()
 	go submissionsRealTimeUpdatesQueueMgr.ListenForUpdates()
 
+	// Start saving the submission works sent to the dead letter queue
+	submissionsDeadLetterQueueMgr := submissionsImplementations.GetSubmissionsDeadLetterQueueMgrInstance()
+	go submissionsDeadLetterQueueMgr.ListenForDeadLetters()
+
 	// Start listening for SSE connections
 	realTimeSubmissionsUpdatesSender := submissionsImplementations.GetSubmissionsRealTimeUpdatesSenderInstance()
 	go realTimeSubmissionsUpdatesSender.Listen()
 
+	// Start generating the plagiarism reports in background
+	plagiarismReportsQueue := plagiarismImplementations.GetPlagiarismReportsQueueInstance()
+	go plagiarismReportsQueue.Listen()
+
+	// Start publishing the submission works saved in the outbox
+	submissionsOutboxRelay := submissionsImplementations.GetSubmissionsOutboxRelayInstance()
+	go submissionsOutboxRelay.Start()
+
+	// Start looking for stuck submissions
+	submissionsWatchdog := submissionsImplementations.GetSubmissionsWatchdogInstance()
+	go submissionsWatchdog.Start()
+
+	// Start collecting the archives that are no longer referenced
+	orphanedArchivesCollector := staticFilesImplementations.GetOrphanedArchivesCollectorInstance()
+	go orphanedArchivesCollector.Start()
+
+	// Start purging the deleted courses and laboratories whose restore window has passed
+	deletionsPurger := coursesImplementations.GetDeletionsPurgerInstance()
+	go deletionsPurger.Start()
+
 	// Start HTTP server
 	router := config.InstanceHttpServer()
 	router.Run(":8080")
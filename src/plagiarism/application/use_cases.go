@@ -0,0 +1,221 @@
+package application
+
+import (
+	"log"
+	"sort"
+
+	blocksDefinitions "github.com/UPB-Code-Labs/main-api/src/blocks/domain/definitions"
+	languagesDefinitions "github.com/UPB-Code-Labs/main-api/src/languages/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/entities"
+	plagiarismErrors "github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/errors"
+	staticFilesDefinitions "github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
+	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+)
+
+type PlagiarismUseCases struct {
+	PlagiarismRepository   definitions.PlagiarismRepository
+	PlagiarismReportsQueue definitions.PlagiarismReportsQueue
+	BlocksRepository       blocksDefinitions.BlockRepository
+	LanguagesRepository    languagesDefinitions.LanguagesRepository
+	StaticFilesRepository  staticFilesDefinitions.StaticFilesRepository
+}
+
+// CreatePlagiarismReport saves a new report and schedules its generation in background
+func (useCases *PlagiarismUseCases) CreatePlagiarismReport(dto *dtos.CreatePlagiarismReportDTO) (reportUUID string, err error) {
+	// Check that the teacher owns the test block
+	ownsTestBlock, err := useCases.BlocksRepository.DoesTeacherOwnsTestBlock(dto.TeacherUUID, dto.TestBlockUUID)
+	if err != nil {
+		return "", err
+	}
+
+	if !ownsTestBlock {
+		return "", plagiarismErrors.TeacherCannotAccessPlagiarismReportError{}
+	}
+
+	// Save the report
+	reportUUID, err = useCases.PlagiarismRepository.SaveReport(dto)
+	if err != nil {
+		return "", err
+	}
+
+	// The report is claimed from the table by the queue, start generating it right away
+	useCases.PlagiarismReportsQueue.Wake()
+
+	return reportUUID, nil
+}
+
+// GetPlagiarismReport returns the status of a report and, once it is ready, the ranked pairs of suspicious submissions
+func (useCases *PlagiarismUseCases) GetPlagiarismReport(dto *dtos.GetPlagiarismReportDTO) (*dtos.PlagiarismReportDTO, error) {
+	report, err := useCases.PlagiarismRepository.GetReportByUUID(dto.ReportUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the teacher owns the test block of the report
+	ownsTestBlock, err := useCases.BlocksRepository.DoesTeacherOwnsTestBlock(dto.TeacherUUID, report.TestBlockUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ownsTestBlock {
+		return nil, plagiarismErrors.TeacherCannotAccessPlagiarismReportError{}
+	}
+
+	// Get the pairs of suspicious submissions
+	pairs := []*entities.PlagiarismPair{}
+	if report.Status == "ready" {
+		pairs, err = useCases.PlagiarismRepository.GetReportPairs(report.UUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &dtos.PlagiarismReportDTO{
+		PlagiarismReport: report,
+		Pairs:            pairs,
+	}, nil
+}
+
+// GenerateNextPlagiarismReport claims the oldest pending report and generates it. Returns an empty UUID if
+// there are no reports to generate
+func (useCases *PlagiarismUseCases) GenerateNextPlagiarismReport(maxAttempts int) (reportUUID string, err error) {
+	reportUUID, err = useCases.PlagiarismRepository.ClaimPendingReport(maxAttempts)
+	if err != nil || reportUUID == "" {
+		return "", err
+	}
+
+	return reportUUID, useCases.GeneratePlagiarismReport(reportUUID)
+}
+
+// GeneratePlagiarismReport compares the latest submissions of the students to the test block of the report
+// against each other and saves the pairs whose similarity is above the threshold of the report
+func (useCases *PlagiarismUseCases) GeneratePlagiarismReport(reportUUID string) error {
+	err := useCases.generatePlagiarismReport(reportUUID)
+	if err != nil {
+		if updateErr := useCases.PlagiarismRepository.SetReportAsFailed(reportUUID, err.Error()); updateErr != nil {
+			log.Println("[Plagiarism reports]: Unable to set the report as failed", updateErr.Error())
+		}
+	}
+
+	return err
+}
+
+func (useCases *PlagiarismUseCases) generatePlagiarismReport(reportUUID string) error {
+	report, err := useCases.PlagiarismRepository.GetReportByUUID(reportUUID)
+	if err != nil {
+		return err
+	}
+
+	// The code of the template of the language is given to every student, so it is not taken into account
+	boilerplate := useCases.getTestBlockBoilerplate(report.TestBlockUUID)
+
+	// Get the fingerprints of the latest submission of each student
+	submissions, err := useCases.PlagiarismRepository.GetLatestSubmissionsOfTestBlock(report.TestBlockUUID)
+	if err != nil {
+		return err
+	}
+
+	analyzedSubmissions := []*dtos.PlagiarismSubmissionDTO{}
+	submissionsFingerprints := []submissionFingerprints{}
+	skippedSubmissions := 0
+
+	for _, submission := range submissions {
+		fingerprints, err := useCases.getSubmissionFingerprints(submission)
+		if err != nil {
+			// Submissions whose archive can not be downloaded or is not valid can not be compared
+			log.Printf(
+				"[Plagiarism reports]: Skipping the submission %s: %s",
+				submission.SubmissionUUID,
+				err.Error(),
+			)
+			skippedSubmissions++
+			continue
+		}
+		fingerprints.removeBoilerplate(boilerplate)
+
+		analyzedSubmissions = append(analyzedSubmissions, submission)
+		submissionsFingerprints = append(submissionsFingerprints, fingerprints)
+	}
+
+	// Compare each pair of submissions
+	pairs := []*entities.PlagiarismPair{}
+	for i := 0; i < len(analyzedSubmissions); i++ {
+		for j := i + 1; j < len(analyzedSubmissions); j++ {
+			similarity, regions := compareFingerprints(submissionsFingerprints[i], submissionsFingerprints[j])
+			if similarity == 0 || similarity < report.MinSimilarityPercentage {
+				continue
+			}
+
+			pairs = append(pairs, &entities.PlagiarismPair{
+				FirstSubmission:      toPlagiarismPairSubmission(analyzedSubmissions[i]),
+				SecondSubmission:     toPlagiarismPairSubmission(analyzedSubmissions[j]),
+				SimilarityPercentage: similarity,
+				MatchingRegions:      regions,
+			})
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].SimilarityPercentage > pairs[j].SimilarityPercentage
+	})
+
+	return useCases.PlagiarismRepository.SaveReportResults(&dtos.PlagiarismReportResultsDTO{
+		ReportUUID:          reportUUID,
+		AnalyzedSubmissions: len(analyzedSubmissions),
+		SkippedSubmissions:  skippedSubmissions,
+		Pairs:               pairs,
+	})
+}
+
+// getSubmissionFingerprints downloads the archive of a submission and returns the fingerprints of its source files
+func (useCases *PlagiarismUseCases) getSubmissionFingerprints(submission *dtos.PlagiarismSubmissionDTO) (submissionFingerprints, error) {
+	archiveBytes, err := useCases.StaticFilesRepository.GetArchiveBytes(&staticFilesDTOs.StaticFileArchiveDTO{
+		FileUUID: submission.ArchiveUUID,
+		FileType: "submission",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return getArchiveFingerprints(archiveBytes)
+}
+
+// getTestBlockBoilerplate returns the fingerprints of the template of the language of the test block.
+// The report is still generated without removing the boilerplate if the template is not available
+func (useCases *PlagiarismUseCases) getTestBlockBoilerplate(testBlockUUID string) submissionFingerprints {
+	testBlock, err := useCases.BlocksRepository.GetTestBlockByUUID(testBlockUUID)
+	if err != nil {
+		log.Println("[Plagiarism reports]: Unable to get the test block", err.Error())
+		return submissionFingerprints{}
+	}
+
+	templateArchiveUUID, err := useCases.LanguagesRepository.GetTemplateArchiveUUIDByLanguageUUID(testBlock.LanguageUUID)
+	if err != nil {
+		log.Println("[Plagiarism reports]: Unable to get the template archive of the language", err.Error())
+		return submissionFingerprints{}
+	}
+
+	templateBytes, err := useCases.StaticFilesRepository.GetLanguageTemplateArchiveBytes(templateArchiveUUID)
+	if err != nil {
+		log.Println("[Plagiarism reports]: Unable to get the template of the language", err.Error())
+		return submissionFingerprints{}
+	}
+
+	boilerplate, err := getArchiveFingerprints(templateBytes)
+	if err != nil {
+		log.Println("[Plagiarism reports]: The template of the language is not valid", err.Error())
+		return submissionFingerprints{}
+	}
+
+	return boilerplate
+}
+
+func toPlagiarismPairSubmission(submission *dtos.PlagiarismSubmissionDTO) entities.PlagiarismPairSubmission {
+	return entities.PlagiarismPairSubmission{
+		SubmissionUUID:  submission.SubmissionUUID,
+		StudentUUID:     submission.StudentUUID,
+		StudentFullName: submission.StudentFullName,
+	}
+}
@@ -0,0 +1,212 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	blocksDefinitions "github.com/UPB-Code-Labs/main-api/src/blocks/domain/definitions"
+	laboratoriesEntities "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+	languagesDefinitions "github.com/UPB-Code-Labs/main-api/src/languages/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/entities"
+	staticFilesDefinitions "github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
+	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+)
+
+// plagiarismRepositoryStub keeps a single report and the results saved for it
+type plagiarismRepositoryStub struct {
+	definitions.PlagiarismRepository
+
+	report      *entities.PlagiarismReport
+	submissions []*dtos.PlagiarismSubmissionDTO
+	results     *dtos.PlagiarismReportResultsDTO
+}
+
+func (stub *plagiarismRepositoryStub) GetReportByUUID(reportUUID string) (*entities.PlagiarismReport, error) {
+	return stub.report, nil
+}
+
+func (stub *plagiarismRepositoryStub) ClaimPendingReport(maxAttempts int) (string, error) {
+	if stub.report.Status != "pending" {
+		return "", nil
+	}
+
+	stub.report.Status = "running"
+	return stub.report.UUID, nil
+}
+
+func (stub *plagiarismRepositoryStub) SetReportAsFailed(reportUUID string, errorMessage string) error {
+	stub.report.Status = "failed"
+	stub.report.ErrorMessage = &errorMessage
+	return nil
+}
+
+func (stub *plagiarismRepositoryStub) GetLatestSubmissionsOfTestBlock(testBlockUUID string) ([]*dtos.PlagiarismSubmissionDTO, error) {
+	return stub.submissions, nil
+}
+
+func (stub *plagiarismRepositoryStub) SaveReportResults(dto *dtos.PlagiarismReportResultsDTO) error {
+	stub.results = dto
+	return nil
+}
+
+// blocksRepositoryStub returns a test block of the language `language`
+type blocksRepositoryStub struct {
+	blocksDefinitions.BlockRepository
+}
+
+func (stub *blocksRepositoryStub) GetTestBlockByUUID(blockUUID string) (*laboratoriesEntities.TestBlock, error) {
+	return &laboratoriesEntities.TestBlock{UUID: blockUUID, LanguageUUID: "language"}, nil
+}
+
+// languagesRepositoryStub maps the UUIDs of the languages to the UUIDs of their template archives
+type languagesRepositoryStub struct {
+	languagesDefinitions.LanguagesRepository
+
+	templates map[string]string
+}
+
+func (stub *languagesRepositoryStub) GetTemplateArchiveUUIDByLanguageUUID(uuid string) (string, error) {
+	templateUUID, exists := stub.templates[uuid]
+	if !exists {
+		return "", errors.New("language not found")
+	}
+
+	return templateUUID, nil
+}
+
+// staticFilesRepositoryStub serves the archives and the templates stored in memory
+type staticFilesRepositoryStub struct {
+	staticFilesDefinitions.StaticFilesRepository
+
+	archives  map[string][]byte
+	templates map[string][]byte
+}
+
+func (stub *staticFilesRepositoryStub) GetArchiveBytes(dto *staticFilesDTOs.StaticFileArchiveDTO) ([]byte, error) {
+	archive, exists := stub.archives[dto.FileUUID]
+	if !exists {
+		return nil, errors.New("archive not found")
+	}
+
+	return archive, nil
+}
+
+func (stub *staticFilesRepositoryStub) GetLanguageTemplateArchiveBytes(templateUUID string) ([]byte, error) {
+	template, exists := stub.templates[templateUUID]
+	if !exists {
+		return nil, errors.New("template not found")
+	}
+
+	return template, nil
+}
+
+func TestGeneratePlagiarismReportExcludesTheTemplate(t *testing.T) {
+	template := createTestArchive(t, map[string]string{"main.py": originalSource})
+
+	repository := &plagiarismRepositoryStub{
+		report: &entities.PlagiarismReport{
+			UUID:          "report",
+			TestBlockUUID: "test-block",
+			Status:        "pending",
+		},
+		submissions: []*dtos.PlagiarismSubmissionDTO{
+			{SubmissionUUID: "first", ArchiveUUID: "first-archive", StudentUUID: "first-student"},
+			{SubmissionUUID: "second", ArchiveUUID: "second-archive", StudentUUID: "second-student"},
+		},
+	}
+
+	// The students only submitted the code of the template
+	useCases := PlagiarismUseCases{
+		PlagiarismRepository: repository,
+		BlocksRepository:     &blocksRepositoryStub{},
+		LanguagesRepository: &languagesRepositoryStub{
+			templates: map[string]string{"language": "template-archive"},
+		},
+		StaticFilesRepository: &staticFilesRepositoryStub{
+			archives: map[string][]byte{
+				"first-archive":  template,
+				"second-archive": template,
+			},
+			templates: map[string][]byte{"template-archive": template},
+		},
+	}
+
+	if err := useCases.GeneratePlagiarismReport("report"); err != nil {
+		t.Fatal(err)
+	}
+
+	if repository.results == nil {
+		t.Fatal("expected the results of the report to be saved")
+	}
+
+	if repository.results.AnalyzedSubmissions != 2 {
+		t.Errorf("expected 2 analyzed submissions, got %d", repository.results.AnalyzedSubmissions)
+	}
+
+	if len(repository.results.Pairs) != 0 {
+		t.Errorf("expected the template to be excluded, got %d pairs", len(repository.results.Pairs))
+	}
+}
+
+func TestGeneratePlagiarismReportSkipsUnavailableArchives(t *testing.T) {
+	repository := &plagiarismRepositoryStub{
+		report: &entities.PlagiarismReport{
+			UUID:          "report",
+			TestBlockUUID: "test-block",
+			Status:        "pending",
+		},
+		submissions: []*dtos.PlagiarismSubmissionDTO{
+			{SubmissionUUID: "first", ArchiveUUID: "first-archive", StudentUUID: "first-student"},
+			{SubmissionUUID: "second", ArchiveUUID: "missing-archive", StudentUUID: "second-student"},
+			{SubmissionUUID: "third", ArchiveUUID: "invalid-archive", StudentUUID: "third-student"},
+			{SubmissionUUID: "fourth", ArchiveUUID: "fourth-archive", StudentUUID: "fourth-student"},
+		},
+	}
+
+	useCases := PlagiarismUseCases{
+		PlagiarismRepository: repository,
+		BlocksRepository:     &blocksRepositoryStub{},
+		LanguagesRepository:  &languagesRepositoryStub{},
+		StaticFilesRepository: &staticFilesRepositoryStub{
+			archives: map[string][]byte{
+				"first-archive":   createTestArchive(t, map[string]string{"main.py": originalSource}),
+				"invalid-archive": []byte("not a zip"),
+				"fourth-archive":  createTestArchive(t, map[string]string{"main.py": renamedSource}),
+			},
+		},
+	}
+
+	reportUUID, err := useCases.GenerateNextPlagiarismReport(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reportUUID != "report" {
+		t.Fatalf("expected the pending report to be generated, got %q", reportUUID)
+	}
+
+	// A submission that can not be downloaded does not make the whole report fail
+	if repository.results == nil {
+		t.Fatalf("expected the results of the report to be saved, the report is %s", repository.report.Status)
+	}
+
+	if repository.results.AnalyzedSubmissions != 2 || repository.results.SkippedSubmissions != 2 {
+		t.Errorf(
+			"expected 2 analyzed and 2 skipped submissions, got %d and %d",
+			repository.results.AnalyzedSubmissions,
+			repository.results.SkippedSubmissions,
+		)
+	}
+
+	if len(repository.results.Pairs) != 1 {
+		t.Errorf("expected 1 pair, got %d", len(repository.results.Pairs))
+	}
+
+	// There are no more reports to generate
+	reportUUID, err = useCases.GenerateNextPlagiarismReport(3)
+	if err != nil || reportUUID != "" {
+		t.Errorf("expected no more reports, got %q and %v", reportUUID, err)
+	}
+}
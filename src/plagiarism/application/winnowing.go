@@ -0,0 +1,362 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"hash/fnv"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/entities"
+)
+
+const (
+	// Number of tokens of each k-gram. Matches shorter than this are considered noise
+	winnowingKGramSize = 8
+
+	// Number of consecutive k-grams a fingerprint is selected from. Any match of, at least,
+	// `winnowingKGramSize + winnowingWindowSize - 1` tokens is guaranteed to be detected
+	winnowingWindowSize = 4
+
+	// Source files bigger than this are not analyzed
+	maxSourceFileSize = 512 * 1024
+
+	// Number of matching regions saved for each pair of submissions
+	maxMatchingRegionsPerPair = 20
+)
+
+// Keywords are kept as they are, any other identifier is replaced by the same token so renaming
+// variables or functions does not hide the similarity between two submissions
+var sourceKeywords = map[string]bool{
+	"and": true, "as": true, "break": true, "case": true, "catch": true, "class": true,
+	"const": true, "continue": true, "def": true, "default": true, "do": true, "elif": true,
+	"else": true, "except": true, "extends": true, "finally": true, "for": true, "func": true,
+	"function": true, "if": true, "implements": true, "import": true, "in": true, "interface": true,
+	"lambda": true, "let": true, "new": true, "not": true, "or": true, "private": true,
+	"protected": true, "public": true, "raise": true, "return": true, "static": true,
+	"struct": true, "switch": true, "this": true, "throw": true, "try": true, "var": true,
+	"void": true, "while": true, "with": true, "yield": true,
+}
+
+// sourceToken normalized token of a source file
+type sourceToken struct {
+	value string
+	line  int
+}
+
+// fingerprint hash of a k-gram selected by the winnowing algorithm and its location
+type fingerprint struct {
+	hash      uint64
+	file      string
+	startLine int
+	endLine   int
+}
+
+// submissionFingerprints fingerprints of all the source files of a submission, indexed by hash
+type submissionFingerprints map[uint64][]fingerprint
+
+// getArchiveFingerprints returns the fingerprints of the source files inside a .zip archive
+func getArchiveFingerprints(archiveBytes []byte) (submissionFingerprints, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archiveBytes), int64(len(archiveBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints := submissionFingerprints{}
+	for _, file := range reader.File {
+		fileName := path.Clean("/" + strings.ReplaceAll(file.Name, `\`, "/"))[1:]
+		if file.FileInfo().IsDir() || !isSourceFile(fileName, file.UncompressedSize64) {
+			continue
+		}
+
+		fileReader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(io.LimitReader(fileReader, maxSourceFileSize+1))
+		fileReader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip binary files
+		if len(content) > maxSourceFileSize || !utf8.Valid(content) || bytes.IndexByte(content, 0) != -1 {
+			continue
+		}
+
+		for _, fileFingerprint := range winnow(fileName, tokenizeSource(string(content))) {
+			fingerprints[fileFingerprint.hash] = append(fingerprints[fileFingerprint.hash], fileFingerprint)
+		}
+	}
+
+	return fingerprints, nil
+}
+
+// isSourceFile returns false for the files that are not written by the student, like hidden files or
+// the metadata added by some operating systems when creating the archive
+func isSourceFile(fileName string, size uint64) bool {
+	if fileName == "" || size > maxSourceFileSize {
+		return false
+	}
+
+	for _, component := range strings.Split(fileName, "/") {
+		if strings.HasPrefix(component, ".") || component == "__MACOSX" || component == "__pycache__" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tokenizeSource splits a source file in normalized tokens. Comments and whitespace are removed,
+// identifiers, numbers and strings are replaced by a token of their kind and keywords and
+// punctuation are kept as they are
+func tokenizeSource(source string) []sourceToken {
+	tokens := []sourceToken{}
+	runes := []rune(source)
+	line := 1
+
+	for i := 0; i < len(runes); {
+		current := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case current == '\n':
+			line++
+			i++
+
+		case unicode.IsSpace(current):
+			i++
+
+		// Line comments
+		case current == '#' || (current == '/' && next == '/'):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		// Block comments
+		case current == '/' && next == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+
+		case current == '_' || unicode.IsLetter(current):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+
+			value := "id"
+			if identifier := string(runes[start:i]); sourceKeywords[identifier] {
+				value = identifier
+			}
+			tokens = append(tokens, sourceToken{value: value, line: line})
+
+		case unicode.IsDigit(current):
+			for i < len(runes) && (runes[i] == '.' || runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, sourceToken{value: "num", line: line})
+
+		case current == '"' || current == '\'' || current == '`':
+			startLine := line
+			i, line = skipStringLiteral(runes, i, line)
+			tokens = append(tokens, sourceToken{value: "str", line: startLine})
+
+		default:
+			tokens = append(tokens, sourceToken{value: string(current), line: line})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// skipStringLiteral returns the position after the string literal that starts at the given position
+// and the line the string literal ends at
+func skipStringLiteral(runes []rune, start int, line int) (int, int) {
+	quote := runes[start]
+
+	// Triple quoted strings (Python docstrings) can span many lines
+	isTripleQuoted := start+2 < len(runes) && runes[start+1] == quote && runes[start+2] == quote
+	if isTripleQuoted {
+		for i := start + 3; i < len(runes); i++ {
+			if runes[i] == '\n' {
+				line++
+			}
+			if i+2 < len(runes) && runes[i] == quote && runes[i+1] == quote && runes[i+2] == quote {
+				return i + 3, line
+			}
+		}
+		return len(runes), line
+	}
+
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1, line
+		case '\n':
+			// Only template literals can span many lines
+			if quote != '`' {
+				return i, line
+			}
+			line++
+		}
+	}
+
+	return len(runes), line
+}
+
+// winnow selects the fingerprints of a source file using the winnowing algorithm: the minimum hash
+// of each window of consecutive k-grams is selected, so the same fragment of code yields the same
+// fingerprints regardless of its position
+func winnow(fileName string, tokens []sourceToken) []fingerprint {
+	if len(tokens) < winnowingKGramSize {
+		return []fingerprint{}
+	}
+
+	hashes := make([]uint64, len(tokens)-winnowingKGramSize+1)
+	for i := range hashes {
+		hasher := fnv.New64a()
+		for _, token := range tokens[i : i+winnowingKGramSize] {
+			hasher.Write([]byte(token.value))
+			hasher.Write([]byte{0})
+		}
+		hashes[i] = hasher.Sum64()
+	}
+
+	windowSize := winnowingWindowSize
+	if len(hashes) < windowSize {
+		windowSize = len(hashes)
+	}
+
+	fingerprints := []fingerprint{}
+	lastSelected := -1
+	for start := 0; start+windowSize <= len(hashes); start++ {
+		// Select the rightmost minimum hash of the window
+		selected := start
+		for i := start; i < start+windowSize; i++ {
+			if hashes[i] <= hashes[selected] {
+				selected = i
+			}
+		}
+
+		if selected == lastSelected {
+			continue
+		}
+		lastSelected = selected
+
+		fingerprints = append(fingerprints, fingerprint{
+			hash:      hashes[selected],
+			file:      fileName,
+			startLine: tokens[selected].line,
+			endLine:   tokens[selected+winnowingKGramSize-1].line,
+		})
+	}
+
+	return fingerprints
+}
+
+// removeBoilerplate removes the fingerprints that are also present in the code given to every student
+func (fingerprints submissionFingerprints) removeBoilerplate(boilerplate submissionFingerprints) {
+	for hash := range boilerplate {
+		delete(fingerprints, hash)
+	}
+}
+
+// compareFingerprints returns the percentage of the fingerprints of the smallest submission that are also
+// present in the other submission and the regions of code both submissions have in common
+func compareFingerprints(first submissionFingerprints, second submissionFingerprints) (float64, []*entities.MatchingRegion) {
+	if len(first) == 0 || len(second) == 0 {
+		return 0, []*entities.MatchingRegion{}
+	}
+
+	matches := []*entities.MatchingRegion{}
+	for hash, firstLocations := range first {
+		secondLocations, isShared := second[hash]
+		if !isShared {
+			continue
+		}
+
+		matches = append(matches, &entities.MatchingRegion{
+			FirstFile:       firstLocations[0].file,
+			FirstStartLine:  firstLocations[0].startLine,
+			FirstEndLine:    firstLocations[0].endLine,
+			SecondFile:      secondLocations[0].file,
+			SecondStartLine: secondLocations[0].startLine,
+			SecondEndLine:   secondLocations[0].endLine,
+		})
+	}
+
+	smallestSubmission := math.Min(float64(len(first)), float64(len(second)))
+	similarity := float64(len(matches)) / smallestSubmission * 100
+
+	return math.Round(similarity*100) / 100, mergeMatchingRegions(matches)
+}
+
+// mergeMatchingRegions merges the overlapping or adjacent matches and returns the biggest regions
+func mergeMatchingRegions(matches []*entities.MatchingRegion) []*entities.MatchingRegion {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].FirstFile != matches[j].FirstFile {
+			return matches[i].FirstFile < matches[j].FirstFile
+		}
+		if matches[i].SecondFile != matches[j].SecondFile {
+			return matches[i].SecondFile < matches[j].SecondFile
+		}
+		if matches[i].FirstStartLine != matches[j].FirstStartLine {
+			return matches[i].FirstStartLine < matches[j].FirstStartLine
+		}
+		return matches[i].SecondStartLine < matches[j].SecondStartLine
+	})
+
+	regions := []*entities.MatchingRegion{}
+	for _, match := range matches {
+		if len(regions) > 0 {
+			last := regions[len(regions)-1]
+
+			isSameFiles := last.FirstFile == match.FirstFile && last.SecondFile == match.SecondFile
+			isFirstAdjacent := match.FirstStartLine <= last.FirstEndLine+1
+			isSecondAdjacent := match.SecondStartLine <= last.SecondEndLine+1 && match.SecondEndLine >= last.SecondStartLine-1
+
+			if isSameFiles && isFirstAdjacent && isSecondAdjacent {
+				last.FirstEndLine = max(last.FirstEndLine, match.FirstEndLine)
+				last.SecondStartLine = min(last.SecondStartLine, match.SecondStartLine)
+				last.SecondEndLine = max(last.SecondEndLine, match.SecondEndLine)
+				continue
+			}
+		}
+
+		regions = append(regions, match)
+	}
+
+	// Keep the biggest regions
+	sort.SliceStable(regions, func(i, j int) bool {
+		return regionSize(regions[i]) > regionSize(regions[j])
+	})
+
+	if len(regions) > maxMatchingRegionsPerPair {
+		regions = regions[:maxMatchingRegionsPerPair]
+	}
+
+	return regions
+}
+
+func regionSize(region *entities.MatchingRegion) int {
+	return region.FirstEndLine - region.FirstStartLine + 1
+}
@@ -0,0 +1,174 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+const originalSource = `# Returns the n-th number of the fibonacci sequence
+def fibonacci(n):
+    if n <= 1:
+        return n
+
+    previous, current = 0, 1
+    for _ in range(n - 1):
+        previous, current = current, previous + current
+
+    return current
+
+
+def main():
+    numbers = [fibonacci(i) for i in range(10)]
+    print("The first numbers are", numbers)
+`
+
+// Same code with renamed identifiers, different comments and formatting
+const renamedSource = `def fib(k):
+    """Computes the sequence"""
+    if k <= 1:
+        return k
+    a, b = 0, 1
+    for _ in range(k - 1):
+        a, b = b, a + b
+    return b
+
+def run():
+    # Print the sequence
+    values = [fib(j) for j in range(10)]
+    print("Sequence:", values)
+`
+
+const unrelatedSource = `class Stack:
+    def __init__(self):
+        self.items = []
+
+    def push(self, item):
+        self.items.append(item)
+
+    def pop(self):
+        if not self.items:
+            raise IndexError("empty stack")
+        return self.items.pop()
+`
+
+func createTestArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func getTestFingerprints(t *testing.T, files map[string]string) submissionFingerprints {
+	t.Helper()
+
+	fingerprints, err := getArchiveFingerprints(createTestArchive(t, files))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fingerprints
+}
+
+func TestTokenizeSourceNormalizesIdentifiers(t *testing.T) {
+	first := tokenizeSource("total = total + price * 2 // Add the price")
+	second := tokenizeSource("/* Sum */ acc = acc + value * 10")
+
+	if len(first) != len(second) {
+		t.Fatalf("expected the same number of tokens, got %d and %d", len(first), len(second))
+	}
+
+	for i := range first {
+		if first[i].value != second[i].value {
+			t.Errorf("expected token %d to be %q, got %q", i, first[i].value, second[i].value)
+		}
+	}
+}
+
+func TestTokenizeSourceTracksLines(t *testing.T) {
+	tokens := tokenizeSource("a = 1\n/* multi\nline */\nb = \"\"\"doc\nstring\"\"\"\nc = 3")
+
+	lastToken := tokens[len(tokens)-1]
+	if lastToken.line != 6 {
+		t.Errorf("expected the last token to be in line 6, got %d", lastToken.line)
+	}
+}
+
+func TestRenamedSubmissionsAreSimilar(t *testing.T) {
+	original := getTestFingerprints(t, map[string]string{"main.py": originalSource})
+	renamed := getTestFingerprints(t, map[string]string{"src/solution.py": renamedSource})
+
+	similarity, regions := compareFingerprints(original, renamed)
+	if similarity < 80 {
+		t.Errorf("expected a similarity of, at least, 80%%, got %.2f%%", similarity)
+	}
+
+	if len(regions) == 0 {
+		t.Fatal("expected, at least, one matching region")
+	}
+
+	for _, region := range regions {
+		if region.FirstFile != "main.py" || region.SecondFile != "src/solution.py" {
+			t.Errorf("unexpected files in the matching region: %+v", region)
+		}
+
+		if region.FirstStartLine > region.FirstEndLine || region.SecondStartLine > region.SecondEndLine {
+			t.Errorf("unexpected lines in the matching region: %+v", region)
+		}
+	}
+}
+
+func TestUnrelatedSubmissionsAreNotSimilar(t *testing.T) {
+	original := getTestFingerprints(t, map[string]string{"main.py": originalSource})
+	unrelated := getTestFingerprints(t, map[string]string{"main.py": unrelatedSource})
+
+	similarity, _ := compareFingerprints(original, unrelated)
+	if similarity > 20 {
+		t.Errorf("expected a similarity of, at most, 20%%, got %.2f%%", similarity)
+	}
+}
+
+func TestBoilerplateIsIgnored(t *testing.T) {
+	template := map[string]string{"main.py": originalSource}
+
+	first := getTestFingerprints(t, template)
+	second := getTestFingerprints(t, template)
+
+	boilerplate := getTestFingerprints(t, template)
+	first.removeBoilerplate(boilerplate)
+	second.removeBoilerplate(boilerplate)
+
+	similarity, regions := compareFingerprints(first, second)
+	if similarity != 0 || len(regions) != 0 {
+		t.Errorf("expected the template to be ignored, got %.2f%% and %d regions", similarity, len(regions))
+	}
+}
+
+func TestNonSourceFilesAreIgnored(t *testing.T) {
+	fingerprints := getTestFingerprints(t, map[string]string{
+		".git/config":              originalSource,
+		"__MACOSX/._main.py":       originalSource,
+		"__pycache__/main.pyc":     originalSource,
+		"binary.bin":               "\x00\x01\x02" + originalSource,
+		"docs/../.hidden/notes.py": originalSource,
+	})
+
+	if len(fingerprints) != 0 {
+		t.Errorf("expected no fingerprints, got %d", len(fingerprints))
+	}
+}
@@ -0,0 +1,6 @@
+package definitions
+
+type PlagiarismReportsQueue interface {
+	// Wake asks the queue to generate the pending reports without waiting for its next run
+	Wake()
+}
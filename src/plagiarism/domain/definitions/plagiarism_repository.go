@@ -0,0 +1,21 @@
+package definitions
+
+import (
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/entities"
+)
+
+type PlagiarismRepository interface {
+	// Reports
+	SaveReport(dto *dtos.CreatePlagiarismReportDTO) (reportUUID string, err error)
+	GetReportByUUID(reportUUID string) (report *entities.PlagiarismReport, err error)
+	ClaimPendingReport(maxAttempts int) (reportUUID string, err error)
+	SetReportAsFailed(reportUUID string, errorMessage string) (err error)
+
+	// Results
+	SaveReportResults(dto *dtos.PlagiarismReportResultsDTO) (err error)
+	GetReportPairs(reportUUID string) (pairs []*entities.PlagiarismPair, err error)
+
+	// Submissions to compare
+	GetLatestSubmissionsOfTestBlock(testBlockUUID string) (submissions []*dtos.PlagiarismSubmissionDTO, err error)
+}
@@ -0,0 +1,39 @@
+package dtos
+
+import "github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/entities"
+
+// CreatePlagiarismReportDTO data transfer object to parse the request of the endpoint
+type CreatePlagiarismReportDTO struct {
+	TeacherUUID             string
+	TestBlockUUID           string
+	MinSimilarityPercentage float64
+}
+
+// GetPlagiarismReportDTO data transfer object to parse the request of the endpoint
+type GetPlagiarismReportDTO struct {
+	TeacherUUID string
+	ReportUUID  string
+}
+
+// PlagiarismReportDTO data transfer object to be used as the response of the endpoint. The pairs
+// are ranked from the most to the least similar
+type PlagiarismReportDTO struct {
+	*entities.PlagiarismReport
+	Pairs []*entities.PlagiarismPair `json:"pairs"`
+}
+
+// PlagiarismReportResultsDTO data transfer object to save the results of a report
+type PlagiarismReportResultsDTO struct {
+	ReportUUID          string
+	AnalyzedSubmissions int
+	SkippedSubmissions  int
+	Pairs               []*entities.PlagiarismPair
+}
+
+// PlagiarismSubmissionDTO latest submission of a student to be compared against the other students' submissions
+type PlagiarismSubmissionDTO struct {
+	SubmissionUUID  string
+	ArchiveUUID     string
+	StudentUUID     string
+	StudentFullName string
+}
@@ -0,0 +1,39 @@
+package entities
+
+// PlagiarismReport report comparing the latest submissions of the students to a test block against each other
+type PlagiarismReport struct {
+	UUID                    string  `json:"uuid"`
+	TestBlockUUID           string  `json:"test_block_uuid"`
+	Status                  string  `json:"status"`
+	MinSimilarityPercentage float64 `json:"min_similarity_percentage"`
+	AnalyzedSubmissions     int     `json:"analyzed_submissions"`
+	SkippedSubmissions      int     `json:"skipped_submissions"`
+	ErrorMessage            *string `json:"error_message"`
+	CreatedAt               string  `json:"created_at"`
+	FinishedAt              *string `json:"finished_at"`
+}
+
+// PlagiarismPair pair of submissions whose similarity is above the threshold of the report
+type PlagiarismPair struct {
+	FirstSubmission      PlagiarismPairSubmission `json:"first_submission"`
+	SecondSubmission     PlagiarismPairSubmission `json:"second_submission"`
+	SimilarityPercentage float64                  `json:"similarity_percentage"`
+	MatchingRegions      []*MatchingRegion        `json:"matching_regions"`
+}
+
+// PlagiarismPairSubmission submission of a pair of suspicious submissions
+type PlagiarismPairSubmission struct {
+	SubmissionUUID  string `json:"submission_uuid"`
+	StudentUUID     string `json:"student_uuid"`
+	StudentFullName string `json:"student_full_name"`
+}
+
+// MatchingRegion region of code found in both submissions of a pair
+type MatchingRegion struct {
+	FirstFile       string `json:"first_file"`
+	FirstStartLine  int    `json:"first_start_line"`
+	FirstEndLine    int    `json:"first_end_line"`
+	SecondFile      string `json:"second_file"`
+	SecondStartLine int    `json:"second_start_line"`
+	SecondEndLine   int    `json:"second_end_line"`
+}
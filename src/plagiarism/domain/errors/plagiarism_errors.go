@@ -0,0 +1,26 @@
+package errors
+
+import "net/http"
+
+// PlagiarismReportNotFoundError error to be thrown when a plagiarism report is not found
+type PlagiarismReportNotFoundError struct{}
+
+func (err PlagiarismReportNotFoundError) Error() string {
+	return "The plagiarism report was not found"
+}
+
+func (err PlagiarismReportNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+// TeacherCannotAccessPlagiarismReportError error to be thrown when a teacher tries to create or read a
+// plagiarism report of a test block they do not own
+type TeacherCannotAccessPlagiarismReportError struct{}
+
+func (err TeacherCannotAccessPlagiarismReportError) Error() string {
+	return "You do not have permission to access the plagiarism reports of this test block"
+}
+
+func (err TeacherCannotAccessPlagiarismReportError) StatusCode() int {
+	return http.StatusForbidden
+}
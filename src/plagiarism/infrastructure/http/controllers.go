@@ -0,0 +1,99 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/application"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/infrastructure/requests"
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/gin-gonic/gin"
+)
+
+// Pairs of submissions less similar than this are not included in the reports by default
+const defaultMinSimilarityPercentage = 30
+
+type PlagiarismController struct {
+	UseCases *application.PlagiarismUseCases
+}
+
+// HandleCreatePlagiarismReport controller to schedule the comparison of the latest submissions of the
+// students to a test block
+func (controller *PlagiarismController) HandleCreatePlagiarismReport(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	testBlockUUID := c.Param("test_block_uuid")
+
+	// Validate the test block UUID
+	if err := sharedInfrastructure.GetValidator().Var(testBlockUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Test block UUID is not valid",
+		})
+		return
+	}
+
+	// Parse the request body. The body is optional
+	var request requests.CreatePlagiarismReportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": "Request body is not valid",
+			})
+			return
+		}
+	}
+
+	// Validate the request body
+	if err := sharedInfrastructure.GetValidator().Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Validation error",
+			"errors":  err.Error(),
+		})
+		return
+	}
+
+	minSimilarityPercentage := float64(defaultMinSimilarityPercentage)
+	if request.MinSimilarityPercentage != nil {
+		minSimilarityPercentage = *request.MinSimilarityPercentage
+	}
+
+	// Create the report
+	reportUUID, err := controller.UseCases.CreatePlagiarismReport(&dtos.CreatePlagiarismReportDTO{
+		TeacherUUID:             teacherUUID,
+		TestBlockUUID:           testBlockUUID,
+		MinSimilarityPercentage: minSimilarityPercentage,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"uuid": reportUUID,
+	})
+}
+
+// HandleGetPlagiarismReport controller to get the status and the results of a plagiarism report
+func (controller *PlagiarismController) HandleGetPlagiarismReport(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	reportUUID := c.Param("report_uuid")
+
+	// Validate the report UUID
+	if err := sharedInfrastructure.GetValidator().Var(reportUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Report UUID is not valid",
+		})
+		return
+	}
+
+	report, err := controller.UseCases.GetPlagiarismReport(&dtos.GetPlagiarismReportDTO{
+		TeacherUUID: teacherUUID,
+		ReportUUID:  reportUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
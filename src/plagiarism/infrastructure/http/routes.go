@@ -0,0 +1,41 @@
+package http
+
+import (
+	blocksImplementations "github.com/UPB-Code-Labs/main-api/src/blocks/infrastructure/implementations"
+	languagesImplementations "github.com/UPB-Code-Labs/main-api/src/languages/infrastructure/implementations"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/application"
+	plagiarismImplementations "github.com/UPB-Code-Labs/main-api/src/plagiarism/infrastructure/implementations"
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	staticFilesImplementations "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
+	"github.com/gin-gonic/gin"
+)
+
+func StartPlagiarismRoutes(g *gin.RouterGroup) {
+	plagiarismGroup := g.Group("/plagiarism")
+
+	useCases := application.PlagiarismUseCases{
+		PlagiarismRepository:   plagiarismImplementations.GetPlagiarismPostgresRepositoryInstance(),
+		PlagiarismReportsQueue: plagiarismImplementations.GetPlagiarismReportsQueueInstance(),
+		BlocksRepository:       blocksImplementations.GetBlocksPostgresRepositoryInstance(),
+		LanguagesRepository:    languagesImplementations.GetLanguagesRepositoryInstance(),
+		StaticFilesRepository:  staticFilesImplementations.GetStaticFilesRepositoryInstance(),
+	}
+
+	controller := &PlagiarismController{
+		UseCases: &useCases,
+	}
+
+	plagiarismGroup.POST(
+		"/test_blocks/:test_block_uuid/reports",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleCreatePlagiarismReport,
+	)
+
+	plagiarismGroup.GET(
+		"/reports/:report_uuid",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controller.HandleGetPlagiarismReport,
+	)
+}
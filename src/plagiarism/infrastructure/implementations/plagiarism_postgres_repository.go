@@ -0,0 +1,311 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/entities"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/domain/errors"
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+)
+
+// PlagiarismPostgresRepository implementation of the PlagiarismRepository interface
+type PlagiarismPostgresRepository struct {
+	Connection *sql.DB
+}
+
+var plagiarismRepositoryInstance *PlagiarismPostgresRepository
+
+// GetPlagiarismPostgresRepositoryInstance returns the singleton instance of the PlagiarismPostgresRepository
+func GetPlagiarismPostgresRepositoryInstance() *PlagiarismPostgresRepository {
+	if plagiarismRepositoryInstance == nil {
+		plagiarismRepositoryInstance = &PlagiarismPostgresRepository{
+			Connection: sharedInfrastructure.GetPostgresConnection(),
+		}
+	}
+
+	return plagiarismRepositoryInstance
+}
+
+// SaveReport saves a new report waiting to be generated
+func (repository *PlagiarismPostgresRepository) SaveReport(dto *dtos.CreatePlagiarismReportDTO) (reportUUID string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO plagiarism_reports (test_block_id, teacher_id, min_similarity_percentage)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	err = repository.Connection.QueryRowContext(
+		ctx,
+		query,
+		dto.TestBlockUUID,
+		dto.TeacherUUID,
+		dto.MinSimilarityPercentage,
+	).Scan(&reportUUID)
+	if err != nil {
+		return "", err
+	}
+
+	return reportUUID, nil
+}
+
+// GetReportByUUID returns the information and the status of a report
+func (repository *PlagiarismPostgresRepository) GetReportByUUID(reportUUID string) (report *entities.PlagiarismReport, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id, test_block_id, status, min_similarity_percentage, analyzed_submissions, skipped_submissions,
+			error_message, created_at, finished_at
+		FROM plagiarism_reports
+		WHERE id = $1
+	`
+
+	report = &entities.PlagiarismReport{}
+	err = repository.Connection.QueryRowContext(ctx, query, reportUUID).Scan(
+		&report.UUID,
+		&report.TestBlockUUID,
+		&report.Status,
+		&report.MinSimilarityPercentage,
+		&report.AnalyzedSubmissions,
+		&report.SkippedSubmissions,
+		&report.ErrorMessage,
+		&report.CreatedAt,
+		&report.FinishedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.PlagiarismReportNotFoundError{}
+		}
+
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ClaimPendingReport marks the oldest pending report as running and locks it for a while, so other instances
+// of the gateway do not generate it at the same time. Running reports whose lock expired (e.g. the gateway
+// crashed while generating them) are claimed again, until they reach the maximum number of attempts, then
+// they are marked as failed. Returns an empty UUID if there are no reports to generate
+func (repository *PlagiarismPostgresRepository) ClaimPendingReport(maxAttempts int) (reportUUID string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Start the transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	// Give up on the reports that were abandoned too many times
+	query := `
+		UPDATE plagiarism_reports
+		SET status = 'failed', error_message = 'The report could not be generated after several attempts', finished_at = CURRENT_TIMESTAMP
+		WHERE status = 'running'
+			AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+			AND attempts >= $1
+	`
+
+	_, err = tx.ExecContext(ctx, query, maxAttempts)
+	if err != nil {
+		return "", err
+	}
+
+	query = `
+		UPDATE plagiarism_reports
+		SET status = 'running', attempts = attempts + 1, locked_until = CURRENT_TIMESTAMP + INTERVAL '15 minutes'
+		WHERE id IN (
+			SELECT id
+			FROM plagiarism_reports
+			WHERE (
+					status = 'pending'
+					OR (status = 'running' AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP))
+				)
+				AND attempts < $1
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`
+
+	err = tx.QueryRowContext(ctx, query, maxAttempts).Scan(&reportUUID)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return reportUUID, nil
+}
+
+// SetReportAsFailed marks a report as failed and saves the reason
+func (repository *PlagiarismPostgresRepository) SetReportAsFailed(reportUUID string, errorMessage string) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE plagiarism_reports
+		SET status = 'failed', error_message = $1, finished_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	_, err = repository.Connection.ExecContext(ctx, query, errorMessage, reportUUID)
+	return err
+}
+
+// SaveReportResults saves the pairs of suspicious submissions of a report and marks it as ready
+func (repository *PlagiarismPostgresRepository) SaveReportResults(dto *dtos.PlagiarismReportResultsDTO) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	// Start the transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO plagiarism_report_pairs (report_id, first_submission_id, second_submission_id, similarity_percentage, matching_regions)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	for _, pair := range dto.Pairs {
+		matchingRegionsJSON, err := json.Marshal(pair.MatchingRegions)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			dto.ReportUUID,
+			pair.FirstSubmission.SubmissionUUID,
+			pair.SecondSubmission.SubmissionUUID,
+			pair.SimilarityPercentage,
+			matchingRegionsJSON,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	query = `
+		UPDATE plagiarism_reports
+		SET status = 'ready', analyzed_submissions = $1, skipped_submissions = $2, finished_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`
+
+	_, err = tx.ExecContext(ctx, query, dto.AnalyzedSubmissions, dto.SkippedSubmissions, dto.ReportUUID)
+	if err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
+
+// GetReportPairs returns the pairs of suspicious submissions of a report, from the most to the least similar
+func (repository *PlagiarismPostgresRepository) GetReportPairs(reportUUID string) (pairs []*entities.PlagiarismPair, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			first_submission.id, first_student.id, first_student.full_name,
+			second_submission.id, second_student.id, second_student.full_name,
+			prp.similarity_percentage, prp.matching_regions
+		FROM plagiarism_report_pairs AS prp
+		INNER JOIN submissions AS first_submission ON prp.first_submission_id = first_submission.id
+		INNER JOIN users AS first_student ON first_submission.student_id = first_student.id
+		INNER JOIN submissions AS second_submission ON prp.second_submission_id = second_submission.id
+		INNER JOIN users AS second_student ON second_submission.student_id = second_student.id
+		WHERE prp.report_id = $1
+		ORDER BY prp.similarity_percentage DESC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, reportUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairs = []*entities.PlagiarismPair{}
+	for rows.Next() {
+		pair := entities.PlagiarismPair{}
+		var matchingRegionsJSON []byte
+
+		if err := rows.Scan(
+			&pair.FirstSubmission.SubmissionUUID,
+			&pair.FirstSubmission.StudentUUID,
+			&pair.FirstSubmission.StudentFullName,
+			&pair.SecondSubmission.SubmissionUUID,
+			&pair.SecondSubmission.StudentUUID,
+			&pair.SecondSubmission.StudentFullName,
+			&pair.SimilarityPercentage,
+			&matchingRegionsJSON,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(matchingRegionsJSON, &pair.MatchingRegions); err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, &pair)
+	}
+
+	return pairs, nil
+}
+
+// GetLatestSubmissionsOfTestBlock returns the latest submission of each student to a test block
+func (repository *PlagiarismPostgresRepository) GetLatestSubmissionsOfTestBlock(testBlockUUID string) (submissions []*dtos.PlagiarismSubmissionDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT s.id, a.file_id, u.id, u.full_name
+		FROM latest_submissions AS s
+		INNER JOIN users AS u ON s.student_id = u.id
+		INNER JOIN archives AS a ON s.archive_id = a.id
+		WHERE s.test_block_id = $1
+		ORDER BY u.full_name ASC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, testBlockUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submissions = []*dtos.PlagiarismSubmissionDTO{}
+	for rows.Next() {
+		submission := dtos.PlagiarismSubmissionDTO{}
+
+		if err := rows.Scan(
+			&submission.SubmissionUUID,
+			&submission.ArchiveUUID,
+			&submission.StudentUUID,
+			&submission.StudentFullName,
+		); err != nil {
+			return nil, err
+		}
+
+		submissions = append(submissions, &submission)
+	}
+
+	return submissions, nil
+}
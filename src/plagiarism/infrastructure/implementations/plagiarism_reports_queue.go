@@ -0,0 +1,95 @@
+package implementations
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	blocksImplementations "github.com/UPB-Code-Labs/main-api/src/blocks/infrastructure/implementations"
+	languagesImplementations "github.com/UPB-Code-Labs/main-api/src/languages/infrastructure/implementations"
+	"github.com/UPB-Code-Labs/main-api/src/plagiarism/application"
+	staticFilesImplementations "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
+)
+
+const (
+	// Times a report is claimed before giving up on it, a report that is abandoned several times (e.g. the
+	// gateway crashes while generating it) would keep crashing the gateway
+	plagiarismReportsMaxAttempts = 3
+
+	// Time between each run of the queue when it is not woken up. The pending reports of a previous run of
+	// the gateway are picked up in the first run
+	plagiarismReportsQueueInterval = 30 * time.Second
+)
+
+// PlagiarismReportsQueue generates the plagiarism reports in background, one at a time, so comparing
+// the submissions does not compete with the requests for resources. The reports are claimed from the
+// table, so they are not lost if the gateway restarts
+type PlagiarismReportsQueue struct {
+	// Signals the queue to run without waiting for the next tick
+	wakeUp chan struct{}
+}
+
+// Singleton instance
+var plagiarismReportsQueueInstance *PlagiarismReportsQueue
+var plagiarismReportsQueueOnce sync.Once
+
+func GetPlagiarismReportsQueueInstance() *PlagiarismReportsQueue {
+	plagiarismReportsQueueOnce.Do(func() {
+		plagiarismReportsQueueInstance = &PlagiarismReportsQueue{
+			wakeUp: make(chan struct{}, 1),
+		}
+	})
+
+	return plagiarismReportsQueueInstance
+}
+
+// Wake asks the queue to generate the pending reports without waiting for its next run
+func (queue *PlagiarismReportsQueue) Wake() {
+	select {
+	case queue.wakeUp <- struct{}{}:
+	default:
+		// The queue is already going to run
+	}
+}
+
+// Listen generates the pending reports every time the queue is woken up or the interval elapses
+func (queue *PlagiarismReportsQueue) Listen() {
+	log.Println("[Plagiarism reports]: Listening for new reports")
+
+	useCases := application.PlagiarismUseCases{
+		PlagiarismRepository:   GetPlagiarismPostgresRepositoryInstance(),
+		PlagiarismReportsQueue: queue,
+		BlocksRepository:       blocksImplementations.GetBlocksPostgresRepositoryInstance(),
+		LanguagesRepository:    languagesImplementations.GetLanguagesRepositoryInstance(),
+		StaticFilesRepository:  staticFilesImplementations.GetStaticFilesRepositoryInstance(),
+	}
+
+	ticker := time.NewTicker(plagiarismReportsQueueInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep generating while there are reports waiting
+		for {
+			reportUUID, err := useCases.GenerateNextPlagiarismReport(plagiarismReportsMaxAttempts)
+			if reportUUID == "" {
+				if err != nil {
+					log.Println("[Plagiarism reports]: There was an error while claiming the pending reports", err.Error())
+				}
+				break
+			}
+
+			if err != nil {
+				log.Printf(
+					"[Plagiarism reports]: There was an error while generating the report %s: %s",
+					reportUUID,
+					err.Error(),
+				)
+			}
+		}
+
+		select {
+		case <-queue.wakeUp:
+		case <-ticker.C:
+		}
+	}
+}
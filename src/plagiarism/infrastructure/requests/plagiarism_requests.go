@@ -0,0 +1,6 @@
+package requests
+
+// CreatePlagiarismReportRequest request to compare the latest submissions of the students to a test block
+type CreatePlagiarismReportRequest struct {
+	MinSimilarityPercentage *float64 `json:"min_similarity_percentage" validate:"omitempty,min=0,max=100"`
+}
@@ -0,0 +1,47 @@
+package entities
+
+// ExecutionLimits limits the runners apply when running the tests of a submission
+type ExecutionLimits struct {
+	CPUTimeMs    int `json:"cpu_time_ms"`
+	WallTimeMs   int `json:"wall_time_ms"`
+	MemoryMb     int `json:"memory_mb"`
+	OutputSizeKb int `json:"output_size_kb"`
+}
+
+// ExecutionLimitsOverrides limits that replace the ones of a language. Nil limits are not replaced
+type ExecutionLimitsOverrides struct {
+	CPUTimeMs    *int `json:"cpu_time_ms"`
+	WallTimeMs   *int `json:"wall_time_ms"`
+	MemoryMb     *int `json:"memory_mb"`
+	OutputSizeKb *int `json:"output_size_kb"`
+}
+
+// DefaultExecutionLimits limits of the languages created without explicit limits
+var DefaultExecutionLimits = ExecutionLimits{
+	CPUTimeMs:    10000,
+	WallTimeMs:   20000,
+	MemoryMb:     512,
+	OutputSizeKb: 1024,
+}
+
+// WithOverrides returns a copy of the limits with the overridden ones replaced
+func (limits ExecutionLimits) WithOverrides(overrides *ExecutionLimitsOverrides) ExecutionLimits {
+	if overrides == nil {
+		return limits
+	}
+
+	if overrides.CPUTimeMs != nil {
+		limits.CPUTimeMs = *overrides.CPUTimeMs
+	}
+	if overrides.WallTimeMs != nil {
+		limits.WallTimeMs = *overrides.WallTimeMs
+	}
+	if overrides.MemoryMb != nil {
+		limits.MemoryMb = *overrides.MemoryMb
+	}
+	if overrides.OutputSizeKb != nil {
+		limits.OutputSizeKb = *overrides.OutputSizeKb
+	}
+
+	return limits
+}
@@ -0,0 +1,16 @@
+package errors
+
+import (
+	"net/http"
+)
+
+type BrokerUnavailableError struct {
+}
+
+func (err BrokerUnavailableError) Error() string {
+	return "The message broker is not available at the moment, please try again later"
+}
+
+func (err BrokerUnavailableError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
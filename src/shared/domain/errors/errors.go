@@ -5,6 +5,13 @@ type DomainError interface {
 	StatusCode() int
 }
 
+// DomainErrorWithDetails domain error that lists the reasons of the failure (e.g. every rule an uploaded
+// archive violates), sent to the client along with the message
+type DomainErrorWithDetails interface {
+	DomainError
+	Details() []string
+}
+
 type GenericDomainError struct {
 	Code    int
 	Message string
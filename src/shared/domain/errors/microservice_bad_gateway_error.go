@@ -0,0 +1,16 @@
+package errors
+
+import (
+	"net/http"
+)
+
+type MicroserviceBadGatewayError struct {
+}
+
+func (err MicroserviceBadGatewayError) Error() string {
+	return "The files service was unable to process the request, please try again later"
+}
+
+func (err MicroserviceBadGatewayError) StatusCode() int {
+	return http.StatusBadGateway
+}
@@ -0,0 +1,16 @@
+package errors
+
+import (
+	"net/http"
+)
+
+type MicroserviceUnavailableError struct {
+}
+
+func (err MicroserviceUnavailableError) Error() string {
+	return "The files service is not available at the moment, please try again later"
+}
+
+func (err MicroserviceUnavailableError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
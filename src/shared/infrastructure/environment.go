@@ -17,6 +17,13 @@ type EnvironmentSpec struct {
 	WebClientUrl                   string `split_words:"true" default:"http://localhost:5173"`
 	StaticFilesMicroserviceAddress string `split_words:"true" default:"http://localhost:8081"`
 
+	// Where the archives are stored: `microservice` or `local`
+	StaticFilesStorage   string `split_words:"true" default:"microservice"`
+	StaticFilesLocalPath string `split_words:"true" default:"volumes/static-files"`
+
+	// Seconds to wait for the static files microservice to respond
+	StaticFilesMicroserviceTimeoutSeconds int `split_words:"true" default:"30"`
+
 	// PgSQL migration files
 	DbMigrationsPath string `split_words:"true" default:"file://sql/migrations"`
 
@@ -26,6 +33,31 @@ type EnvironmentSpec struct {
 
 	// Configuration parameters
 	ArchiveMaxSizeKb int64 `split_words:"true" default:"1024"`
+
+	// Maximum size of the imported laboratory bundles, each test archive they contain is limited by the
+	// archives size
+	LaboratoryBundleMaxSizeKb int64 `split_words:"true" default:"16384"`
+
+	// Limits of the content of the uploaded archives
+	ArchiveMaxUncompressedSizeKb int64    `split_words:"true" default:"10240"`
+	ArchiveMaxFiles              int      `split_words:"true" default:"1000"`
+	ArchiveForbiddenExtensions   []string `split_words:"true" default:".exe,.dll,.so,.dylib,.bat,.cmd,.msi"`
+
+	// Submissions watchdog parameters
+	SubmissionsTimeoutSeconds          int `split_words:"true" default:"300"`
+	SubmissionsMaxRequeueAttempts      int `split_words:"true" default:"2"`
+	SubmissionsWatchdogIntervalSeconds int `split_words:"true" default:"60"`
+
+	// Time a submission work can wait in the queue before being dead-lettered
+	SubmissionsWorkTtlSeconds int `split_words:"true" default:"3600"`
+
+	// Orphaned archives collector parameters
+	OrphanedArchivesGracePeriodSeconds       int `split_words:"true" default:"3600"`
+	OrphanedArchivesCollectorIntervalSeconds int `split_words:"true" default:"3600"`
+
+	// Deleted courses and laboratories can be restored within this window, then the purger removes them
+	DeletionsRestoreWindowSeconds  int `split_words:"true" default:"2592000"`
+	DeletionsPurgerIntervalSeconds int `split_words:"true" default:"3600"`
 }
 
 var environment *EnvironmentSpec
@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strconv"
+
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+	"github.com/gin-gonic/gin"
+)
+
+// ExecutionLimitsRequest execution limits sent by the admins for a language or by the teachers for a test
+// block. Limits that were not sent are nil
+type ExecutionLimitsRequest struct {
+	CPUTimeMs    *int `json:"cpu_time_ms" validate:"omitempty,min=100,max=600000"`
+	WallTimeMs   *int `json:"wall_time_ms" validate:"omitempty,min=100,max=1800000"`
+	MemoryMb     *int `json:"memory_mb" validate:"omitempty,min=16,max=8192"`
+	OutputSizeKb *int `json:"output_size_kb" validate:"omitempty,min=1,max=10240"`
+}
+
+// GetExecutionLimitsRequestFromForm parses the optional execution limits fields of a multipart form. The fields
+// are named as the JSON fields of the limits
+func GetExecutionLimitsRequestFromForm(c *gin.Context) (*ExecutionLimitsRequest, error) {
+	req := &ExecutionLimitsRequest{}
+
+	fields := []struct {
+		name  string
+		limit **int
+	}{
+		{"cpu_time_ms", &req.CPUTimeMs},
+		{"wall_time_ms", &req.WallTimeMs},
+		{"memory_mb", &req.MemoryMb},
+		{"output_size_kb", &req.OutputSizeKb},
+	}
+
+	for _, field := range fields {
+		value := c.PostForm(field.name)
+		if value == "" {
+			continue
+		}
+
+		parsedValue, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("the `%s` field must be an integer", field.name)
+		}
+
+		*field.limit = &parsedValue
+	}
+
+	return req, nil
+}
+
+// ToOverrides returns the limits that were sent
+func (req *ExecutionLimitsRequest) ToOverrides() *sharedEntities.ExecutionLimitsOverrides {
+	return &sharedEntities.ExecutionLimitsOverrides{
+		CPUTimeMs:    req.CPUTimeMs,
+		WallTimeMs:   req.WallTimeMs,
+		MemoryMb:     req.MemoryMb,
+		OutputSizeKb: req.OutputSizeKb,
+	}
+}
@@ -0,0 +1,64 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newExecutionLimitsFormContext(form url.Values) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c
+}
+
+func TestGetExecutionLimitsRequestFromForm(t *testing.T) {
+	c := newExecutionLimitsFormContext(url.Values{
+		"cpu_time_ms": {"2000"},
+		"memory_mb":   {"256"},
+	})
+
+	req, err := GetExecutionLimitsRequestFromForm(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.CPUTimeMs == nil || *req.CPUTimeMs != 2000 {
+		t.Errorf("expected the CPU time limit to be 2000, got %v", req.CPUTimeMs)
+	}
+	if req.MemoryMb == nil || *req.MemoryMb != 256 {
+		t.Errorf("expected the memory limit to be 256, got %v", req.MemoryMb)
+	}
+
+	// The limits that were not sent are not overridden
+	if req.WallTimeMs != nil || req.OutputSizeKb != nil {
+		t.Error("expected the limits that were not sent to be nil")
+	}
+
+	if err := GetValidator().Struct(req); err != nil {
+		t.Errorf("expected the limits to be valid, got %v", err)
+	}
+}
+
+func TestGetExecutionLimitsRequestFromFormRejectsInvalidLimits(t *testing.T) {
+	c := newExecutionLimitsFormContext(url.Values{"wall_time_ms": {"two seconds"}})
+	if _, err := GetExecutionLimitsRequestFromForm(c); err == nil {
+		t.Error("expected an error for a limit that is not an integer")
+	}
+
+	c = newExecutionLimitsFormContext(url.Values{"output_size_kb": {"0"}})
+	req, err := GetExecutionLimitsRequestFromForm(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := GetValidator().Struct(req); err == nil {
+		t.Error("expected an error for a limit out of range")
+	}
+}
@@ -0,0 +1,186 @@
+package infrastructure
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
+)
+
+// MicroserviceHTTPClientConfig parameters of the client used to request a microservice
+type MicroserviceHTTPClientConfig struct {
+	// Time to wait for the headers of the response
+	Timeout time.Duration
+
+	// Times an idempotent request is retried and the delay before the first retry, doubled after each retry
+	MaxRetries int
+	RetryDelay time.Duration
+
+	// Consecutive failures that open the circuit and the time it stays open before a new request is tried
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+// MicroserviceHTTPClient HTTP client shared by all the requests to a microservice. It retries the idempotent
+// requests that fail and stops sending requests for a while (opens the circuit) when the microservice keeps
+// failing, so the requests fail fast instead of waiting for the timeout
+type MicroserviceHTTPClient struct {
+	client *http.Client
+	config MicroserviceHTTPClientConfig
+
+	// Circuit breaker state
+	consecutiveFailures int
+	openUntil           time.Time
+	isProbing           bool
+	mutex               sync.Mutex
+}
+
+// Singleton instance
+var staticFilesMicroserviceClientInstance *MicroserviceHTTPClient
+var staticFilesMicroserviceClientOnce sync.Once
+
+// GetStaticFilesMicroserviceClient returns the client used to request the static files microservice
+func GetStaticFilesMicroserviceClient() *MicroserviceHTTPClient {
+	staticFilesMicroserviceClientOnce.Do(func() {
+		staticFilesMicroserviceClientInstance = NewMicroserviceHTTPClient(MicroserviceHTTPClientConfig{
+			Timeout:          time.Duration(GetEnvironment().StaticFilesMicroserviceTimeoutSeconds) * time.Second,
+			MaxRetries:       2,
+			RetryDelay:       200 * time.Millisecond,
+			FailureThreshold: 5,
+			OpenDuration:     30 * time.Second,
+		})
+	})
+
+	return staticFilesMicroserviceClientInstance
+}
+
+// NewMicroserviceHTTPClient creates a client with a closed circuit
+func NewMicroserviceHTTPClient(config MicroserviceHTTPClientConfig) *MicroserviceHTTPClient {
+	// The whole request is not limited by a timeout, as the archives are streamed to the clients
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: config.Timeout,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   16,
+	}
+
+	return &MicroserviceHTTPClient{
+		client: &http.Client{Transport: transport},
+		config: config,
+	}
+}
+
+// Do sends a request once. Used for the requests that can not be repeated safely (e.g. saving a new archive)
+func (client *MicroserviceHTTPClient) Do(request *http.Request) (*http.Response, error) {
+	return client.send(request)
+}
+
+// DoIdempotent sends a request, retrying it when the microservice is not reachable or unavailable. Requests
+// whose body can not be read again are sent only once
+func (client *MicroserviceHTTPClient) DoIdempotent(request *http.Request) (*http.Response, error) {
+	canRetry := request.Body == nil || request.GetBody != nil
+	delay := client.config.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		response, err := client.send(request)
+		if !canRetry || attempt >= client.config.MaxRetries || !isRetryable(response, err) {
+			return response, err
+		}
+
+		if response != nil {
+			response.Body.Close()
+		}
+
+		// Wait before retrying, unless the request was cancelled
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		// Read the body again
+		if request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			request = request.Clone(request.Context())
+			request.Body = body
+		}
+	}
+}
+
+// send sends a request if the circuit is not open and records its result
+func (client *MicroserviceHTTPClient) send(request *http.Request) (*http.Response, error) {
+	if !client.allowRequest() {
+		if request.Body != nil {
+			request.Body.Close()
+		}
+
+		return nil, errors.MicroserviceUnavailableError{}
+	}
+
+	response, err := client.client.Do(request)
+	client.recordResult(err == nil && response.StatusCode < http.StatusInternalServerError)
+	return response, err
+}
+
+// allowRequest returns false while the circuit is open. Once the open duration elapses, a single request is
+// allowed to check if the microservice recovered
+func (client *MicroserviceHTTPClient) allowRequest() bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.consecutiveFailures < client.config.FailureThreshold {
+		return true
+	}
+
+	if time.Now().Before(client.openUntil) || client.isProbing {
+		return false
+	}
+
+	client.isProbing = true
+	return true
+}
+
+// recordResult closes the circuit after a success and opens it after too many consecutive failures
+func (client *MicroserviceHTTPClient) recordResult(isSuccess bool) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.isProbing = false
+	if isSuccess {
+		client.consecutiveFailures = 0
+		return
+	}
+
+	client.consecutiveFailures++
+	if client.consecutiveFailures >= client.config.FailureThreshold {
+		client.openUntil = time.Now().Add(client.config.OpenDuration)
+	}
+}
+
+// isRetryable returns true if the request failed because the microservice was not reachable or unavailable
+func isRetryable(response *http.Response, err error) bool {
+	if err != nil {
+		// The circuit is open, retrying would fail too
+		_, isCircuitOpen := err.(errors.MicroserviceUnavailableError)
+		return !isCircuitOpen
+	}
+
+	switch response.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
+)
+
+func newTestMicroserviceClient() *MicroserviceHTTPClient {
+	return NewMicroserviceHTTPClient(MicroserviceHTTPClientConfig{
+		Timeout:          200 * time.Millisecond,
+		MaxRetries:       2,
+		RetryDelay:       10 * time.Millisecond,
+		FailureThreshold: 3,
+		OpenDuration:     200 * time.Millisecond,
+	})
+}
+
+// startFailingServer starts a server that fails with the given status the first `failures` requests
+func startFailingServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestIdempotentRequestsAreRetried(t *testing.T) {
+	server, requests := startFailingServer(t, 2, http.StatusServiceUnavailable)
+	client := newTestMicroserviceClient()
+
+	request, _ := http.NewRequest("POST", server.URL, strings.NewReader("payload"))
+	response, err := client.DoIdempotent(request)
+	if err := ParseMicroserviceError(response, err); err != nil {
+		t.Fatalf("expected the request to succeed after retrying, got %v", err)
+	}
+	response.Body.Close()
+
+	if *requests != 3 {
+		t.Errorf("expected 3 requests, got %d", *requests)
+	}
+}
+
+func TestNonIdempotentRequestsAreNotRetried(t *testing.T) {
+	server, requests := startFailingServer(t, 1, http.StatusServiceUnavailable)
+	client := newTestMicroserviceClient()
+
+	request, _ := http.NewRequest("POST", server.URL, strings.NewReader("payload"))
+	response, err := client.Do(request)
+	if err := ParseMicroserviceError(response, err); err != (errors.MicroserviceBadGatewayError{}) {
+		t.Fatalf("expected a MicroserviceBadGatewayError, got %v", err)
+	}
+
+	if *requests != 1 {
+		t.Errorf("expected 1 request, got %d", *requests)
+	}
+}
+
+func TestSlowResponsesTimeOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := newTestMicroserviceClient()
+
+	start := time.Now()
+	request, _ := http.NewRequest("GET", server.URL, nil)
+	response, err := client.Do(request)
+	if err := ParseMicroserviceError(response, err); err != (errors.MicroserviceUnavailableError{}) {
+		t.Fatalf("expected a MicroserviceUnavailableError, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the request to time out, it took %s", elapsed)
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	server, requests := startFailingServer(t, 3, http.StatusInternalServerError)
+	client := newTestMicroserviceClient()
+
+	send := func() error {
+		request, _ := http.NewRequest("GET", server.URL, nil)
+		response, err := client.Do(request)
+		return ParseMicroserviceError(response, err)
+	}
+
+	// Consecutive failures open the circuit
+	for i := 0; i < 3; i++ {
+		if err := send(); err != (errors.MicroserviceBadGatewayError{}) {
+			t.Fatalf("expected a MicroserviceBadGatewayError, got %v", err)
+		}
+	}
+
+	// The requests fail fast while the circuit is open
+	if err := send(); err != (errors.MicroserviceUnavailableError{}) {
+		t.Fatalf("expected a MicroserviceUnavailableError, got %v", err)
+	}
+
+	if *requests != 3 {
+		t.Errorf("expected the open circuit to not send requests, got %d requests", *requests)
+	}
+
+	// A request is tried once the circuit was open for a while, closing it
+	time.Sleep(250 * time.Millisecond)
+	if err := send(); err != nil {
+		t.Fatalf("expected the request to succeed, got %v", err)
+	}
+
+	if err := send(); err != nil {
+		t.Fatalf("expected the circuit to be closed, got %v", err)
+	}
+}
+
+func TestParseMicroserviceErrorForwardsClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Archive not found"}`))
+	}))
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	parsedError := ParseMicroserviceError(response, err)
+	if parsedError == nil || parsedError.Error() != "Archive not found" {
+		t.Fatalf("expected the message of the microservice, got %v", parsedError)
+	}
+
+	if statusCode := parsedError.(errors.DomainError).StatusCode(); statusCode != http.StatusNotFound {
+		t.Errorf("expected the status code of the microservice, got %d", statusCode)
+	}
+}
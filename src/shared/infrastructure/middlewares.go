@@ -13,6 +13,11 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 			err := c.Errors[0]
 
 			switch e := err.Err.(type) {
+			case sharedErrors.DomainErrorWithDetails:
+				c.JSON(e.StatusCode(), gin.H{
+					"message": e.Error(),
+					"errors":  e.Details(),
+				})
 			case sharedErrors.DomainError:
 				c.JSON(e.StatusCode(), gin.H{
 					"message": e.Error(),
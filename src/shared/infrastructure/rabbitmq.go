@@ -1,88 +1,490 @@
 package infrastructure
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-var rabbitMQChannel *amqp.Channel
-var rabbitMQSubmissionsQueue *amqp.Queue
+const (
+	// Name of the durable queue the submission works are published to
+	RabbitMQSubmissionsQueueName = "submissions"
 
-func ConnectToRabbitMQ() {
-	// Stablish connection
-	rabbitMQConnectionString := GetEnvironment().RabbitMQConnectionString
-	conn, err := amqp.Dial(rabbitMQConnectionString)
+	// Name of the exchange and queue the rejected or expired submission works are sent to
+	RabbitMQSubmissionsDeadLetterExchangeName = "submissions-dead-letter"
+	RabbitMQSubmissionsDeadLetterQueueName    = "submissions-dead-letter"
+)
+
+// RabbitMQTopology declares the queues, exchanges and bindings that must exist in every connection
+type RabbitMQTopology func(channel *amqp.Channel) error
+
+// RabbitMQConnectionManager keeps the connection to RabbitMQ alive: when the connection or one of its
+// channels is closed, it reconnects with an exponential backoff and declares the topology again
+type RabbitMQConnectionManager struct {
+	connectionString string
+
+	// Delays between the reconnection attempts
+	minReconnectDelay time.Duration
+	maxReconnectDelay time.Duration
+
+	// Topologies declared after each connection
+	topologies []RabbitMQTopology
+
+	// Current connection, nil while disconnected
+	connection       *amqp.Connection
+	channel          *amqp.Channel
+	publisherChannel *amqp.Channel
+
+	// Mandatory messages returned by the broker through the publisher channel
+	publisherReturns <-chan amqp.Return
+
+	// The mandatory messages are published one at a time, so the returned messages can be told apart
+	publishMutex      sync.Mutex
+	publishedMessages uint64
+
+	// Closed once the manager is connected, replaced by a new channel when the connection is lost
+	connected chan struct{}
+
+	// True once the connection was closed on purpose, so the manager does not reconnect
+	isClosed bool
+
+	mutex sync.RWMutex
+}
+
+// Singleton instance
+var rabbitMQConnectionManagerInstance *RabbitMQConnectionManager
+var rabbitMQConnectionManagerOnce sync.Once
+
+func GetRabbitMQConnectionManager() *RabbitMQConnectionManager {
+	rabbitMQConnectionManagerOnce.Do(func() {
+		rabbitMQConnectionManagerInstance = NewRabbitMQConnectionManager(GetEnvironment().RabbitMQConnectionString)
+		rabbitMQConnectionManagerInstance.AddTopology(declareRabbitMQSubmissionsDeadLetterQueue)
+		rabbitMQConnectionManagerInstance.AddTopology(declareRabbitMQSubmissionsQueue)
+	})
+
+	return rabbitMQConnectionManagerInstance
+}
+
+// NewRabbitMQConnectionManager creates a disconnected manager
+func NewRabbitMQConnectionManager(connectionString string) *RabbitMQConnectionManager {
+	return &RabbitMQConnectionManager{
+		connectionString:  connectionString,
+		minReconnectDelay: 500 * time.Millisecond,
+		maxReconnectDelay: 30 * time.Second,
+		topologies:        []RabbitMQTopology{},
+		connected:         make(chan struct{}),
+	}
+}
+
+// AddTopology registers a topology to be declared after each connection. The topology is declared
+// right away if the manager is already connected
+func (manager *RabbitMQConnectionManager) AddTopology(topology RabbitMQTopology) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.topologies = append(manager.topologies, topology)
+	if manager.channel == nil {
+		return nil
+	}
+
+	return topology(manager.channel)
+}
+
+// Connect opens the connection to RabbitMQ. Once connected, the manager reconnects by itself
+// until `Close` is called
+func (manager *RabbitMQConnectionManager) Connect() error {
+	return manager.connect()
+}
+
+// Close closes the connection without reconnecting
+func (manager *RabbitMQConnectionManager) Close() {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.isClosed = true
+	if manager.connection != nil {
+		manager.connection.Close()
+	}
+}
+
+// IsConnected returns true if the connection is currently open
+func (manager *RabbitMQConnectionManager) IsConnected() bool {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	return manager.channel != nil
+}
+
+// WaitForConnection returns a channel that is closed once the manager is connected
+func (manager *RabbitMQConnectionManager) WaitForConnection() <-chan struct{} {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	return manager.connected
+}
+
+// Channel returns the channel used to declare the topology and to consume messages. Fails
+// fast with a `BrokerUnavailableError` while disconnected
+func (manager *RabbitMQConnectionManager) Channel() (*amqp.Channel, error) {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	if manager.channel == nil {
+		return nil, errors.BrokerUnavailableError{}
+	}
+
+	return manager.channel, nil
+}
+
+// PublisherChannel returns the channel used to publish messages with publisher confirms. Fails
+// fast with a `BrokerUnavailableError` while disconnected
+func (manager *RabbitMQConnectionManager) PublisherChannel() (*amqp.Channel, error) {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	if manager.publisherChannel == nil {
+		return nil, errors.BrokerUnavailableError{}
+	}
+
+	return manager.publisherChannel, nil
+}
+
+// PublishMandatory publishes a message that must be routed to a queue and waits for the broker to confirm
+// it. Fails if the broker does not acknowledge the message or returns it because it could not be routed
+func (manager *RabbitMQConnectionManager) PublishMandatory(
+	ctx context.Context,
+	exchange string,
+	routingKey string,
+	msg amqp.Publishing,
+) error {
+	manager.publishMutex.Lock()
+	defer manager.publishMutex.Unlock()
+
+	manager.mutex.RLock()
+	channel := manager.publisherChannel
+	returns := manager.publisherReturns
+	manager.mutex.RUnlock()
+
+	if channel == nil {
+		return errors.BrokerUnavailableError{}
+	}
+
+	// Discard the returns of the messages whose publication already failed
+	for hasReturns := true; hasReturns; {
+		select {
+		case returned, ok := <-returns:
+			hasReturns = ok
+			if ok {
+				logReturnedRabbitMQMessage(returned)
+			}
+		default:
+			hasReturns = false
+		}
+	}
+
+	// Identify the message to tell if it is the one returned by the broker
+	manager.publishedMessages++
+	msg.MessageId = strconv.FormatUint(manager.publishedMessages, 10)
+
+	mandatory := true
+	immediate := false
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		exchange,
+		routingKey,
+		mandatory,
+		immediate,
+		msg,
+	)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
-	// Get channel
-	ch, err := conn.Channel()
+	acked, err := confirmation.WaitContext(ctx)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
+	}
+
+	if !acked {
+		return fmt.Errorf("the message published to %q with the routing key %q was not acknowledged", exchange, routingKey)
 	}
 
-	// Set channel
-	log.Println("Connected to RabbitMQ")
-	rabbitMQChannel = ch
+	// The broker sends the return of an unroutable message before its acknowledgement and the client
+	// dispatches both in order, so the return is already in the channel once the message is acknowledged
+	for {
+		select {
+		case returned, ok := <-returns:
+			if !ok {
+				return nil
+			}
+
+			logReturnedRabbitMQMessage(returned)
+			if returned.MessageId == msg.MessageId {
+				return fmt.Errorf("the message published to %q with the routing key %q was returned: %s", exchange, routingKey, returned.ReplyText)
+			}
+		default:
+			return nil
+		}
+	}
 }
 
-func CloseRabbitMQConnection() {
-	if rabbitMQChannel != nil {
-		rabbitMQChannel.Close()
+// connect opens a new connection, its channels and declares the topology
+func (manager *RabbitMQConnectionManager) connect() error {
+	conn, err := amqp.Dial(manager.connectionString)
+	if err != nil {
+		return err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	// Get a channel in confirm mode, so the broker acknowledges each published message
+	publisherCh, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	noWait := false
+	if err := publisherCh.Confirm(noWait); err != nil {
+		conn.Close()
+		return err
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if manager.isClosed {
+		conn.Close()
+		return nil
+	}
+
+	for _, topology := range manager.topologies {
+		if err := topology(ch); err != nil {
+			conn.Close()
+			return err
+		}
 	}
+
+	// Messages published as mandatory that could not be routed to any queue are returned
+	publisherReturns := publisherCh.NotifyReturn(make(chan amqp.Return, 16))
+
+	// Watch the connection and its channels
+	connectionCloses := conn.NotifyClose(make(chan *amqp.Error, 1))
+	channelCloses := ch.NotifyClose(make(chan *amqp.Error, 1))
+	publisherChannelCloses := publisherCh.NotifyClose(make(chan *amqp.Error, 1))
+
+	manager.connection = conn
+	manager.channel = ch
+	manager.publisherChannel = publisherCh
+	manager.publisherReturns = publisherReturns
+	close(manager.connected)
+
+	log.Println("[RabbitMQ]: Connected to RabbitMQ")
+
+	go manager.watch(conn, connectionCloses, channelCloses, publisherChannelCloses)
+	return nil
 }
 
-func GetRabbitMQChannel() *amqp.Channel {
-	if rabbitMQChannel == nil {
-		ConnectToRabbitMQ()
+// watch waits for the connection or one of its channels to be closed and reconnects
+func (manager *RabbitMQConnectionManager) watch(
+	conn *amqp.Connection,
+	connectionCloses <-chan *amqp.Error,
+	channelCloses <-chan *amqp.Error,
+	publisherChannelCloses <-chan *amqp.Error,
+) {
+	var closeErr *amqp.Error
+	select {
+	case closeErr = <-connectionCloses:
+	case closeErr = <-channelCloses:
+	case closeErr = <-publisherChannelCloses:
+	}
+
+	// A closed channel can not be reopened, so the whole connection is opened again
+	conn.Close()
+
+	manager.mutex.Lock()
+	manager.connection = nil
+	manager.channel = nil
+	manager.publisherChannel = nil
+	manager.publisherReturns = nil
+	manager.connected = make(chan struct{})
+	isClosed := manager.isClosed
+	manager.mutex.Unlock()
+
+	if isClosed {
+		return
+	}
+
+	if closeErr != nil {
+		log.Println("[RabbitMQ]: The connection was lost:", closeErr.Error())
+	} else {
+		log.Println("[RabbitMQ]: The connection was lost")
 	}
 
-	return rabbitMQChannel
+	manager.reconnect()
 }
 
-func GetRabbitMQSubmissionsQueue() *amqp.Queue {
-	if rabbitMQSubmissionsQueue == nil {
-		ch := GetRabbitMQChannel()
+// reconnect tries to connect again, waiting longer after each failed attempt
+func (manager *RabbitMQConnectionManager) reconnect() {
+	delay := manager.minReconnectDelay
 
-		// Declare queue
-		qName := "submissions"
-		qDurable := true
-		qAutoDelete := false
-		qExclusive := false
-		qNoWait := false
-		qArgs := amqp.Table{}
+	for {
+		time.Sleep(delay)
 
-		q, err := ch.QueueDeclare(
-			qName,
-			qDurable,
-			qAutoDelete,
-			qExclusive,
-			qNoWait,
-			qArgs,
-		)
+		manager.mutex.RLock()
+		isClosed := manager.isClosed
+		manager.mutex.RUnlock()
 
-		if err != nil {
-			log.Fatal(err.Error())
+		if isClosed {
+			return
 		}
 
-		// Set fair dispatch
-		maxPrefetchCount := 4 // Limit to 4 submissions per worker
-		err = ch.Qos(
-			maxPrefetchCount,
-			0,
-			false,
-		)
+		err := manager.connect()
+		if err == nil {
+			return
+		}
 
-		if err != nil {
-			log.Fatal(err.Error())
+		delay *= 2
+		if delay > manager.maxReconnectDelay {
+			delay = manager.maxReconnectDelay
 		}
 
-		// Set queue
-		log.Println("RabbitMQ submissions queue declared / set")
-		rabbitMQSubmissionsQueue = &q
+		log.Printf("[RabbitMQ]: Unable to reconnect, retrying in %s: %s", delay, err.Error())
+	}
+}
+
+// declareRabbitMQSubmissionsQueue declares the durable queue the submission works are published to.
+// It is declared without arguments, as the tests runners do, so the existing queues are still valid. The
+// works rejected by the runners or expired are sent to the dead letter exchange by a policy of the broker
+func declareRabbitMQSubmissionsQueue(ch *amqp.Channel) error {
+	qName := RabbitMQSubmissionsQueueName
+	qDurable := true
+	qAutoDelete := false
+	qExclusive := false
+	qNoWait := false
+	qArgs := amqp.Table{}
+
+	_, err := ch.QueueDeclare(
+		qName,
+		qDurable,
+		qAutoDelete,
+		qExclusive,
+		qNoWait,
+		qArgs,
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Println("[RabbitMQ]: Submissions queue declared")
+	return nil
+}
+
+// declareRabbitMQSubmissionsDeadLetterQueue declares the exchange and the durable queue that receive
+// the submission works that could not be processed
+func declareRabbitMQSubmissionsDeadLetterQueue(ch *amqp.Channel) error {
+	// Declare the exchange
+	exName := RabbitMQSubmissionsDeadLetterExchangeName
+	exKind := "fanout"
+	exDurable := true
+	exAutoDelete := false
+	exInternal := false
+	exNoWait := false
+	exArgs := amqp.Table{}
+
+	err := ch.ExchangeDeclare(
+		exName,
+		exKind,
+		exDurable,
+		exAutoDelete,
+		exInternal,
+		exNoWait,
+		exArgs,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Declare the queue
+	qName := RabbitMQSubmissionsDeadLetterQueueName
+	qDurable := true
+	qAutoDelete := false
+	qExclusive := false
+	qNoWait := false
+	qArgs := amqp.Table{}
+
+	_, err = ch.QueueDeclare(
+		qName,
+		qDurable,
+		qAutoDelete,
+		qExclusive,
+		qNoWait,
+		qArgs,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Bind the queue to the exchange
+	routingKey := ""
+	err = ch.QueueBind(
+		qName,
+		routingKey,
+		exName,
+		qNoWait,
+		amqp.Table{},
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Println("[RabbitMQ]: Submissions dead letter queue declared")
+	return nil
+}
+
+// logReturnedRabbitMQMessage logs a published message that could not be routed to any queue
+func logReturnedRabbitMQMessage(returned amqp.Return) {
+	log.Printf(
+		"[RabbitMQ]: The message published to %q with the routing key %q was returned: %s",
+		returned.Exchange,
+		returned.RoutingKey,
+		returned.ReplyText,
+	)
+}
+
+func ConnectToRabbitMQ() {
+	err := GetRabbitMQConnectionManager().Connect()
+	if err != nil {
+		log.Fatal(err.Error())
 	}
+}
+
+func CloseRabbitMQConnection() {
+	GetRabbitMQConnectionManager().Close()
+}
+
+// GetRabbitMQChannel returns the channel used to declare the topology and to consume messages
+func GetRabbitMQChannel() (*amqp.Channel, error) {
+	return GetRabbitMQConnectionManager().Channel()
+}
+
+// PublishRabbitMQMandatoryMessage publishes a message that must be routed to a queue and waits for the broker
+// to confirm it
+func PublishRabbitMQMandatoryMessage(ctx context.Context, exchange string, routingKey string, msg amqp.Publishing) error {
+	return GetRabbitMQConnectionManager().PublishMandatory(ctx, exchange, routingKey, msg)
+}
 
-	return rabbitMQSubmissionsQueue
+// GetRabbitMQPublisherChannel returns the channel used to publish messages with publisher confirms
+func GetRabbitMQPublisherChannel() (*amqp.Channel, error) {
+	return GetRabbitMQConnectionManager().PublisherChannel()
 }
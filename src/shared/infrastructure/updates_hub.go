@@ -0,0 +1,142 @@
+package infrastructure
+
+import (
+	"log"
+	"sync"
+)
+
+// UpdatesHubSubscription subscription to the updates published under a key. The `Updates` channel
+// is closed when the subscription is cancelled or when the subscriber is dropped for being too slow
+type UpdatesHubSubscription[T any] struct {
+	Key     string
+	Updates chan T
+}
+
+// UpdatesHub fan-out hub to send real time updates to many concurrent subscribers per key
+type UpdatesHub[T any] struct {
+	// Name of the hub, used in the logs
+	name string
+
+	// Number of updates a subscriber can have pending before being considered a slow consumer
+	bufferSize int
+
+	// All the open subscriptions, grouped by key
+	subscriptions map[string]map[*UpdatesHubSubscription[T]]struct{}
+	mutex         sync.RWMutex
+}
+
+// NewUpdatesHub creates a hub without subscriptions
+func NewUpdatesHub[T any](name string, bufferSize int) *UpdatesHub[T] {
+	return &UpdatesHub[T]{
+		name:          name,
+		bufferSize:    bufferSize,
+		subscriptions: make(map[string]map[*UpdatesHubSubscription[T]]struct{}),
+	}
+}
+
+// Subscribe creates a new subscription to the updates published under the given key
+func (hub *UpdatesHub[T]) Subscribe(key string) *UpdatesHubSubscription[T] {
+	subscription := &UpdatesHubSubscription[T]{
+		Key:     key,
+		Updates: make(chan T, hub.bufferSize),
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	keySubscriptions, exists := hub.subscriptions[key]
+	if !exists {
+		keySubscriptions = make(map[*UpdatesHubSubscription[T]]struct{})
+		hub.subscriptions[key] = keySubscriptions
+	}
+	keySubscriptions[subscription] = struct{}{}
+
+	log.Printf(
+		"[%s]: New subscription to %s. Subscriptions to %s: %d",
+		hub.name,
+		key,
+		key,
+		len(keySubscriptions),
+	)
+	return subscription
+}
+
+// Unsubscribe cancels a subscription and closes its channel. Calling it more than once
+// or after the subscriber was dropped has no effect
+func (hub *UpdatesHub[T]) Unsubscribe(subscription *UpdatesHubSubscription[T]) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	hub.removeSubscription(subscription)
+}
+
+// SubscriptionsCount returns the number of open subscriptions to the given key
+func (hub *UpdatesHub[T]) SubscriptionsCount(key string) int {
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+
+	return len(hub.subscriptions[key])
+}
+
+// HasSubscriptions returns true if there is, at least, one open subscription to any key
+func (hub *UpdatesHub[T]) HasSubscriptions() bool {
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+
+	return len(hub.subscriptions) > 0
+}
+
+// Publish sends an update to all the subscribers of the given key without blocking.
+// Subscribers whose buffer is full are dropped
+func (hub *UpdatesHub[T]) Publish(key string, update T) {
+	slowSubscriptions := []*UpdatesHubSubscription[T]{}
+
+	hub.mutex.RLock()
+	for subscription := range hub.subscriptions[key] {
+		select {
+		case subscription.Updates <- update:
+		default:
+			slowSubscriptions = append(slowSubscriptions, subscription)
+		}
+	}
+	hub.mutex.RUnlock()
+
+	if len(slowSubscriptions) == 0 {
+		return
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, subscription := range slowSubscriptions {
+		log.Printf("[%s]: Dropping slow subscriber of %s", hub.name, subscription.Key)
+		hub.removeSubscription(subscription)
+	}
+}
+
+// removeSubscription removes a subscription and closes its channel. The caller must hold the write lock
+func (hub *UpdatesHub[T]) removeSubscription(subscription *UpdatesHubSubscription[T]) {
+	keySubscriptions, exists := hub.subscriptions[subscription.Key]
+	if !exists {
+		return
+	}
+
+	if _, isSubscribed := keySubscriptions[subscription]; !isSubscribed {
+		return
+	}
+
+	delete(keySubscriptions, subscription)
+	close(subscription.Updates)
+
+	if len(keySubscriptions) == 0 {
+		delete(hub.subscriptions, subscription.Key)
+	}
+
+	log.Printf(
+		"[%s]: Subscription to %s removed. Subscriptions to %s: %d",
+		hub.name,
+		subscription.Key,
+		subscription.Key,
+		len(keySubscriptions),
+	)
+}
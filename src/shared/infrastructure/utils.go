@@ -1,17 +1,19 @@
 package infrastructure
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"sort"
 	"time"
 
 	sharedDomainErrors "github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
 	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
 )
 
 // ParseRFCEDate parses a date in RFC3339 format
@@ -58,61 +60,115 @@ func ValidateMultipartFileHeader(multipartHeader *multipart.FileHeader) error {
 
 // ParseMicroserviceError parses the error returned by the archives microservice
 func ParseMicroserviceError(resp *http.Response, err error) error {
-	statusStr := fmt.Sprintf("%d", resp.StatusCode)
-	isInTwoHundredsGroup := statusStr[0] == '2'
-
-	if err != nil || !isInTwoHundredsGroup {
-		defaultErrorMessage := "There was an error while requesting the archives microservice"
-		errorMessage := defaultErrorMessage
-
-		// Decode the body
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return &sharedDomainErrors.GenericDomainError{
-				Code:    http.StatusBadRequest,
-				Message: defaultErrorMessage,
-			}
+	// The microservice was not reachable, so there is no response
+	if err != nil {
+		var domainError sharedDomainErrors.DomainError
+		if errors.As(err, &domainError) {
+			return domainError
 		}
 
-		// Parse the JSON
-		var responseJSON map[string]interface{}
-		err = json.Unmarshal(body, &responseJSON)
-		if err != nil {
-			return &sharedDomainErrors.GenericDomainError{
-				Code:    http.StatusBadRequest,
-				Message: defaultErrorMessage,
-			}
-		}
+		return sharedDomainErrors.MicroserviceUnavailableError{}
+	}
+
+	isInTwoHundredsGroup := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if isInTwoHundredsGroup {
+		return nil
+	}
+
+	defer resp.Body.Close()
 
-		// Get the error message
-		msg, ok := responseJSON["message"].(string)
-		if ok {
-			errorMessage = msg
+	// Internal errors of the microservice are not forwarded to the client
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return sharedDomainErrors.MicroserviceBadGatewayError{}
+	}
+
+	defaultErrorMessage := "There was an error while requesting the archives microservice"
+	errorMessage := defaultErrorMessage
+
+	// Decode the body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &sharedDomainErrors.GenericDomainError{
+			Code:    http.StatusBadRequest,
+			Message: defaultErrorMessage,
 		}
+	}
 
-		// Return the error
+	// Parse the JSON
+	var responseJSON map[string]interface{}
+	err = json.Unmarshal(body, &responseJSON)
+	if err != nil {
 		return &sharedDomainErrors.GenericDomainError{
-			Code:    resp.StatusCode,
-			Message: errorMessage,
+			Code:    http.StatusBadRequest,
+			Message: defaultErrorMessage,
 		}
 	}
 
-	return nil
+	// Get the error message
+	msg, ok := responseJSON["message"].(string)
+	if ok {
+		errorMessage = msg
+	}
+
+	// Return the error
+	return &sharedDomainErrors.GenericDomainError{
+		Code:    resp.StatusCode,
+		Message: errorMessage,
+	}
 }
 
-type baseMultipartFormBuffer struct {
-	BodyBuffer       *bytes.Buffer
-	BodyBufferWriter *multipart.Writer
+// MultipartFormStream multipart form that is written while it is being sent
+type MultipartFormStream struct {
+	Body        io.ReadCloser
+	ContentType string
 }
 
-func GetMultipartFormBufferFromFile(file *multipart.File) (*baseMultipartFormBuffer, error) {
+// GetMultipartFormStreamFromFile returns a multipart form with the given file and fields. The form is written
+// through a pipe as the request body is read, so the file is never fully loaded in memory
+func GetMultipartFormStreamFromFile(file *multipart.File, fields map[string]string) (*MultipartFormStream, error) {
 	FILE_NAME := "archive.zip"
 	FILE_CONTENT_TYPE := "application/zip"
 
-	// Create multipart writer
-	var bodyBuffer bytes.Buffer
-	multipartWriter := multipart.NewWriter(&bodyBuffer)
+	// Reset the file pointer to the beginning
+	_, err := (*file).Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	multipartWriter := multipart.NewWriter(pipeWriter)
+
+	go func() {
+		// The reader gets the error, if any, when it reads the body
+		pipeWriter.CloseWithError(writeMultipartForm(multipartWriter, file, fields, FILE_NAME, FILE_CONTENT_TYPE))
+	}()
+
+	return &MultipartFormStream{
+		Body:        pipeReader,
+		ContentType: multipartWriter.FormDataContentType(),
+	}, nil
+}
+
+// writeMultipartForm writes the fields and the file of a multipart form
+func writeMultipartForm(
+	multipartWriter *multipart.Writer,
+	file *multipart.File,
+	fields map[string]string,
+	fileName string,
+	fileContentType string,
+) error {
+	// Add the fields, sorted so the form is always the same
+	fieldNames := make([]string, 0, len(fields))
+	for name := range fields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+
+	for _, name := range fieldNames {
+		if err := multipartWriter.WriteField(name, fields[name]); err != nil {
+			return err
+		}
+	}
 
 	// Add the file field to the request
 	header := textproto.MIMEHeader{}
@@ -120,29 +176,32 @@ func GetMultipartFormBufferFromFile(file *multipart.File) (*baseMultipartFormBuf
 		fmt.Sprintf(
 			`form-data; name="%s"; filename="%s"`,
 			"file",
-			FILE_NAME,
+			fileName,
 		),
 	)
-	header.Set("Content-Type", FILE_CONTENT_TYPE)
-
-	// Reset the file pointer to the beginning
-	_, err := (*file).Seek(0, io.SeekStart)
-	if err != nil {
-		return nil, err
-	}
+	header.Set("Content-Type", fileContentType)
 
-	// Add the file to the request
 	fileWriter, err := multipartWriter.CreatePart(header)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if _, err := io.Copy(fileWriter, *file); err != nil {
-		return nil, err
+		return err
 	}
 
-	return &baseMultipartFormBuffer{
-		BodyBuffer:       &bodyBuffer,
-		BodyBufferWriter: multipartWriter,
-	}, nil
+	return multipartWriter.Close()
+}
+
+// SendArchive copies a `zip` archive to the response as it is read, so it is never fully loaded in memory.
+// The content is closed once it was sent
+func SendArchive(c *gin.Context, content io.ReadCloser, contentLength int64, contentDisposition string) {
+	defer content.Close()
+
+	extraHeaders := map[string]string{}
+	if contentDisposition != "" {
+		extraHeaders["Content-Disposition"] = contentDisposition
+	}
+
+	c.DataFromReader(http.StatusOK, contentLength, "application/zip", content, extraHeaders)
 }
@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestGetMultipartFormStreamFromFile(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "archive-*.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString("archive content"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file pointer is at the end, the form must include the whole file anyway
+	var multipartFile multipart.File = file
+	form, err := GetMultipartFormStreamFromFile(&multipartFile, map[string]string{
+		"archive_type": "test",
+		"archive_uuid": "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parse the form as the static files microservice would do
+	request, err := http.NewRequest("POST", "/archives/save", form.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", form.ContentType)
+
+	if err := request.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+
+	if archiveType := request.FormValue("archive_type"); archiveType != "test" {
+		t.Errorf("expected the archive type to be test, got %q", archiveType)
+	}
+
+	uploadedFile, header, err := request.FormFile("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uploadedFile.Close()
+
+	if header.Filename != "archive.zip" || header.Header.Get("Content-Type") != "application/zip" {
+		t.Errorf("unexpected file header: %v", header.Header)
+	}
+
+	content, err := io.ReadAll(uploadedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "archive content" {
+		t.Errorf("expected the content of the file, got %q", content)
+	}
+}
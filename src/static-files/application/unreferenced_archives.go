@@ -0,0 +1,27 @@
+package application
+
+import (
+	"log"
+
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+)
+
+// DeleteUnreferencedArchive deletes an archive that was saved but is not referenced by any row (e.g. the
+// entity that referenced it could not be saved), so it would never be collected. The errors are only logged
+// with the given context, as the caller is already handling the error that left the archive unreferenced
+func DeleteUnreferencedArchive(repository definitions.StaticFilesRepository, archiveUUID, archiveType, context string) {
+	err := repository.DeleteArchive(&dtos.StaticFileArchiveDTO{
+		FileUUID: archiveUUID,
+		FileType: archiveType,
+	})
+	if err != nil {
+		log.Printf(
+			"[%s]: Unable to delete the %s archive %s that is not referenced: %s",
+			context,
+			archiveType,
+			archiveUUID,
+			err.Error(),
+		)
+	}
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"log"
+	"net/http"
+
+	sharedDomainErrors "github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/entities"
+)
+
+type StaticFilesUseCases struct {
+	StaticFilesRepository definitions.StaticFilesRepository
+	ArchivesRepository    definitions.ArchivesRepository
+}
+
+// Types of archives an orphaned archive without a recorded type may have. Language templates are not
+// included, as they are never deleted
+var orphanedArchiveTypes = []string{"test", "submission"}
+
+// CollectOrphanedArchives deletes the archives that are no longer referenced from the static files storage
+// and the database. Archives that could not be deleted are kept for the next collection
+func (useCases *StaticFilesUseCases) CollectOrphanedArchives(dto *dtos.CollectOrphanedArchivesDTO) (*dtos.OrphanedArchivesReportDTO, error) {
+	orphanedArchives, err := useCases.ArchivesRepository.GetOrphanedArchives(dto.GracePeriodSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dtos.OrphanedArchivesReportDTO{
+		DryRun:           dto.DryRun,
+		OrphanedArchives: orphanedArchives,
+		DeletedArchives:  []string{},
+		FailedArchives:   []string{},
+	}
+
+	if dto.DryRun {
+		return report, nil
+	}
+
+	for _, archive := range orphanedArchives {
+		if err := useCases.deleteOrphanedArchive(archive); err != nil {
+			log.Printf(
+				"[Archives collector]: Unable to delete the orphaned archive %s: %s",
+				archive.UUID,
+				err.Error(),
+			)
+
+			report.FailedArchives = append(report.FailedArchives, archive.UUID)
+			continue
+		}
+
+		report.DeletedArchives = append(report.DeletedArchives, archive.UUID)
+	}
+
+	return report, nil
+}
+
+// deleteOrphanedArchive deletes the file of an archive and then its metadata, so a file is never left
+// without a row pointing to it. Files that were already deleted (e.g. along with their test block) are
+// skipped
+func (useCases *StaticFilesUseCases) deleteOrphanedArchive(archive *entities.OrphanedArchive) error {
+	archiveTypes := orphanedArchiveTypes
+	if archive.ArchiveType != nil {
+		archiveTypes = []string{*archive.ArchiveType}
+	}
+
+	for _, archiveType := range archiveTypes {
+		err := useCases.StaticFilesRepository.DeleteArchive(&dtos.StaticFileArchiveDTO{
+			FileUUID: archive.FileUUID,
+			FileType: archiveType,
+		})
+		if err == nil {
+			break
+		}
+
+		if !isArchiveNotFoundError(err) {
+			return err
+		}
+	}
+
+	return useCases.ArchivesRepository.DeleteOrphanedArchive(archive.UUID)
+}
+
+// isArchiveNotFoundError returns true if the storage reported that the archive does not exist
+func isArchiveNotFoundError(err error) bool {
+	domainError, ok := err.(sharedDomainErrors.DomainError)
+	return ok && domainError.StatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+
+	sharedDomainErrors "github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/entities"
+)
+
+// staticFilesRepositoryStub stores the files by `<type>/<uuid>` and fails to delete the ones in `failingFiles`
+type staticFilesRepositoryStub struct {
+	definitions.StaticFilesRepository
+
+	files        map[string]bool
+	failingFiles map[string]bool
+	deleteCalls  []string
+}
+
+func (stub *staticFilesRepositoryStub) DeleteArchive(dto *dtos.StaticFileArchiveDTO) error {
+	key := dto.FileType + "/" + dto.FileUUID
+	stub.deleteCalls = append(stub.deleteCalls, key)
+
+	if stub.failingFiles[dto.FileUUID] {
+		return sharedDomainErrors.MicroserviceUnavailableError{}
+	}
+
+	if !stub.files[key] {
+		return &sharedDomainErrors.GenericDomainError{Code: 404, Message: "Archive not found"}
+	}
+
+	delete(stub.files, key)
+	return nil
+}
+
+type archivesRepositoryStub struct {
+	orphanedArchives []*entities.OrphanedArchive
+	deletedArchives  []string
+}
+
+func (stub *archivesRepositoryStub) GetOrphanedArchives(gracePeriodSeconds int) ([]*entities.OrphanedArchive, error) {
+	return stub.orphanedArchives, nil
+}
+
+func (stub *archivesRepositoryStub) DeleteOrphanedArchive(archiveUUID string) error {
+	stub.deletedArchives = append(stub.deletedArchives, archiveUUID)
+	return nil
+}
+
+func newOrphanedArchivesStubs() (*staticFilesRepositoryStub, *archivesRepositoryStub) {
+	testType := "test"
+
+	staticFilesRepository := &staticFilesRepositoryStub{
+		files: map[string]bool{
+			"test/typed-file":         true,
+			"submission/untyped-file": true,
+		},
+		failingFiles: map[string]bool{"failing-file": true},
+	}
+
+	archivesRepository := &archivesRepositoryStub{
+		orphanedArchives: []*entities.OrphanedArchive{
+			{UUID: "typed", FileUUID: "typed-file", ArchiveType: &testType},
+			{UUID: "untyped", FileUUID: "untyped-file"},
+			{UUID: "already-deleted", FileUUID: "already-deleted-file", ArchiveType: &testType},
+			{UUID: "failing", FileUUID: "failing-file", ArchiveType: &testType},
+		},
+	}
+
+	return staticFilesRepository, archivesRepository
+}
+
+func TestCollectOrphanedArchives(t *testing.T) {
+	staticFilesRepository, archivesRepository := newOrphanedArchivesStubs()
+	useCases := StaticFilesUseCases{
+		StaticFilesRepository: staticFilesRepository,
+		ArchivesRepository:    archivesRepository,
+	}
+
+	report, err := useCases.CollectOrphanedArchives(&dtos.CollectOrphanedArchivesDTO{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Archives whose file was already deleted are collected too
+	expectedDeleted := []string{"typed", "untyped", "already-deleted"}
+	if !reflect.DeepEqual(report.DeletedArchives, expectedDeleted) {
+		t.Errorf("expected %v to be deleted, got %v", expectedDeleted, report.DeletedArchives)
+	}
+
+	if !reflect.DeepEqual(archivesRepository.deletedArchives, expectedDeleted) {
+		t.Errorf("expected the metadata of %v to be deleted, got %v", expectedDeleted, archivesRepository.deletedArchives)
+	}
+
+	// The metadata of the archives whose file could not be deleted is kept
+	if !reflect.DeepEqual(report.FailedArchives, []string{"failing"}) {
+		t.Errorf("expected the failing archive to be reported, got %v", report.FailedArchives)
+	}
+
+	// The type of the archives without one is guessed
+	expectedCalls := []string{
+		"test/typed-file",
+		"test/untyped-file",
+		"submission/untyped-file",
+		"test/already-deleted-file",
+		"test/failing-file",
+	}
+	if !reflect.DeepEqual(staticFilesRepository.deleteCalls, expectedCalls) {
+		t.Errorf("expected the files %v to be deleted, got %v", expectedCalls, staticFilesRepository.deleteCalls)
+	}
+}
+
+func TestCollectOrphanedArchivesDryRun(t *testing.T) {
+	staticFilesRepository, archivesRepository := newOrphanedArchivesStubs()
+	useCases := StaticFilesUseCases{
+		StaticFilesRepository: staticFilesRepository,
+		ArchivesRepository:    archivesRepository,
+	}
+
+	report, err := useCases.CollectOrphanedArchives(&dtos.CollectOrphanedArchivesDTO{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.OrphanedArchives) != 4 {
+		t.Errorf("expected 4 orphaned archives to be reported, got %d", len(report.OrphanedArchives))
+	}
+
+	if len(staticFilesRepository.deleteCalls) > 0 || len(archivesRepository.deletedArchives) > 0 {
+		t.Error("expected nothing to be deleted in a dry run")
+	}
+}
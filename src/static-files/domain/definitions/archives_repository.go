@@ -0,0 +1,13 @@
+package definitions
+
+import "github.com/UPB-Code-Labs/main-api/src/static-files/domain/entities"
+
+// ArchivesRepository metadata of the archives saved in the database
+type ArchivesRepository interface {
+	// GetOrphanedArchives returns the archives that are not referenced by any language, test block or
+	// submission and were created before the grace period
+	GetOrphanedArchives(gracePeriodSeconds int) ([]*entities.OrphanedArchive, error)
+
+	// DeleteOrphanedArchive deletes the metadata of an archive, as long as it is still not referenced
+	DeleteOrphanedArchive(archiveUUID string) error
+}
@@ -0,0 +1,9 @@
+package definitions
+
+import "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+
+// ArchivesValidator inspects the content of the uploaded archives before they are stored
+type ArchivesValidator interface {
+	// ValidateArchive returns an error listing every rule the archive violates, if any
+	ValidateArchive(dto *dtos.ValidateArchiveDTO) error
+}
@@ -6,8 +6,16 @@ type StaticFilesRepository interface {
 	SaveArchive(dto *dtos.SaveStaticFileDTO) (fileUUID string, err error)
 	OverwriteArchive(dto *dtos.OverwriteStaticFileDTO) error
 
+	// DuplicateArchive saves a copy of the archive with a new UUID, so the copies can be edited independently
+	DuplicateArchive(dto *dtos.StaticFileArchiveDTO) (fileUUID string, err error)
+
 	GetArchiveBytes(dto *dtos.StaticFileArchiveDTO) ([]byte, error)
 	GetLanguageTemplateArchiveBytes(languageUUID string) ([]byte, error)
 
+	// GetArchiveStream and GetLanguageTemplateArchiveStream return the content of the archive without loading it
+	// in memory. The caller must close the content
+	GetArchiveStream(dto *dtos.StaticFileArchiveDTO) (*dtos.StaticFileStreamDTO, error)
+	GetLanguageTemplateArchiveStream(languageUUID string) (*dtos.StaticFileStreamDTO, error)
+
 	DeleteArchive(dto *dtos.StaticFileArchiveDTO) error
 }
@@ -1,6 +1,11 @@
 package dtos
 
-import "mime/multipart"
+import (
+	"io"
+	"mime/multipart"
+
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/entities"
+)
 
 type SaveStaticFileDTO struct {
 	FileType string `json:"archive_type"`
@@ -17,3 +22,37 @@ type StaticFileArchiveDTO struct {
 	FileUUID string `json:"archive_uuid"`
 	FileType string `json:"archive_type"`
 }
+
+// StaticFileStreamDTO content of an archive that is read while it is being sent to the client
+type StaticFileStreamDTO struct {
+	Content io.ReadCloser
+
+	// Size of the archive in bytes, -1 if it is unknown
+	ContentLength      int64
+	ContentDisposition string
+}
+
+// CollectOrphanedArchivesDTO parameters used to collect the archives that are no longer referenced
+type CollectOrphanedArchivesDTO struct {
+	// Archives created within this period are not collected, as their references may still be being saved
+	GracePeriodSeconds int
+
+	// Only report the orphaned archives, without deleting them
+	DryRun bool
+}
+
+// OrphanedArchivesReportDTO result of a collection of the orphaned archives
+type OrphanedArchivesReportDTO struct {
+	DryRun           bool                        `json:"dry_run"`
+	OrphanedArchives []*entities.OrphanedArchive `json:"orphaned_archives"`
+
+	// UUIDs of the archives that were deleted or could not be deleted
+	DeletedArchives []string `json:"deleted_archives"`
+	FailedArchives  []string `json:"failed_archives"`
+}
+
+// ValidateArchiveDTO archive uploaded by a user and the paths its project must contain
+type ValidateArchiveDTO struct {
+	File          *multipart.File
+	RequiredPaths []string
+}
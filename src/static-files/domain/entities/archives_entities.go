@@ -0,0 +1,12 @@
+package entities
+
+// OrphanedArchive archive that is no longer referenced by a language, a test block or a submission
+type OrphanedArchive struct {
+	UUID     string `json:"uuid"`
+	FileUUID string `json:"file_uuid"`
+
+	// Type of the archive in the static files storage. Archives saved before the type was recorded
+	// do not have one
+	ArchiveType *string `json:"archive_type"`
+	CreatedAt   string  `json:"created_at"`
+}
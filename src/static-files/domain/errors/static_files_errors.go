@@ -0,0 +1,40 @@
+package errors
+
+import "net/http"
+
+type ArchiveNotFoundError struct{}
+
+func (err ArchiveNotFoundError) Error() string {
+	return "The requested archive was not found"
+}
+
+func (err ArchiveNotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type InvalidArchiveTypeError struct{}
+
+func (err InvalidArchiveTypeError) Error() string {
+	return "The archive type is not valid, it must be `test`, `submission` or `template`"
+}
+
+func (err InvalidArchiveTypeError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// InvalidArchiveContentError the content of an uploaded archive violates one or more rules
+type InvalidArchiveContentError struct {
+	Violations []string
+}
+
+func (err InvalidArchiveContentError) Error() string {
+	return "The archive does not fulfill the requirements, please fix the listed errors and upload it again"
+}
+
+func (err InvalidArchiveContentError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (err InvalidArchiveContentError) Details() []string {
+	return err.Violations
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/application"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	"github.com/gin-gonic/gin"
+)
+
+type StaticFilesController struct {
+	UseCases *application.StaticFilesUseCases
+}
+
+// HandleGetOrphanedArchives controller to get the archives the collector would delete, without deleting them
+func (controller *StaticFilesController) HandleGetOrphanedArchives(c *gin.Context) {
+	report, err := controller.UseCases.CollectOrphanedArchives(&dtos.CollectOrphanedArchivesDTO{
+		GracePeriodSeconds: sharedInfrastructure.GetEnvironment().OrphanedArchivesGracePeriodSeconds,
+		DryRun:             true,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/application"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
+	"github.com/gin-gonic/gin"
+)
+
+func StartStaticFilesRoutes(g *gin.RouterGroup) {
+	archivesGroup := g.Group("/archives")
+
+	useCases := application.StaticFilesUseCases{
+		StaticFilesRepository: implementations.GetStaticFilesRepositoryInstance(),
+		ArchivesRepository:    implementations.GetArchivesPostgresRepositoryInstance(),
+	}
+
+	controller := StaticFilesController{
+		UseCases: &useCases,
+	}
+
+	archivesGroup.GET(
+		"/orphaned",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controller.HandleGetOrphanedArchives,
+	)
+}
@@ -0,0 +1,88 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/entities"
+)
+
+type ArchivesPostgresRepository struct {
+	Connection *sql.DB
+}
+
+// Condition satisfied by the archives that are not referenced by any row
+const orphanedArchiveCondition = `
+	NOT EXISTS ( SELECT 1 FROM languages WHERE template_archive_id = archives.id )
+	AND NOT EXISTS ( SELECT 1 FROM test_blocks WHERE test_archive_id = archives.id )
+	AND NOT EXISTS ( SELECT 1 FROM submissions WHERE archive_id = archives.id )
+`
+
+// Singleton instance
+var archivesPostgresRepositoryInstance *ArchivesPostgresRepository
+var archivesPostgresRepositoryOnce sync.Once
+
+func GetArchivesPostgresRepositoryInstance() *ArchivesPostgresRepository {
+	archivesPostgresRepositoryOnce.Do(func() {
+		archivesPostgresRepositoryInstance = &ArchivesPostgresRepository{
+			Connection: sharedInfrastructure.GetPostgresConnection(),
+		}
+	})
+
+	return archivesPostgresRepositoryInstance
+}
+
+// GetOrphanedArchives returns the archives that are no longer referenced, from the oldest to the newest
+func (repository *ArchivesPostgresRepository) GetOrphanedArchives(gracePeriodSeconds int) (archives []*entities.OrphanedArchive, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, file_id, archive_type, created_at
+		FROM archives
+		WHERE created_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+		AND ` + orphanedArchiveCondition + `
+		ORDER BY created_at
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, gracePeriodSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	archives = []*entities.OrphanedArchive{}
+	for rows.Next() {
+		archive := entities.OrphanedArchive{}
+		archiveType := sql.NullString{}
+
+		if err := rows.Scan(&archive.UUID, &archive.FileUUID, &archiveType, &archive.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		if archiveType.Valid {
+			archive.ArchiveType = &archiveType.String
+		}
+
+		archives = append(archives, &archive)
+	}
+
+	return archives, rows.Err()
+}
+
+// DeleteOrphanedArchive deletes the metadata of an archive. Archives that are referenced again are kept
+func (repository *ArchivesPostgresRepository) DeleteOrphanedArchive(archiveUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		DELETE FROM archives
+		WHERE id = $1
+		AND ` + orphanedArchiveCondition
+
+	_, err := repository.Connection.ExecContext(ctx, query, archiveUUID)
+	return err
+}
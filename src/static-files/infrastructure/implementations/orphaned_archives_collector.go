@@ -0,0 +1,69 @@
+package implementations
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/application"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+)
+
+// OrphanedArchivesCollector periodically deletes the archives that are no longer referenced (e.g. their
+// test block was deleted or the upload failed midway) from the static files storage and the database
+type OrphanedArchivesCollector struct {
+	// Archives created within this period are not collected
+	GracePeriodSeconds int
+
+	// Time between each collection
+	Interval time.Duration
+}
+
+// Singleton instance
+var orphanedArchivesCollectorInstance *OrphanedArchivesCollector
+var orphanedArchivesCollectorOnce sync.Once
+
+func GetOrphanedArchivesCollectorInstance() *OrphanedArchivesCollector {
+	orphanedArchivesCollectorOnce.Do(func() {
+		environment := sharedInfrastructure.GetEnvironment()
+
+		orphanedArchivesCollectorInstance = &OrphanedArchivesCollector{
+			GracePeriodSeconds: environment.OrphanedArchivesGracePeriodSeconds,
+			Interval:           time.Duration(environment.OrphanedArchivesCollectorIntervalSeconds) * time.Second,
+		}
+	})
+
+	return orphanedArchivesCollectorInstance
+}
+
+// Start collects the orphaned archives every interval
+func (collector *OrphanedArchivesCollector) Start() {
+	log.Println("[Archives collector]: Collecting the orphaned archives every", collector.Interval)
+
+	useCases := application.StaticFilesUseCases{
+		StaticFilesRepository: GetStaticFilesRepositoryInstance(),
+		ArchivesRepository:    GetArchivesPostgresRepositoryInstance(),
+	}
+
+	ticker := time.NewTicker(collector.Interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		report, err := useCases.CollectOrphanedArchives(&dtos.CollectOrphanedArchivesDTO{
+			GracePeriodSeconds: collector.GracePeriodSeconds,
+		})
+		if err != nil {
+			log.Println("[Archives collector]: There was an error while collecting the orphaned archives", err.Error())
+			continue
+		}
+
+		if len(report.DeletedArchives) > 0 || len(report.FailedArchives) > 0 {
+			log.Printf(
+				"[Archives collector]: %d orphaned archives were deleted and %d could not be deleted",
+				len(report.DeletedArchives),
+				len(report.FailedArchives),
+			)
+		}
+	}
+}
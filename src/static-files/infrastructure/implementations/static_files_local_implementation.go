@@ -0,0 +1,238 @@
+package implementations
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/errors"
+)
+
+// StaticFilesLocalImplementation stores the archives in a local directory (or a mounted volume) instead of
+// the static files microservice. The archives are separated by type, as the microservice does:
+//
+//	<BasePath>/test/<uuid>.zip
+//	<BasePath>/submission/<uuid>.zip
+//	<BasePath>/template/<uuid>.zip
+type StaticFilesLocalImplementation struct {
+	BasePath string
+}
+
+// Types of archives that can be stored
+var localArchiveTypes = map[string]bool{
+	"test":       true,
+	"submission": true,
+	"template":   true,
+}
+
+// SaveArchive saves a file in the local directory
+func (implementation *StaticFilesLocalImplementation) SaveArchive(dto *dtos.SaveStaticFileDTO) (fileUUID string, err error) {
+	fileUUID, err = newArchiveUUID()
+	if err != nil {
+		return "", err
+	}
+
+	err = implementation.writeArchive(dto.FileType, fileUUID, dto.File)
+	if err != nil {
+		return "", err
+	}
+
+	return fileUUID, nil
+}
+
+// OverwriteArchive overwrites a file in the local directory
+func (implementation *StaticFilesLocalImplementation) OverwriteArchive(dto *dtos.OverwriteStaticFileDTO) error {
+	archivePath, err := implementation.getArchivePath(dto.FileType, dto.FileUUID)
+	if err != nil {
+		return err
+	}
+
+	// Only existing archives can be overwritten
+	if _, err := os.Stat(archivePath); err != nil {
+		if os.IsNotExist(err) {
+			return errors.ArchiveNotFoundError{}
+		}
+
+		return err
+	}
+
+	return implementation.writeArchive(dto.FileType, dto.FileUUID, dto.File)
+}
+
+// DuplicateArchive copies a file of the local directory
+func (implementation *StaticFilesLocalImplementation) DuplicateArchive(dto *dtos.StaticFileArchiveDTO) (fileUUID string, err error) {
+	archivePath, err := implementation.getArchivePath(dto.FileType, dto.FileUUID)
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.ArchiveNotFoundError{}
+		}
+
+		return "", err
+	}
+	defer archive.Close()
+
+	var file multipart.File = archive
+	return implementation.SaveArchive(&dtos.SaveStaticFileDTO{
+		FileType: dto.FileType,
+		File:     &file,
+	})
+}
+
+// GetArchiveBytes reads a file from the local directory
+func (implementation *StaticFilesLocalImplementation) GetArchiveBytes(dto *dtos.StaticFileArchiveDTO) ([]byte, error) {
+	return implementation.readArchive(dto.FileType, dto.FileUUID)
+}
+
+// GetLanguageTemplateArchiveBytes reads a language template file from the local directory
+func (implementation *StaticFilesLocalImplementation) GetLanguageTemplateArchiveBytes(languageUUID string) ([]byte, error) {
+	return implementation.readArchive("template", languageUUID)
+}
+
+// GetArchiveStream opens a file from the local directory
+func (implementation *StaticFilesLocalImplementation) GetArchiveStream(dto *dtos.StaticFileArchiveDTO) (*dtos.StaticFileStreamDTO, error) {
+	return implementation.openArchive(dto.FileType, dto.FileUUID)
+}
+
+// GetLanguageTemplateArchiveStream opens a language template file from the local directory
+func (implementation *StaticFilesLocalImplementation) GetLanguageTemplateArchiveStream(languageUUID string) (*dtos.StaticFileStreamDTO, error) {
+	return implementation.openArchive("template", languageUUID)
+}
+
+// DeleteArchive removes a file from the local directory
+func (implementation *StaticFilesLocalImplementation) DeleteArchive(dto *dtos.StaticFileArchiveDTO) error {
+	archivePath, err := implementation.getArchivePath(dto.FileType, dto.FileUUID)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(archivePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.ArchiveNotFoundError{}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// getArchivePath returns the path of an archive, making sure it can not point outside the base directory
+func (implementation *StaticFilesLocalImplementation) getArchivePath(archiveType string, archiveUUID string) (string, error) {
+	if !localArchiveTypes[archiveType] {
+		return "", errors.InvalidArchiveTypeError{}
+	}
+
+	if err := sharedInfrastructure.GetValidator().Var(archiveUUID, "uuid"); err != nil {
+		return "", errors.ArchiveNotFoundError{}
+	}
+
+	return filepath.Join(implementation.BasePath, archiveType, archiveUUID+".zip"), nil
+}
+
+// readArchive returns the content of an archive
+func (implementation *StaticFilesLocalImplementation) readArchive(archiveType string, archiveUUID string) ([]byte, error) {
+	archivePath, err := implementation.getArchivePath(archiveType, archiveUUID)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	archiveBytes, err := os.ReadFile(archivePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []byte{}, errors.ArchiveNotFoundError{}
+		}
+
+		return []byte{}, err
+	}
+
+	return archiveBytes, nil
+}
+
+// openArchive opens an archive to be read while it is sent to the client
+func (implementation *StaticFilesLocalImplementation) openArchive(archiveType string, archiveUUID string) (*dtos.StaticFileStreamDTO, error) {
+	archivePath, err := implementation.getArchivePath(archiveType, archiveUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.ArchiveNotFoundError{}
+		}
+
+		return nil, err
+	}
+
+	info, err := archive.Stat()
+	if err != nil {
+		archive.Close()
+		return nil, err
+	}
+
+	return &dtos.StaticFileStreamDTO{
+		Content:            archive,
+		ContentLength:      info.Size(),
+		ContentDisposition: getArchiveContentDisposition(archiveUUID),
+	}, nil
+}
+
+// writeArchive writes the content of a file to an archive. The content is written to a temporary file first,
+// so readers never see a partially written archive
+func (implementation *StaticFilesLocalImplementation) writeArchive(archiveType string, archiveUUID string, file *multipart.File) error {
+	archivePath, err := implementation.getArchivePath(archiveType, archiveUUID)
+	if err != nil {
+		return err
+	}
+
+	archiveDirectory := filepath.Dir(archivePath)
+	if err := os.MkdirAll(archiveDirectory, 0o750); err != nil {
+		return err
+	}
+
+	// Reset the file pointer to the beginning
+	if _, err := (*file).Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	temporaryFile, err := os.CreateTemp(archiveDirectory, archiveUUID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporaryFile.Name())
+
+	if _, err := io.Copy(temporaryFile, *file); err != nil {
+		temporaryFile.Close()
+		return err
+	}
+
+	if err := temporaryFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFile.Name(), archivePath)
+}
+
+// newArchiveUUID generates a random (version 4) UUID for a new archive
+func newArchiveUUID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, bytes); err != nil {
+		return "", err
+	}
+
+	bytes[6] = (bytes[6] & 0x0f) | 0x40 // Version 4
+	bytes[8] = (bytes[8] & 0x3f) | 0x80 // Variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bytes[0:4], bytes[4:6], bytes[6:8], bytes[8:10], bytes[10:16]), nil
+}
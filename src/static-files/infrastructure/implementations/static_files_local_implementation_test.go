@@ -0,0 +1,156 @@
+package implementations
+
+import (
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/errors"
+)
+
+func createTestFile(t *testing.T, content string) *multipart.File {
+	t.Helper()
+
+	file, err := os.CreateTemp(t.TempDir(), "archive-*.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+
+	var multipartFile multipart.File = file
+	return &multipartFile
+}
+
+func TestLocalArchivesLifecycle(t *testing.T) {
+	basePath := t.TempDir()
+	implementation := &StaticFilesLocalImplementation{BasePath: basePath}
+
+	// Save
+	archiveUUID, err := implementation.SaveArchive(&dtos.SaveStaticFileDTO{
+		FileType: "test",
+		File:     createTestFile(t, "first version"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(basePath, "test", archiveUUID+".zip")); err != nil {
+		t.Fatalf("expected the archive to be stored in the test directory: %v", err)
+	}
+
+	// Archives of other types are not mixed
+	_, err = implementation.GetArchiveBytes(&dtos.StaticFileArchiveDTO{FileUUID: archiveUUID, FileType: "submission"})
+	if err != (errors.ArchiveNotFoundError{}) {
+		t.Errorf("expected an ArchiveNotFoundError, got %v", err)
+	}
+
+	// Overwrite
+	err = implementation.OverwriteArchive(&dtos.OverwriteStaticFileDTO{
+		FileUUID: archiveUUID,
+		FileType: "test",
+		File:     createTestFile(t, "second version"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := implementation.GetArchiveBytes(&dtos.StaticFileArchiveDTO{FileUUID: archiveUUID, FileType: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "second version" {
+		t.Errorf("expected the overwritten content, got %q", content)
+	}
+
+	// Delete
+	err = implementation.DeleteArchive(&dtos.StaticFileArchiveDTO{FileUUID: archiveUUID, FileType: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = implementation.GetArchiveBytes(&dtos.StaticFileArchiveDTO{FileUUID: archiveUUID, FileType: "test"})
+	if err != (errors.ArchiveNotFoundError{}) {
+		t.Errorf("expected an ArchiveNotFoundError, got %v", err)
+	}
+}
+
+func TestLocalArchivesRejectInvalidPaths(t *testing.T) {
+	implementation := &StaticFilesLocalImplementation{BasePath: t.TempDir()}
+
+	_, err := implementation.SaveArchive(&dtos.SaveStaticFileDTO{
+		FileType: "../outside",
+		File:     createTestFile(t, "content"),
+	})
+	if err != (errors.InvalidArchiveTypeError{}) {
+		t.Errorf("expected an InvalidArchiveTypeError, got %v", err)
+	}
+
+	_, err = implementation.GetArchiveBytes(&dtos.StaticFileArchiveDTO{FileUUID: "../../etc/passwd", FileType: "test"})
+	if err != (errors.ArchiveNotFoundError{}) {
+		t.Errorf("expected an ArchiveNotFoundError, got %v", err)
+	}
+
+	err = implementation.OverwriteArchive(&dtos.OverwriteStaticFileDTO{
+		FileUUID: "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c",
+		FileType: "submission",
+		File:     createTestFile(t, "content"),
+	})
+	if err != (errors.ArchiveNotFoundError{}) {
+		t.Errorf("expected only existing archives to be overwritten, got %v", err)
+	}
+}
+
+func TestLocalArchivesDuplicate(t *testing.T) {
+	implementation := &StaticFilesLocalImplementation{BasePath: t.TempDir()}
+
+	archiveUUID, err := implementation.SaveArchive(&dtos.SaveStaticFileDTO{
+		FileType: "test",
+		File:     createTestFile(t, "original"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copyUUID, err := implementation.DuplicateArchive(&dtos.StaticFileArchiveDTO{FileUUID: archiveUUID, FileType: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if copyUUID == archiveUUID {
+		t.Fatal("expected the copy to have a new UUID")
+	}
+
+	// Editing the original does not change the copy
+	err = implementation.OverwriteArchive(&dtos.OverwriteStaticFileDTO{
+		FileUUID: archiveUUID,
+		FileType: "test",
+		File:     createTestFile(t, "edited"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := implementation.GetArchiveBytes(&dtos.StaticFileArchiveDTO{FileUUID: copyUUID, FileType: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "original" {
+		t.Errorf("expected the copy to keep the original content, got %q", content)
+	}
+
+	_, err = implementation.DuplicateArchive(&dtos.StaticFileArchiveDTO{
+		FileUUID: "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c",
+		FileType: "test",
+	})
+	if err != (errors.ArchiveNotFoundError{}) {
+		t.Errorf("expected an ArchiveNotFoundError, got %v", err)
+	}
+}
@@ -6,55 +6,49 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"os"
 
 	sharedDomainErrors "github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
 )
 
-type StaticFilesMicroserviceImplementation struct{}
+type StaticFilesMicroserviceImplementation struct {
+	// Base URL of the static files microservice
+	Address string
+
+	// Client shared by all the requests to the microservice
+	Client *sharedInfrastructure.MicroserviceHTTPClient
+}
 
 // SaveArchive saves a file in the static files microservice
 func (implementation *StaticFilesMicroserviceImplementation) SaveArchive(dto *dtos.SaveStaticFileDTO) (fileUUID string, err error) {
-	// Create multipart writer
 	staticFilesEndpoint := fmt.Sprintf(
 		"%s/archives/save",
-		sharedInfrastructure.GetEnvironment().StaticFilesMicroserviceAddress,
+		implementation.Address,
 	)
 
-	baseMultipartBuffer, err := sharedInfrastructure.GetMultipartFormBufferFromFile(dto.File)
-	if err != nil {
-		return "", err
-	}
-
-	bodyBufferWriter := baseMultipartBuffer.BodyBufferWriter
-	bodyBuffer := baseMultipartBuffer.BodyBuffer
-
-	// Add the file type field to the request
-	err = bodyBufferWriter.WriteField("archive_type", dto.FileType)
-	if err != nil {
-		return "", err
-	}
-
-	// Close the writer
-	err = bodyBufferWriter.Close()
+	// Create the multipart form with the file type field
+	multipartForm, err := sharedInfrastructure.GetMultipartFormStreamFromFile(dto.File, map[string]string{
+		"archive_type": dto.FileType,
+	})
 	if err != nil {
 		return "", err
 	}
 
 	// Prepare the request
-	req, err := http.NewRequest("POST", staticFilesEndpoint, bodyBuffer)
-
+	req, err := http.NewRequest("POST", staticFilesEndpoint, multipartForm.Body)
 	if err != nil {
+		multipartForm.Body.Close()
 		return "", err
 	}
 
-	req.Header.Set("Content-Type", bodyBufferWriter.FormDataContentType())
+	req.Header.Set("Content-Type", multipartForm.ContentType)
 
-	// Send the request
-	client := &http.Client{}
-	res, err := client.Do(req)
+	// Send the request, the form is streamed so it can not be sent again
+	res, err := implementation.Client.Do(req)
 
 	// Forward error message if any
 	microserviceError := sharedInfrastructure.ParseMicroserviceError(res, err)
@@ -62,6 +56,8 @@ func (implementation *StaticFilesMicroserviceImplementation) SaveArchive(dto *dt
 		return "", microserviceError
 	}
 
+	defer res.Body.Close()
+
 	// Return the UUID of the saved file
 	var response map[string]interface{}
 	err = json.NewDecoder(res.Body).Decode(&response)
@@ -81,69 +77,103 @@ func (implementation *StaticFilesMicroserviceImplementation) SaveArchive(dto *dt
 
 // OverwriteArchive overwrites a file in the static files microservice
 func (implementation *StaticFilesMicroserviceImplementation) OverwriteArchive(dto *dtos.OverwriteStaticFileDTO) error {
-	// Create multipart writer
 	staticFilesEndpoint := fmt.Sprintf(
 		"%s/archives/overwrite",
-		sharedInfrastructure.GetEnvironment().StaticFilesMicroserviceAddress,
+		implementation.Address,
 	)
 
-	baseMultipartBuffer, err := sharedInfrastructure.GetMultipartFormBufferFromFile(dto.File)
+	// Create the multipart form with the file type and archive uuid fields
+	multipartForm, err := sharedInfrastructure.GetMultipartFormStreamFromFile(dto.File, map[string]string{
+		"archive_type": dto.FileType,
+		"archive_uuid": dto.FileUUID,
+	})
 	if err != nil {
 		return err
 	}
 
-	// Add the file type field to the request
-	err = baseMultipartBuffer.BodyBufferWriter.WriteField("archive_type", dto.FileType)
+	// Prepare the request
+	req, err := http.NewRequest("PUT", staticFilesEndpoint, multipartForm.Body)
 	if err != nil {
+		multipartForm.Body.Close()
 		return err
 	}
 
-	// Add the archive uuid field to the request
-	err = baseMultipartBuffer.BodyBufferWriter.WriteField("archive_uuid", dto.FileUUID)
+	req.Header.Set("Content-Type", multipartForm.ContentType)
+
+	// Send the request, the form is streamed so it can not be sent again
+	res, err := implementation.Client.Do(req)
+
+	// Forward error message if any
+	microserviceError := sharedInfrastructure.ParseMicroserviceError(res, err)
+	if microserviceError != nil {
+		return microserviceError
+	}
+
+	res.Body.Close()
+	return nil
+}
+
+// DuplicateArchive downloads a file from the static files microservice and saves it again. The content is
+// kept in a temporary file, as the microservice expects a form with a seekable file
+func (implementation *StaticFilesMicroserviceImplementation) DuplicateArchive(dto *dtos.StaticFileArchiveDTO) (fileUUID string, err error) {
+	stream, err := implementation.GetArchiveStream(dto)
 	if err != nil {
-		return err
+		return "", err
 	}
+	defer stream.Content.Close()
 
-	// Close the writer
-	err = baseMultipartBuffer.BodyBufferWriter.Close()
+	temporaryFile, err := os.CreateTemp("", "archive-*.zip")
 	if err != nil {
-		return err
+		return "", err
 	}
+	defer os.Remove(temporaryFile.Name())
+	defer temporaryFile.Close()
 
-	// Prepare the request
-	req, err := http.NewRequest("PUT", staticFilesEndpoint, baseMultipartBuffer.BodyBuffer)
+	if _, err := io.Copy(temporaryFile, stream.Content); err != nil {
+		return "", err
+	}
 
+	var file multipart.File = temporaryFile
+	return implementation.SaveArchive(&dtos.SaveStaticFileDTO{
+		FileType: dto.FileType,
+		File:     &file,
+	})
+}
+
+// GetArchiveBytes gets a file from the static files microservice
+func (implementation *StaticFilesMicroserviceImplementation) GetArchiveBytes(dto *dtos.StaticFileArchiveDTO) ([]byte, error) {
+	stream, err := implementation.GetArchiveStream(dto)
 	if err != nil {
-		return err
+		return []byte{}, err
 	}
+	defer stream.Content.Close()
 
-	req.Header.Set("Content-Type", baseMultipartBuffer.BodyBufferWriter.FormDataContentType())
-
-	// Send the request
-	client := &http.Client{}
-	res, err := client.Do(req)
+	return io.ReadAll(stream.Content)
+}
 
-	// Forward error message if any
-	microserviceError := sharedInfrastructure.ParseMicroserviceError(res, err)
-	if microserviceError != nil {
-		return microserviceError
+// GetLanguageTemplateArchiveBytes gets a language template file from the static files microservice
+func (implementation *StaticFilesMicroserviceImplementation) GetLanguageTemplateArchiveBytes(languageUUID string) ([]byte, error) {
+	stream, err := implementation.GetLanguageTemplateArchiveStream(languageUUID)
+	if err != nil {
+		return []byte{}, err
 	}
+	defer stream.Content.Close()
 
-	return nil
+	return io.ReadAll(stream.Content)
 }
 
-// DeleteArchive deletes a file in the static files microservice
-func (implementation *StaticFilesMicroserviceImplementation) GetArchiveBytes(dto *dtos.StaticFileArchiveDTO) ([]byte, error) {
+// GetArchiveStream gets a file from the static files microservice without reading the response
+func (implementation *StaticFilesMicroserviceImplementation) GetArchiveStream(dto *dtos.StaticFileArchiveDTO) (*dtos.StaticFileStreamDTO, error) {
 	// Prepare the request
 	staticFilesEndpoint := fmt.Sprintf(
 		"%s/archives/download",
-		sharedInfrastructure.GetEnvironment().StaticFilesMicroserviceAddress,
+		implementation.Address,
 	)
 
 	// Create request payload from the dto
 	body, err := json.Marshal(dto)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 
 	// Create request
@@ -153,46 +183,20 @@ func (implementation *StaticFilesMicroserviceImplementation) GetArchiveBytes(dto
 		bytes.NewBuffer(body),
 	)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 
 	request.Header.Set("Content-Type", "application/json")
-
-	// Send the request
-	client := &http.Client{}
-	response, err := client.Do(request)
-
-	// Forward error message if any
-	microserviceError := sharedInfrastructure.ParseMicroserviceError(response, err)
-	if microserviceError != nil {
-		return []byte{}, microserviceError
-	}
-
-	defer response.Body.Close()
-
-	// Handle error
-	if response.StatusCode != http.StatusOK {
-		return []byte{}, errors.New(
-			"there was an error while trying to get the archive from the static files microservice",
-		)
-	}
-
-	// Read response
-	responseBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	// Return response
-	return responseBody, nil
+	return implementation.sendArchiveStreamRequest(request, dto.FileUUID)
 }
 
-// GetLanguageTemplateArchiveBytes gets a language template file from the static files microservice
-func (implementation *StaticFilesMicroserviceImplementation) GetLanguageTemplateArchiveBytes(languageUUID string) ([]byte, error) {
+// GetLanguageTemplateArchiveStream gets a language template file from the static files microservice without
+// reading the response
+func (implementation *StaticFilesMicroserviceImplementation) GetLanguageTemplateArchiveStream(languageUUID string) (*dtos.StaticFileStreamDTO, error) {
 	// Prepare the request
 	staticFilesEndpoint := fmt.Sprintf(
 		"%s/templates/%s",
-		sharedInfrastructure.GetEnvironment().StaticFilesMicroserviceAddress,
+		implementation.Address,
 		languageUUID,
 	)
 
@@ -203,36 +207,47 @@ func (implementation *StaticFilesMicroserviceImplementation) GetLanguageTemplate
 		nil,
 	)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 
-	// Send the request
-	client := &http.Client{}
-	response, err := client.Do(request)
+	return implementation.sendArchiveStreamRequest(request, languageUUID)
+}
+
+// sendArchiveStreamRequest sends a request to download an archive and returns its body, along with the
+// headers to forward to the client
+func (implementation *StaticFilesMicroserviceImplementation) sendArchiveStreamRequest(request *http.Request, archiveUUID string) (*dtos.StaticFileStreamDTO, error) {
+	// Send the request, downloads are retried if the microservice is not available
+	response, err := implementation.Client.DoIdempotent(request)
 
 	// Forward error message if any
 	microserviceError := sharedInfrastructure.ParseMicroserviceError(response, err)
 	if microserviceError != nil {
-		return []byte{}, microserviceError
+		return nil, microserviceError
 	}
 
-	defer response.Body.Close()
-
 	// Handle error
 	if response.StatusCode != http.StatusOK {
-		return []byte{}, errors.New(
+		response.Body.Close()
+		return nil, errors.New(
 			"there was an error while trying to get the archive from the static files microservice",
 		)
 	}
 
-	// Read response
-	responseBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return []byte{}, err
+	contentDisposition := response.Header.Get("Content-Disposition")
+	if contentDisposition == "" {
+		contentDisposition = getArchiveContentDisposition(archiveUUID)
 	}
 
-	// Return response
-	return responseBody, nil
+	return &dtos.StaticFileStreamDTO{
+		Content:            response.Body,
+		ContentLength:      response.ContentLength,
+		ContentDisposition: contentDisposition,
+	}, nil
+}
+
+// getArchiveContentDisposition returns the `Content-Disposition` header used to download an archive
+func getArchiveContentDisposition(archiveUUID string) string {
+	return fmt.Sprintf(`attachment; filename="%s.zip"`, archiveUUID)
 }
 
 // DeleteArchive deletes a file in the static files microservice
@@ -240,7 +255,7 @@ func (implementation *StaticFilesMicroserviceImplementation) DeleteArchive(dto *
 	// Prepare the request
 	staticFilesEndpoint := fmt.Sprintf(
 		"%s/archives/delete",
-		sharedInfrastructure.GetEnvironment().StaticFilesMicroserviceAddress,
+		implementation.Address,
 	)
 
 	// Create request payload from the dto
@@ -261,9 +276,8 @@ func (implementation *StaticFilesMicroserviceImplementation) DeleteArchive(dto *
 
 	request.Header.Set("Content-Type", "application/json")
 
-	// Send the request
-	client := &http.Client{}
-	response, err := client.Do(request)
+	// Send the request. It is not retried, as the archive may have been deleted by the failed attempt
+	response, err := implementation.Client.Do(request)
 
 	// Forward error message if any
 	microserviceError := sharedInfrastructure.ParseMicroserviceError(response, err)
@@ -0,0 +1,134 @@
+package implementations
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sharedErrors "github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+)
+
+func newTestMicroserviceImplementation(t *testing.T, handler http.HandlerFunc) *StaticFilesMicroserviceImplementation {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &StaticFilesMicroserviceImplementation{
+		Address: server.URL,
+		Client: sharedInfrastructure.NewMicroserviceHTTPClient(sharedInfrastructure.MicroserviceHTTPClientConfig{
+			Timeout:          200 * time.Millisecond,
+			MaxRetries:       1,
+			RetryDelay:       10 * time.Millisecond,
+			FailureThreshold: 5,
+			OpenDuration:     time.Second,
+		}),
+	}
+}
+
+func TestMicroserviceArchiveStreamForwardsHeaders(t *testing.T) {
+	implementation := newTestMicroserviceImplementation(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Disposition", `attachment; filename="tests.zip"`)
+		w.Header().Set("Content-Length", "7")
+		w.Write([]byte("archive"))
+	})
+
+	stream, err := implementation.GetArchiveStream(&dtos.StaticFileArchiveDTO{
+		FileUUID: "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c",
+		FileType: "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Content.Close()
+
+	if stream.ContentLength != 7 || stream.ContentDisposition != `attachment; filename="tests.zip"` {
+		t.Errorf("expected the headers of the microservice, got %d and %q", stream.ContentLength, stream.ContentDisposition)
+	}
+
+	content, err := io.ReadAll(stream.Content)
+	if err != nil || string(content) != "archive" {
+		t.Errorf("expected the content of the archive, got %q and %v", content, err)
+	}
+}
+
+func TestMicroserviceFailuresAreMappedToDomainErrors(t *testing.T) {
+	// Failing responses
+	implementation := newTestMicroserviceImplementation(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := implementation.GetArchiveBytes(&dtos.StaticFileArchiveDTO{
+		FileUUID: "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c",
+		FileType: "test",
+	})
+	if err != (sharedErrors.MicroserviceBadGatewayError{}) {
+		t.Errorf("expected a MicroserviceBadGatewayError, got %v", err)
+	}
+
+	// Slow responses
+	implementation = newTestMicroserviceImplementation(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	_, err = implementation.GetLanguageTemplateArchiveBytes("6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c")
+	if err != (sharedErrors.MicroserviceUnavailableError{}) {
+		t.Errorf("expected a MicroserviceUnavailableError, got %v", err)
+	}
+
+	// Service down
+	implementation = &StaticFilesMicroserviceImplementation{
+		Address: "http://127.0.0.1:1",
+		Client:  implementation.Client,
+	}
+
+	err = implementation.DeleteArchive(&dtos.StaticFileArchiveDTO{
+		FileUUID: "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c",
+		FileType: "test",
+	})
+	if err != (sharedErrors.MicroserviceUnavailableError{}) {
+		t.Errorf("expected a MicroserviceUnavailableError, got %v", err)
+	}
+}
+
+func TestMicroserviceDuplicateArchiveSavesTheDownloadedContent(t *testing.T) {
+	implementation := newTestMicroserviceImplementation(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/archives/download":
+			w.Write([]byte("archive"))
+		case "/archives/save":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("expected the archive in the form: %v", err)
+				return
+			}
+
+			content, _ := io.ReadAll(file)
+			if string(content) != "archive" || r.FormValue("archive_type") != "test" {
+				t.Errorf("expected the downloaded test archive to be saved, got %q", content)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"uuid": "0f5d8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	copyUUID, err := implementation.DuplicateArchive(&dtos.StaticFileArchiveDTO{
+		FileUUID: "6b1c8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c",
+		FileType: "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if copyUUID != "0f5d8a3e-5d2f-4e7a-9c0b-3f8d2e1a4b5c" {
+		t.Errorf("expected the UUID of the saved copy, got %q", copyUUID)
+	}
+}
@@ -0,0 +1,41 @@
+package implementations
+
+import (
+	"log"
+	"sync"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
+)
+
+// Singleton instance
+var staticFilesRepositoryInstance definitions.StaticFilesRepository
+var staticFilesRepositoryOnce sync.Once
+
+// GetStaticFilesRepositoryInstance returns the implementation of the static files repository selected
+// with the `STATIC_FILES_STORAGE` environment variable
+func GetStaticFilesRepositoryInstance() definitions.StaticFilesRepository {
+	staticFilesRepositoryOnce.Do(func() {
+		environment := sharedInfrastructure.GetEnvironment()
+
+		switch environment.StaticFilesStorage {
+		case "microservice":
+			staticFilesRepositoryInstance = &StaticFilesMicroserviceImplementation{
+				Address: environment.StaticFilesMicroserviceAddress,
+				Client:  sharedInfrastructure.GetStaticFilesMicroserviceClient(),
+			}
+		case "local":
+			log.Println("[Static files]: Storing the archives in", environment.StaticFilesLocalPath)
+			staticFilesRepositoryInstance = &StaticFilesLocalImplementation{
+				BasePath: environment.StaticFilesLocalPath,
+			}
+		default:
+			log.Fatalf(
+				"[Static files]: Unknown storage %q, it must be `microservice` or `local`",
+				environment.StaticFilesStorage,
+			)
+		}
+	})
+
+	return staticFilesRepositoryInstance
+}
@@ -0,0 +1,219 @@
+package implementations
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/errors"
+)
+
+// ZipArchivesValidator opens the uploaded `zip` archives and checks their entries, so archives that could
+// harm the tests runners (e.g. zip bombs or entries outside the project) are never stored
+type ZipArchivesValidator struct {
+	// Maximum size of all the files once extracted
+	MaxUncompressedSizeKb int64
+
+	// Maximum number of files (directories are not counted)
+	MaxFiles int
+
+	// Extensions of the files that can not be uploaded (e.g. `.exe`)
+	ForbiddenExtensions []string
+}
+
+// Extensions of the archives that can not be nested in an uploaded archive, as their content can not be checked
+var nestedArchivesExtensions = []string{".zip", ".rar", ".7z", ".tar", ".gz", ".tgz", ".bz2", ".xz"}
+
+// Singleton instance
+var zipArchivesValidatorInstance *ZipArchivesValidator
+var zipArchivesValidatorOnce sync.Once
+
+func GetZipArchivesValidatorInstance() *ZipArchivesValidator {
+	zipArchivesValidatorOnce.Do(func() {
+		environment := sharedInfrastructure.GetEnvironment()
+
+		zipArchivesValidatorInstance = &ZipArchivesValidator{
+			MaxUncompressedSizeKb: environment.ArchiveMaxUncompressedSizeKb,
+			MaxFiles:              environment.ArchiveMaxFiles,
+			ForbiddenExtensions:   environment.ArchiveForbiddenExtensions,
+		}
+	})
+
+	return zipArchivesValidatorInstance
+}
+
+// ValidateArchive checks every entry of the archive and returns all the violated rules at once, so the
+// users can fix their archive in a single try
+func (validator *ZipArchivesValidator) ValidateArchive(dto *dtos.ValidateArchiveDTO) error {
+	file := *dto.File
+
+	size, err := getArchiveSize(file)
+	if err != nil {
+		return err
+	}
+
+	reader, err := zip.NewReader(file, size)
+	if err != nil {
+		return errors.InvalidArchiveContentError{
+			Violations: []string{"The archive is not a valid ZIP archive or it is corrupted"},
+		}
+	}
+
+	violations := []string{}
+	entriesNames := []string{}
+
+	var uncompressedSize uint64
+	var filesCount int
+
+	for _, entry := range reader.File {
+		// Windows tools may use backslashes as separators
+		name := strings.ReplaceAll(entry.Name, "\\", "/")
+
+		if !isArchiveEntryPathSafe(name) {
+			violations = append(violations, fmt.Sprintf("The path of `%s` must be relative and stay inside the archive", entry.Name))
+			continue
+		}
+		entriesNames = append(entriesNames, path.Clean(name))
+
+		if entry.Mode()&os.ModeSymlink != 0 {
+			violations = append(violations, fmt.Sprintf("`%s` is a symbolic link, which is not allowed", entry.Name))
+			continue
+		}
+
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+
+		filesCount++
+		uncompressedSize += entry.UncompressedSize64
+
+		extension := strings.ToLower(path.Ext(name))
+		if containsString(nestedArchivesExtensions, extension) {
+			violations = append(violations, fmt.Sprintf("`%s` is a nested archive, which is not allowed", entry.Name))
+		} else if containsString(validator.ForbiddenExtensions, extension) {
+			violations = append(violations, fmt.Sprintf("`%s` has a forbidden extension (%s)", entry.Name, extension))
+		}
+	}
+
+	// The declared sizes can be trusted, as the ZIP readers (including Go's) fail when an entry is bigger
+	// than its declared size
+	if uncompressedSize > uint64(validator.MaxUncompressedSizeKb)*1024 {
+		violations = append(violations, fmt.Sprintf(
+			"The extracted files must be less than %d KB, but they are %d KB",
+			validator.MaxUncompressedSizeKb,
+			uncompressedSize/1024,
+		))
+	}
+
+	if filesCount > validator.MaxFiles {
+		violations = append(violations, fmt.Sprintf(
+			"The archive must contain at most %d files, but it contains %d",
+			validator.MaxFiles,
+			filesCount,
+		))
+	}
+
+	for _, requiredPath := range dto.RequiredPaths {
+		if !archiveContainsPath(entriesNames, requiredPath) {
+			violations = append(violations, fmt.Sprintf("The project must contain the `%s` directory", requiredPath))
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.InvalidArchiveContentError{Violations: violations}
+	}
+
+	return nil
+}
+
+// getArchiveSize returns the size of the archive, leaving the file at the position it was
+func getArchiveSize(file io.Seeker) (int64, error) {
+	currentPosition, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := file.Seek(currentPosition, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// isArchiveEntryPathSafe returns false if the entry would be extracted outside the destination directory
+func isArchiveEntryPathSafe(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") {
+		return false
+	}
+
+	// Windows drive letters (e.g. `C:/`)
+	if len(name) >= 2 && name[1] == ':' {
+		return false
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// archiveContainsPath returns true if the path exists at the root of the archive or inside its only
+// top-level directory, as the projects are usually compressed along with their folder
+func archiveContainsPath(entriesNames []string, requiredPath string) bool {
+	requiredPath = strings.Trim(requiredPath, "/")
+	roots := []string{""}
+
+	if rootDirectory := getArchiveRootDirectory(entriesNames); rootDirectory != "" {
+		roots = append(roots, rootDirectory+"/")
+	}
+
+	for _, root := range roots {
+		for _, name := range entriesNames {
+			if name == root+requiredPath || strings.HasPrefix(name, root+requiredPath+"/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// getArchiveRootDirectory returns the directory that contains all the entries, if any
+func getArchiveRootDirectory(entriesNames []string) string {
+	rootDirectory := ""
+
+	for _, name := range entriesNames {
+		topLevelName, _, _ := strings.Cut(name, "/")
+		if rootDirectory == "" {
+			rootDirectory = topLevelName
+		} else if rootDirectory != topLevelName {
+			return ""
+		}
+	}
+
+	return rootDirectory
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package implementations
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/static-files/domain/errors"
+)
+
+// zipTestEntry entry of an archive created for the tests
+type zipTestEntry struct {
+	name    string
+	content string
+	mode    os.FileMode
+}
+
+func createTestZip(t *testing.T, entries []zipTestEntry) string {
+	t.Helper()
+
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		if entry.mode != 0 {
+			header.SetMode(entry.mode)
+		}
+
+		entryWriter, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := entryWriter.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.String()
+}
+
+func newTestZipArchivesValidator() *ZipArchivesValidator {
+	return &ZipArchivesValidator{
+		MaxUncompressedSizeKb: 10,
+		MaxFiles:              4,
+		ForbiddenExtensions:   []string{".exe"},
+	}
+}
+
+func TestValidateArchiveAcceptsProjects(t *testing.T) {
+	validator := newTestZipArchivesValidator()
+
+	// The required paths can be inside the top-level directory of the project
+	archive := createTestZip(t, []zipTestEntry{
+		{name: "calculator/"},
+		{name: "calculator/pom.xml", content: "<project></project>"},
+		{name: "calculator/src/main/java/Calculator.java", content: "class Calculator {}"},
+	})
+
+	err := validator.ValidateArchive(&dtos.ValidateArchiveDTO{
+		File:          createTestFile(t, archive),
+		RequiredPaths: []string{"src/main/java"},
+	})
+	if err != nil {
+		t.Errorf("expected the archive to be valid, got %v", err)
+	}
+}
+
+func TestValidateArchiveListsEveryViolation(t *testing.T) {
+	validator := newTestZipArchivesValidator()
+
+	archive := createTestZip(t, []zipTestEntry{
+		{name: "../outside.txt", content: "outside"},
+		{name: "/etc/passwd", content: "root"},
+		{name: "link", content: "/etc/passwd", mode: os.ModeSymlink | 0o777},
+		{name: "libs/dependencies.zip", content: "nested"},
+		{name: "run.EXE", content: "binary"},
+		{name: "big.txt", content: strings.Repeat("a", 11*1024)},
+		{name: "other.txt", content: "other"},
+		{name: "another.txt", content: "another"},
+	})
+
+	err := validator.ValidateArchive(&dtos.ValidateArchiveDTO{
+		File:          createTestFile(t, archive),
+		RequiredPaths: []string{"src/main/java"},
+	})
+
+	validationErr, ok := err.(errors.InvalidArchiveContentError)
+	if !ok {
+		t.Fatalf("expected an InvalidArchiveContentError, got %v", err)
+	}
+
+	expectedViolations := []string{
+		"The path of `../outside.txt` must be relative and stay inside the archive",
+		"The path of `/etc/passwd` must be relative and stay inside the archive",
+		"`link` is a symbolic link, which is not allowed",
+		"`libs/dependencies.zip` is a nested archive, which is not allowed",
+		"`run.EXE` has a forbidden extension (.exe)",
+		"The extracted files must be less than 10 KB, but they are 11 KB",
+		"The archive must contain at most 4 files, but it contains 5",
+		"The project must contain the `src/main/java` directory",
+	}
+	if !reflect.DeepEqual(validationErr.Details(), expectedViolations) {
+		t.Errorf("expected the violations %q, got %q", expectedViolations, validationErr.Details())
+	}
+}
+
+func TestValidateArchiveRejectsCorruptedArchives(t *testing.T) {
+	validator := newTestZipArchivesValidator()
+
+	err := validator.ValidateArchive(&dtos.ValidateArchiveDTO{
+		File: createTestFile(t, "not a zip archive"),
+	})
+
+	if _, ok := err.(errors.InvalidArchiveContentError); !ok {
+		t.Errorf("expected an InvalidArchiveContentError, got %v", err)
+	}
+}
@@ -1,11 +1,17 @@
 package application
 
 import (
-	"mime/multipart"
+	"encoding/json"
+	"log"
 	"time"
 
 	blocksDefinitions "github.com/UPB-Code-Labs/main-api/src/blocks/domain/definitions"
+	blocksErrors "github.com/UPB-Code-Labs/main-api/src/blocks/domain/errors"
 	laboratoriesDefinitions "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/definitions"
+	laboratoriesEntities "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/entities"
+	laboratoriesErrors "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/errors"
+	languagesDefinitions "github.com/UPB-Code-Labs/main-api/src/languages/domain/definitions"
+	staticFilesApplication "github.com/UPB-Code-Labs/main-api/src/static-files/application"
 	staticFilesDefinitions "github.com/UPB-Code-Labs/main-api/src/static-files/domain/definitions"
 	staticFilesDTOs "github.com/UPB-Code-Labs/main-api/src/static-files/domain/dtos"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/definitions"
@@ -20,6 +26,9 @@ type SubmissionUseCases struct {
 	BlocksRepository        blocksDefinitions.BlockRepository
 	SubmissionsRepository   definitions.SubmissionsRepository
 	SubmissionsQueueManager definitions.SubmissionsQueueManager
+	SubmissionsOutboxRelay  definitions.SubmissionsOutboxRelay
+	LanguagesRepository     languagesDefinitions.LanguagesRepository
+	ArchivesValidator       staticFilesDefinitions.ArchivesValidator
 }
 
 func (useCases *SubmissionUseCases) CanStudentSubmitToTestBlock(studentUUID string, testBlockUUID string) (bool, error) {
@@ -37,15 +46,13 @@ func (useCases *SubmissionUseCases) SaveSubmission(dto *dtos.CreateSubmissionDTO
 		return "", errors.StudentCannotSubmitToTestBlock{}
 	}
 
-	// Validate the laboratory is open
-	isLaboratoryOpen, err := useCases.isTestBlockLaboratoryOpen(dto.TestBlockUUID)
+	// Validate the laboratory accepts the submission, late submissions are flagged with their lateness
+	lateMinutes, err := useCases.getSubmissionLateMinutes(dto.TestBlockUUID, dto.StudentUUID)
 	if err != nil {
 		return "", err
 	}
 
-	if !isLaboratoryOpen {
-		return "", errors.LaboratoryIsClosed{}
-	}
+	dto.LateMinutes = lateMinutes
 
 	// Check if the student already has a submission for the given test block
 	previousStudentSubmission, err := useCases.SubmissionsRepository.GetStudentSubmission(dto.StudentUUID, dto.TestBlockUUID)
@@ -64,94 +71,97 @@ func (useCases *SubmissionUseCases) SaveSubmission(dto *dtos.CreateSubmissionDTO
 			return "", errors.StudentHasRecentSubmission{}
 		}
 
-		// Check if the previous submission is still pending
-		finalStatus := "ready"
-		if previousStudentSubmission.Status != finalStatus {
+		// Check if the previous submission is still pending. Timed out submissions will not be evaluated, so
+		// the student can submit again
+		isPreviousSubmissionFinished := previousStudentSubmission.Status == "ready" ||
+			previousStudentSubmission.Status == "timed_out"
+		if !isPreviousSubmissionFinished {
 			return "", errors.StudentHasPendingSubmission{}
 		}
+	}
 
-		// If the student already has a submission, reset its status and overwrite the archive
-		err = useCases.resetSubmissionStatus(previousStudentSubmission, dto.SubmissionArchive)
-		if err != nil {
-			return "", err
-		}
+	// Check the content of the archive, according to the language of the test block
+	err = useCases.validateSubmissionArchive(dto)
+	if err != nil {
+		return "", err
+	}
 
-		// Submit the work to the submissions queue
-		err = useCases.submitWorkToQueue(previousStudentSubmission.UUID)
-		if err != nil {
-			return "", err
-		}
+	// Create a new submission (attempt), previous submissions are kept as part of the student's history.
+	// The work is saved in the outbox along with the submission
+	submissionUUID, err := useCases.createSubmission(dto)
+	if err != nil {
+		return "", err
+	}
 
-		return previousStudentSubmission.UUID, nil
-	} else {
-		// If the student doesn't have a submission, create a new one
-		submissionUUID, err := useCases.createSubmission(dto)
-		if err != nil {
-			return "", err
-		}
+	// Publish the work to the submissions queue as soon as possible
+	useCases.SubmissionsOutboxRelay.Wake()
 
-		// Submit the work to the submissions queue
-		err = useCases.submitWorkToQueue(submissionUUID)
-		if err != nil {
-			return "", err
-		}
+	return submissionUUID, nil
+}
 
-		return submissionUUID, nil
+func (useCases *SubmissionUseCases) validateSubmissionArchive(dto *dtos.CreateSubmissionDTO) error {
+	testBlock, err := useCases.BlocksRepository.GetTestBlockByUUID(dto.TestBlockUUID)
+	if err != nil {
+		return err
 	}
+
+	language, err := useCases.LanguagesRepository.GetByUUID(testBlock.LanguageUUID)
+	if err != nil {
+		return err
+	}
+
+	return useCases.ArchivesValidator.ValidateArchive(&staticFilesDTOs.ValidateArchiveDTO{
+		File:          dto.SubmissionArchive,
+		RequiredPaths: language.RequiredPaths,
+	})
 }
 
-func (useCases *SubmissionUseCases) isTestBlockLaboratoryOpen(testBlockUUID string) (bool, error) {
+// getSubmissionLateMinutes checks the laboratory accepts submissions from the student, according to the dates
+// they were granted, if any, and the late policy of the laboratory. Returns the minutes the submission is late
+func (useCases *SubmissionUseCases) getSubmissionLateMinutes(testBlockUUID string, studentUUID string) (int, error) {
 	// Get the UUID of the laboratory the test block belongs to
 	laboratoryUUID, err := useCases.BlocksRepository.GetTestBlockLaboratoryUUID(testBlockUUID)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
 	// Get the laboratory
 	laboratory, err := useCases.LaboratoriesRepository.GetLaboratoryInformationByUUID(laboratoryUUID)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
-	// Check if the laboratory is open
-	parsedClosingDate, err := time.Parse(time.RFC3339, laboratory.DueDate)
+	// Apply the dates granted to the student, if any
+	override, err := useCases.LaboratoriesRepository.GetStudentDateOverride(laboratoryUUID, studentUUID)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
-	if time.Now().After(parsedClosingDate) {
-		return false, nil
-	}
+	openingDate, dueDate := override.Apply(laboratory.OpeningDate, laboratory.DueDate)
 
-	return true, nil
-}
-
-func (useCases *SubmissionUseCases) resetSubmissionStatus(previousStudentSubmission *entities.Submission, newArchive *multipart.File) error {
-	// Get the UUID of the .zip archive in the static files microservice
-	archiveUUID, err := useCases.SubmissionsRepository.GetStudentSubmissionArchiveUUIDFromSubmissionUUID(previousStudentSubmission.UUID)
+	// Check if the laboratory is open
+	parsedOpeningDate, err := time.Parse(time.RFC3339, openingDate)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// Overwrite the archive in the static files microservice
-	err = useCases.StaticFilesRepository.OverwriteArchive(
-		&staticFilesDTOs.OverwriteStaticFileDTO{
-			FileUUID: archiveUUID,
-			FileType: "submission",
-			File:     newArchive,
-		},
-	)
+	parsedClosingDate, err := time.Parse(time.RFC3339, dueDate)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// Reset the submission status
-	err = useCases.SubmissionsRepository.ResetSubmissionStatus(previousStudentSubmission.UUID)
-	if err != nil {
-		return err
+	now := time.Now()
+	if now.Before(parsedOpeningDate) {
+		return 0, errors.LaboratoryIsNotOpen{}
 	}
 
-	return nil
+	// Late submissions are only accepted during the grace period or the late window
+	lateMinutes := laboratoriesEntities.GetLateMinutes(parsedClosingDate, now)
+	if !laboratory.LatePolicy.AcceptsSubmission(lateMinutes) {
+		return 0, errors.LaboratoryIsClosed{}
+	}
+
+	return lateMinutes, nil
 }
 
 func (useCases *SubmissionUseCases) createSubmission(dto *dtos.CreateSubmissionDTO) (string, error) {
@@ -171,28 +181,14 @@ func (useCases *SubmissionUseCases) createSubmission(dto *dtos.CreateSubmissionD
 	// Save the submission
 	submissionUUID, err := useCases.SubmissionsRepository.SaveSubmission(dto)
 	if err != nil {
+		staticFilesApplication.DeleteUnreferencedArchive(useCases.StaticFilesRepository, archiveUUID, "submission", "Submissions")
+
 		return "", err
 	}
 
 	return submissionUUID, nil
 }
 
-func (useCases *SubmissionUseCases) submitWorkToQueue(submissionUUID string) error {
-	// Get the submission work
-	submissionWork, err := useCases.SubmissionsRepository.GetSubmissionWorkMetadata(submissionUUID)
-	if err != nil {
-		return err
-	}
-
-	// Send the submission work to the submissions queue
-	err = useCases.SubmissionsQueueManager.QueueWork(submissionWork)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func (useCases *SubmissionUseCases) GetSubmissionStatus(studentUUID, testBlockUUID string) (*dtos.SubmissionStatusUpdateDTO, error) {
 	// Check if the student could submit to the given test block
 	canSubmit, err := useCases.CanStudentSubmitToTestBlock(studentUUID, testBlockUUID)
@@ -214,19 +210,26 @@ func (useCases *SubmissionUseCases) GetSubmissionStatus(studentUUID, testBlockUU
 		return nil, errors.StudentSubmissionNotFound{}
 	}
 
+	// Get the results of the test cases
+	testResults, err := useCases.SubmissionsRepository.GetSubmissionTestResults(submission.UUID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the submission status
 	dto := dtos.SubmissionStatusUpdateDTO{
 		SubmissionUUID:   submission.UUID,
 		SubmissionStatus: submission.Status,
 		TestsPassed:      submission.Passing,
 		TestsOutput:      submission.Stdout,
+		TestResults:      testResults,
 	}
 
 	return &dto, nil
 }
 
-// GetSubmissionArchive Use case to return the bytes of the `zip` archive of a submission
-func (useCases *SubmissionUseCases) GetSubmissionArchive(dto *dtos.GetSubmissionArchiveDTO) ([]byte, error) {
+// GetSubmissionArchive Use case to return the content of the `zip` archive of a submission
+func (useCases *SubmissionUseCases) GetSubmissionArchive(dto *dtos.GetSubmissionArchiveDTO) (*staticFilesDTOs.StaticFileStreamDTO, error) {
 	// Check if the user has access to the submission
 	if dto.UserRole == "teacher" {
 		// If the user is a teacher, check if is the teacher of the course that the submission belongs to
@@ -256,14 +259,230 @@ func (useCases *SubmissionUseCases) GetSubmissionArchive(dto *dtos.GetSubmission
 		return nil, err
 	}
 
-	// Get the bytes of the .zip archive
-	archiveBytes, err := useCases.StaticFilesRepository.GetArchiveBytes(&staticFilesDTOs.StaticFileArchiveDTO{
+	// Get the content of the .zip archive
+	return useCases.StaticFilesRepository.GetArchiveStream(&staticFilesDTOs.StaticFileArchiveDTO{
 		FileUUID: archiveUUID,
 		FileType: "submission",
 	})
+}
+
+// GetStudentSubmissionsHistory Use case to return all the submissions (attempts) of a student in a test block
+func (useCases *SubmissionUseCases) GetStudentSubmissionsHistory(dto *dtos.GetStudentSubmissionsHistoryDTO) ([]*dtos.SubmissionAttemptDTO, error) {
+	// Check if the user has access to the submissions of the student
+	if dto.UserRole == "teacher" {
+		ownsTestBlock, err := useCases.BlocksRepository.DoesTeacherOwnsTestBlock(dto.UserUUID, dto.TestBlockUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ownsTestBlock {
+			return nil, errors.UserDoesNotHaveAccessToSubmission{}
+		}
+	} else {
+		if dto.UserUUID != dto.StudentUUID {
+			return nil, errors.UserDoesNotHaveAccessToSubmission{}
+		}
+
+		canSubmit, err := useCases.CanStudentSubmitToTestBlock(dto.StudentUUID, dto.TestBlockUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !canSubmit {
+			return nil, errors.StudentCannotSubmitToTestBlock{}
+		}
+	}
+
+	// Get the submissions
+	submissions, err := useCases.SubmissionsRepository.GetStudentSubmissionsHistory(dto.StudentUUID, dto.TestBlockUUID)
 	if err != nil {
 		return nil, err
 	}
 
-	return archiveBytes, nil
+	// Number the attempts from the oldest to the newest
+	attempts := []*dtos.SubmissionAttemptDTO{}
+	for index, submission := range submissions {
+		attempts = append(attempts, &dtos.SubmissionAttemptDTO{
+			SubmissionUUID: submission.UUID,
+			AttemptNumber:  len(submissions) - index,
+			Status:         submission.Status,
+			Passing:        submission.Passing,
+			Stdout:         submission.Stdout,
+			SubmittedAt:    submission.SubmittedAt,
+			LateMinutes:    submission.LateMinutes,
+		})
+	}
+
+	return attempts, nil
+}
+
+// CanTeacherWatchLaboratorySubmissions Use case to validate the teacher can receive the real time
+// updates of the submissions of a laboratory
+func (useCases *SubmissionUseCases) CanTeacherWatchLaboratorySubmissions(dto *dtos.GetLaboratorySubmissionsUpdatesDTO) error {
+	teacherOwnsLaboratory, err := useCases.LaboratoriesRepository.DoesTeacherOwnLaboratory(dto.TeacherUUID, dto.LaboratoryUUID)
+	if err != nil {
+		return err
+	}
+
+	if !teacherOwnsLaboratory {
+		return laboratoriesErrors.TeacherDoesNotOwnLaboratoryError{}
+	}
+
+	return nil
+}
+
+// RerunTestBlockSubmissions requeues the latest submission of each student to a test block, so they are
+// evaluated against the current tests of the block
+func (useCases *SubmissionUseCases) RerunTestBlockSubmissions(dto *dtos.RerunTestBlockSubmissionsDTO) (*dtos.SubmissionsRerunDTO, error) {
+	// Check that the teacher owns the test block
+	if err := useCases.checkTeacherOwnsTestBlock(dto.TeacherUUID, dto.TestBlockUUID); err != nil {
+		return nil, err
+	}
+
+	// Reset the status of the submissions
+	rerunUUID, submissionsUUIDs, err := useCases.SubmissionsRepository.CreateSubmissionsRerun(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	// The works were saved in the outbox, publish them right away
+	if len(submissionsUUIDs) > 0 {
+		useCases.SubmissionsOutboxRelay.Wake()
+	}
+
+	return useCases.SubmissionsRepository.GetSubmissionsRerun(rerunUUID)
+}
+
+// GetSubmissionsRerun returns the progress of a batch of requeued submissions
+func (useCases *SubmissionUseCases) GetSubmissionsRerun(dto *dtos.GetSubmissionsRerunDTO) (*dtos.SubmissionsRerunDTO, error) {
+	rerun, err := useCases.SubmissionsRepository.GetSubmissionsRerun(dto.RerunUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the teacher owns the test block of the batch
+	if err := useCases.checkTeacherOwnsTestBlock(dto.TeacherUUID, rerun.TestBlockUUID); err != nil {
+		return nil, err
+	}
+
+	return rerun, nil
+}
+
+// GetTestBlockSubmissionsReruns returns the progress of all the batches of requeued submissions of a test block
+func (useCases *SubmissionUseCases) GetTestBlockSubmissionsReruns(dto *dtos.GetTestBlockSubmissionsRerunsDTO) ([]*dtos.SubmissionsRerunDTO, error) {
+	// Check that the teacher owns the test block
+	if err := useCases.checkTeacherOwnsTestBlock(dto.TeacherUUID, dto.TestBlockUUID); err != nil {
+		return nil, err
+	}
+
+	return useCases.SubmissionsRepository.GetTestBlockSubmissionsReruns(dto.TestBlockUUID)
+}
+
+// HandleStuckSubmissions requeues the submissions whose status has not changed in time, as the message may
+// have been lost or the runner may have crashed. Submissions that were already requeued the maximum number
+// of times or whose work was dead-lettered are marked as timed out, so the students can submit again
+func (useCases *SubmissionUseCases) HandleStuckSubmissions(dto *dtos.StuckSubmissionsDTO) (*dtos.StuckSubmissionsResultDTO, error) {
+	timedOutSubmissions, err := useCases.SubmissionsRepository.SetStuckSubmissionsAsTimedOut(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	requeuedSubmissions, err := useCases.SubmissionsRepository.RequeueStuckSubmissions(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	// The works were saved in the outbox, publish them right away
+	if len(requeuedSubmissions) > 0 {
+		useCases.SubmissionsOutboxRelay.Wake()
+	}
+
+	return &dtos.StuckSubmissionsResultDTO{
+		RequeuedSubmissions: requeuedSubmissions,
+		TimedOutSubmissions: timedOutSubmissions,
+	}, nil
+}
+
+// RelaySubmissionsOutbox publishes the pending messages of the outbox to the submissions queue. Messages that
+// can not be published are retried in the next run. Returns the number of published messages
+func (useCases *SubmissionUseCases) RelaySubmissionsOutbox(limit int) (int, error) {
+	messages, err := useCases.SubmissionsRepository.ClaimSubmissionsOutboxMessages(limit)
+	if err != nil {
+		return 0, err
+	}
+
+	publishedMessages := 0
+	for _, message := range messages {
+		err := useCases.publishSubmissionsOutboxMessage(message)
+		if err != nil {
+			log.Printf(
+				"[Submissions outbox]: Unable to publish the work of the submission %s (attempt %d): %s",
+				message.SubmissionUUID,
+				message.Attempts+1,
+				err.Error(),
+			)
+
+			err = useCases.SubmissionsRepository.SetSubmissionsOutboxMessageAsFailed(message.UUID, err.Error())
+			if err != nil {
+				return publishedMessages, err
+			}
+
+			continue
+		}
+
+		err = useCases.SubmissionsRepository.SetSubmissionsOutboxMessageAsSent(message.UUID)
+		if err != nil {
+			return publishedMessages, err
+		}
+
+		publishedMessages++
+	}
+
+	return publishedMessages, nil
+}
+
+func (useCases *SubmissionUseCases) publishSubmissionsOutboxMessage(message *entities.SubmissionsOutboxMessage) error {
+	work := entities.SubmissionWork{}
+	if err := json.Unmarshal([]byte(message.Payload), &work); err != nil {
+		return err
+	}
+
+	return useCases.SubmissionsQueueManager.QueueWork(&work)
+}
+
+// SaveSubmissionDeadLetter saves a submission work that could not be processed by the runners, so it can
+// be inspected and replayed by the administrators
+func (useCases *SubmissionUseCases) SaveSubmissionDeadLetter(dto *dtos.SaveSubmissionDeadLetterDTO) error {
+	return useCases.SubmissionsRepository.SaveSubmissionDeadLetter(dto)
+}
+
+// GetSubmissionsDeadLetters returns the submission works that could not be processed by the runners
+func (useCases *SubmissionUseCases) GetSubmissionsDeadLetters(dto *dtos.GetSubmissionsDeadLettersDTO) ([]*dtos.SubmissionDeadLetterDTO, error) {
+	return useCases.SubmissionsRepository.GetSubmissionsDeadLetters(dto)
+}
+
+// ReplaySubmissionDeadLetter sends a dead-lettered submission work to the queue again. Returns the UUID of
+// the replayed submission
+func (useCases *SubmissionUseCases) ReplaySubmissionDeadLetter(deadLetterUUID string) (string, error) {
+	submissionUUID, err := useCases.SubmissionsRepository.ReplaySubmissionDeadLetter(deadLetterUUID)
+	if err != nil {
+		return "", err
+	}
+
+	// The work was saved in the outbox, publish it right away
+	useCases.SubmissionsOutboxRelay.Wake()
+	return submissionUUID, nil
+}
+
+func (useCases *SubmissionUseCases) checkTeacherOwnsTestBlock(teacherUUID string, testBlockUUID string) error {
+	ownsTestBlock, err := useCases.BlocksRepository.DoesTeacherOwnsTestBlock(teacherUUID, testBlockUUID)
+	if err != nil {
+		return err
+	}
+
+	if !ownsTestBlock {
+		return blocksErrors.TeacherDoesNotOwnBlock{}
+	}
+
+	return nil
 }
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
+)
+
+// stuckSubmissionsRepositoryStub returns the given requeued and timed out submissions
+type stuckSubmissionsRepositoryStub struct {
+	definitions.SubmissionsRepository
+
+	stuckSubmissions    []string
+	timedOutSubmissions []string
+}
+
+func (stub *stuckSubmissionsRepositoryStub) RequeueStuckSubmissions(dto *dtos.StuckSubmissionsDTO) ([]string, error) {
+	return stub.stuckSubmissions, nil
+}
+
+func (stub *stuckSubmissionsRepositoryStub) SetStuckSubmissionsAsTimedOut(dto *dtos.StuckSubmissionsDTO) ([]string, error) {
+	return stub.timedOutSubmissions, nil
+}
+
+// outboxRepositoryStub returns the given outbox messages and records the sent and failed ones
+type outboxRepositoryStub struct {
+	definitions.SubmissionsRepository
+
+	outboxMessages       []*entities.SubmissionsOutboxMessage
+	sentOutboxMessages   []string
+	failedOutboxMessages []string
+}
+
+func (stub *outboxRepositoryStub) ClaimSubmissionsOutboxMessages(limit int) ([]*entities.SubmissionsOutboxMessage, error) {
+	return stub.outboxMessages, nil
+}
+
+func (stub *outboxRepositoryStub) SetSubmissionsOutboxMessageAsSent(messageUUID string) error {
+	stub.sentOutboxMessages = append(stub.sentOutboxMessages, messageUUID)
+	return nil
+}
+
+func (stub *outboxRepositoryStub) SetSubmissionsOutboxMessageAsFailed(messageUUID string, errorMessage string) error {
+	stub.failedOutboxMessages = append(stub.failedOutboxMessages, messageUUID)
+	return nil
+}
+
+// submissionsQueueManagerStub fails to queue the submissions in `failingSubmissions`
+type submissionsQueueManagerStub struct {
+	failingSubmissions map[string]bool
+	queuedSubmissions  []string
+}
+
+func (stub *submissionsQueueManagerStub) QueueWork(work *entities.SubmissionWork) error {
+	if stub.failingSubmissions[work.SubmissionUUID] {
+		return errors.New("connection closed")
+	}
+
+	stub.queuedSubmissions = append(stub.queuedSubmissions, work.SubmissionUUID)
+	return nil
+}
+
+// submissionsOutboxRelayStub counts the times the relay was woken up
+type submissionsOutboxRelayStub struct {
+	wakeUps int
+}
+
+func (stub *submissionsOutboxRelayStub) Wake() {
+	stub.wakeUps++
+}
+
+func TestHandleStuckSubmissions(t *testing.T) {
+	queueManager := &submissionsQueueManagerStub{}
+	relay := &submissionsOutboxRelayStub{}
+
+	useCases := SubmissionUseCases{
+		SubmissionsRepository: &stuckSubmissionsRepositoryStub{
+			stuckSubmissions:    []string{"first", "second", "third"},
+			timedOutSubmissions: []string{"fourth"},
+		},
+		SubmissionsQueueManager: queueManager,
+		SubmissionsOutboxRelay:  relay,
+	}
+
+	result, err := useCases.HandleStuckSubmissions(&dtos.StuckSubmissionsDTO{
+		TimeoutSeconds:     60,
+		MaxRequeueAttempts: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The works are saved in the outbox by the repository, so they are only published by the relay
+	if len(queueManager.queuedSubmissions) != 0 {
+		t.Errorf("expected no directly queued submissions, got %v", queueManager.queuedSubmissions)
+	}
+
+	if relay.wakeUps != 1 {
+		t.Errorf("expected the relay to be woken up once, got %d", relay.wakeUps)
+	}
+
+	if !reflect.DeepEqual(result.RequeuedSubmissions, []string{"first", "second", "third"}) {
+		t.Errorf("unexpected requeued submissions: %v", result.RequeuedSubmissions)
+	}
+
+	if !reflect.DeepEqual(result.TimedOutSubmissions, []string{"fourth"}) {
+		t.Errorf("unexpected timed out submissions: %v", result.TimedOutSubmissions)
+	}
+}
+
+func TestRelaySubmissionsOutbox(t *testing.T) {
+	repository := &outboxRepositoryStub{
+		outboxMessages: []*entities.SubmissionsOutboxMessage{
+			{UUID: "message-1", SubmissionUUID: "first", Payload: `{"submission_uuid":"first"}`},
+			{UUID: "message-2", SubmissionUUID: "second", Payload: `{"submission_uuid":"second"}`},
+			{UUID: "message-3", SubmissionUUID: "third", Payload: `not a JSON`},
+		},
+	}
+
+	queueManager := &submissionsQueueManagerStub{
+		failingSubmissions: map[string]bool{"second": true},
+	}
+
+	useCases := SubmissionUseCases{
+		SubmissionsRepository:   repository,
+		SubmissionsQueueManager: queueManager,
+	}
+
+	publishedMessages, err := useCases.RelaySubmissionsOutbox(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if publishedMessages != 1 {
+		t.Errorf("expected 1 published message, got %d", publishedMessages)
+	}
+
+	if !reflect.DeepEqual(queueManager.queuedSubmissions, []string{"first"}) {
+		t.Errorf("unexpected queued submissions: %v", queueManager.queuedSubmissions)
+	}
+
+	// Only the confirmed messages are marked as sent, the others are retried
+	if !reflect.DeepEqual(repository.sentOutboxMessages, []string{"message-1"}) {
+		t.Errorf("unexpected sent messages: %v", repository.sentOutboxMessages)
+	}
+
+	if !reflect.DeepEqual(repository.failedOutboxMessages, []string{"message-2", "message-3"}) {
+		t.Errorf("unexpected failed messages: %v", repository.failedOutboxMessages)
+	}
+}
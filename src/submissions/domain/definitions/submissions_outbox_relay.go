@@ -0,0 +1,6 @@
+package definitions
+
+type SubmissionsOutboxRelay interface {
+	// Wake asks the relay to publish the pending messages of the outbox without waiting for its next run
+	Wake()
+}
@@ -1,18 +1,27 @@
 package definitions
 
 import (
+	"time"
+
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
 )
 
 type SubmissionsRepository interface {
-	// SaveSubmission saves the metadata of a new submission in the database
+	// SaveSubmission saves the metadata of a new submission in the database, along with the outbox message
+	// with its work
 	SaveSubmission(dto *dtos.CreateSubmissionDTO) (submissionUUID string, err error)
-	// ResetSubmissionStatus resets the status of a submission to "pending"
-	ResetSubmissionStatus(submissionUUID string) (err error)
 
-	// GetStudentSubmission returns the metadata of an student submission
+	// GetStudentSubmission returns the metadata of the latest student submission
 	GetStudentSubmission(studentUUID string, testBlockUUID string) (submission *entities.Submission, err error)
+	// GetStudentSubmissionsHistory returns the metadata of all the student submissions (attempts) in a test block
+	GetStudentSubmissionsHistory(studentUUID string, testBlockUUID string) (submissions []*entities.Submission, err error)
+
+	// SaveSubmissionTestResults replaces the results of the test cases of a submission
+	SaveSubmissionTestResults(submissionUUID string, results []*entities.SubmissionTestResult) (err error)
+	// GetSubmissionTestResults returns the results of the test cases of a submission
+	GetSubmissionTestResults(submissionUUID string) (results []*entities.SubmissionTestResult, err error)
+
 	// GetSubmissionWorkMetadata returns the metadata needed to enqueue a new submission work
 	GetSubmissionWorkMetadata(submissionUUID string) (submissionWorkMetadata *entities.SubmissionWork, err error)
 
@@ -22,6 +31,43 @@ type SubmissionsRepository interface {
 	// DoesStudentOwnSubmission returns true if the student owns the submission
 	DoesStudentOwnSubmission(studentUUID string, submissionUUID string) (bool, error)
 
+	// GetLaboratorySubmissionUpdate returns the current status of a submission along with the progress
+	// of the student in the laboratory the submission belongs to
+	GetLaboratorySubmissionUpdate(submissionUUID string) (update *dtos.LaboratorySubmissionUpdateDTO, err error)
+
 	// GetTeacherOfCourseBySubmissionUUID returns the teacher of the course that the submission belongs to
 	GetTeacherOfCourseBySubmissionUUID(submissionUUID string) (teacherUUID string, err error)
+
+	// CreateSubmissionsRerun resets the status of the latest (ready or timed out) submissions of a test block and
+	// saves them as a new batch. Returns the UUIDs of the submissions to requeue
+	CreateSubmissionsRerun(dto *dtos.RerunTestBlockSubmissionsDTO) (rerunUUID string, submissionsUUIDs []string, err error)
+	// GetSubmissionsRerun returns the progress of a batch of requeued submissions
+	GetSubmissionsRerun(rerunUUID string) (rerun *dtos.SubmissionsRerunDTO, err error)
+	// GetTestBlockSubmissionsReruns returns the progress of all the batches of requeued submissions of a test block
+	GetTestBlockSubmissionsReruns(testBlockUUID string) (reruns []*dtos.SubmissionsRerunDTO, err error)
+
+	// RequeueStuckSubmissions resets the status of the submissions whose status has not changed in time and that
+	// were not requeued the maximum number of times yet. Returns the UUIDs of the submissions to requeue
+	RequeueStuckSubmissions(dto *dtos.StuckSubmissionsDTO) (submissionsUUIDs []string, err error)
+	// SetStuckSubmissionsAsTimedOut marks as timed out the submissions whose status has not changed in time
+	// after being requeued the maximum number of times
+	SetStuckSubmissionsAsTimedOut(dto *dtos.StuckSubmissionsDTO) (submissionsUUIDs []string, err error)
+
+	// ClaimSubmissionsOutboxMessages returns the oldest messages of the outbox that were not sent yet. The messages
+	// are locked for a while, so other instances of the gateway do not publish them at the same time
+	ClaimSubmissionsOutboxMessages(limit int) (messages []*entities.SubmissionsOutboxMessage, err error)
+	// SetSubmissionsOutboxMessageAsSent marks a message of the outbox as sent
+	SetSubmissionsOutboxMessageAsSent(messageUUID string) (err error)
+	// SetSubmissionsOutboxMessageAsFailed releases a message of the outbox that could not be published, so it is retried
+	SetSubmissionsOutboxMessageAsFailed(messageUUID string, errorMessage string) (err error)
+	// DeleteSentSubmissionsOutboxMessages removes the messages that were sent longer than the given duration ago
+	DeleteSentSubmissionsOutboxMessages(olderThan time.Duration) (err error)
+
+	// SaveSubmissionDeadLetter saves a submission work that was rejected by the runners or expired in the queue
+	SaveSubmissionDeadLetter(dto *dtos.SaveSubmissionDeadLetterDTO) (err error)
+	// GetSubmissionsDeadLetters returns the dead-lettered submission works, from the newest to the oldest
+	GetSubmissionsDeadLetters(dto *dtos.GetSubmissionsDeadLettersDTO) (deadLetters []*dtos.SubmissionDeadLetterDTO, err error)
+	// ReplaySubmissionDeadLetter resets the status of a dead-lettered submission and saves its work in the outbox
+	// again. Returns the UUID of the replayed submission
+	ReplaySubmissionDeadLetter(deadLetterUUID string) (submissionUUID string, err error)
 }
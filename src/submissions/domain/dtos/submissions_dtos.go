@@ -1,12 +1,20 @@
 package dtos
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+
+	laboratoriesDTOs "github.com/UPB-Code-Labs/main-api/src/laboratories/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
+)
 
 type CreateSubmissionDTO struct {
 	StudentUUID       string
 	TestBlockUUID     string
 	SubmissionArchive *multipart.File
 	SavedArchiveUUID  string
+
+	// Minutes the submission was sent after the due date of the student
+	LateMinutes int
 }
 
 type GetSubmissionDTO struct {
@@ -19,6 +27,9 @@ type SubmissionStatusUpdateDTO struct {
 	SubmissionStatus string `json:"submission_status"`
 	TestsPassed      bool   `json:"tests_passed"`
 	TestsOutput      string `json:"tests_output"`
+
+	// Results of each test case, sent by the tests runner once the submission is ready
+	TestResults []*entities.SubmissionTestResult `json:"test_results"`
 }
 
 type GetSubmissionArchiveDTO struct {
@@ -26,3 +37,108 @@ type GetSubmissionArchiveDTO struct {
 	UserRole       string
 	SubmissionUUID string
 }
+
+type GetStudentSubmissionsHistoryDTO struct {
+	UserUUID      string
+	UserRole      string
+	StudentUUID   string
+	TestBlockUUID string
+}
+
+type SubmissionAttemptDTO struct {
+	SubmissionUUID string `json:"submission_uuid"`
+	AttemptNumber  int    `json:"attempt_number"`
+	Status         string `json:"status"`
+	Passing        bool   `json:"passing"`
+	Stdout         string `json:"stdout"`
+	SubmittedAt    string `json:"submitted_at"`
+	LateMinutes    int    `json:"late_minutes"`
+}
+
+type GetLaboratorySubmissionsUpdatesDTO struct {
+	TeacherUUID    string
+	LaboratoryUUID string
+}
+
+// LaboratorySubmissionUpdateDTO status update of a submission sent to the teachers of the laboratory
+type LaboratorySubmissionUpdateDTO struct {
+	LaboratoryUUID   string `json:"-"`
+	SubmissionUUID   string `json:"submission_uuid"`
+	TestBlockUUID    string `json:"test_block_uuid"`
+	SubmissionStatus string `json:"submission_status"`
+	TestsPassed      bool   `json:"tests_passed"`
+
+	// Up-to-date progress of the student in the laboratory
+	StudentProgress laboratoriesDTOs.SummarizedStudentProgressDTO `json:"student_progress"`
+}
+
+type RerunTestBlockSubmissionsDTO struct {
+	TeacherUUID   string
+	TestBlockUUID string
+
+	// What started the rerun: `manual` or `test_archive_update`
+	Trigger string
+}
+
+type GetSubmissionsRerunDTO struct {
+	TeacherUUID string
+	RerunUUID   string
+}
+
+type GetTestBlockSubmissionsRerunsDTO struct {
+	TeacherUUID   string
+	TestBlockUUID string
+}
+
+// SubmissionsRerunDTO progress of a batch of submissions requeued to be evaluated against the current tests
+type SubmissionsRerunDTO struct {
+	UUID          string `json:"uuid"`
+	TestBlockUUID string `json:"test_block_uuid"`
+	Trigger       string `json:"trigger"`
+	CreatedAt     string `json:"created_at"`
+
+	TotalSubmissions     int  `json:"total_submissions"`
+	PendingSubmissions   int  `json:"pending_submissions"`
+	RunningSubmissions   int  `json:"running_submissions"`
+	ReadySubmissions     int  `json:"ready_submissions"`
+	TimedOutSubmissions  int  `json:"timed_out_submissions"`
+	NotQueuedSubmissions int  `json:"not_queued_submissions"`
+	IsCompleted          bool `json:"is_completed"`
+}
+
+// StuckSubmissionsDTO parameters used to find the submissions whose status has not changed in time
+type StuckSubmissionsDTO struct {
+	TimeoutSeconds     int
+	MaxRequeueAttempts int
+}
+
+// StuckSubmissionsResultDTO submissions handled by the watchdog in a single run
+type StuckSubmissionsResultDTO struct {
+	RequeuedSubmissions []string
+	TimedOutSubmissions []string
+}
+
+// SaveSubmissionDeadLetterDTO submission work received from the dead letter queue
+type SaveSubmissionDeadLetterDTO struct {
+	SubmissionUUID string
+	Payload        string
+
+	// Why the work was dead-lettered: `rejected`, `expired`, `maxlen` or `delivery_limit`
+	Reason string
+}
+
+type GetSubmissionsDeadLettersDTO struct {
+	IncludeReplayed bool
+}
+
+// SubmissionDeadLetterDTO submission work that could not be processed by the runners
+type SubmissionDeadLetterDTO struct {
+	UUID             string  `json:"uuid"`
+	SubmissionUUID   string  `json:"submission_uuid"`
+	StudentUUID      string  `json:"student_uuid"`
+	TestBlockUUID    string  `json:"test_block_uuid"`
+	SubmissionStatus string  `json:"submission_status"`
+	Reason           string  `json:"reason"`
+	DeadLetteredAt   string  `json:"dead_lettered_at"`
+	ReplayedAt       *string `json:"replayed_at"`
+}
@@ -1,6 +1,12 @@
 package entities
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sharedEntities "github.com/UPB-Code-Labs/main-api/src/shared/domain/entities"
+)
 
 type Submission struct {
 	UUID        string `json:"uuid"`
@@ -9,6 +15,42 @@ type Submission struct {
 	Status      string `json:"status"`
 	Stdout      string `json:"stdout"`
 	SubmittedAt string `json:"-"`
+	LateMinutes int    `json:"late_minutes"`
+}
+
+type SubmissionTestResult struct {
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	DurationMs     int    `json:"duration_ms"`
+	FailureMessage string `json:"failure_message"`
+}
+
+// Statuses of the results of the test cases. See the `TEST_RESULT_STATUS` type in the database
+const (
+	TestResultPassed  = "passed"
+	TestResultFailed  = "failed"
+	TestResultError   = "error"
+	TestResultSkipped = "skipped"
+)
+
+// NormalizeStatus maps the status reported by the tests runner to one of the known statuses. Unknown
+// statuses are saved as errors, keeping the reported status in the failure message
+func (result *SubmissionTestResult) NormalizeStatus() {
+	switch strings.ToLower(strings.TrimSpace(result.Status)) {
+	case "passed", "pass", "passing", "success", "ok":
+		result.Status = TestResultPassed
+	case "failed", "fail", "failing", "failure":
+		result.Status = TestResultFailed
+	case "error", "errored":
+		result.Status = TestResultError
+	case "skipped", "skip", "ignored", "disabled":
+		result.Status = TestResultSkipped
+	default:
+		if result.FailureMessage == "" {
+			result.FailureMessage = fmt.Sprintf("Unknown status reported by the tests runner: %q", result.Status)
+		}
+		result.Status = TestResultError
+	}
 }
 
 type SubmissionWork struct {
@@ -16,6 +58,17 @@ type SubmissionWork struct {
 	LanguageUUID          string `json:"language_uuid"`
 	SubmissionArchiveUUID string `json:"submission_archive_uuid"`
 	TestArchiveUUID       string `json:"test_archive_uuid"`
+
+	// Limits of the test block, or the defaults of its language when not overridden
+	ExecutionLimits sharedEntities.ExecutionLimits `json:"execution_limits"`
+}
+
+// SubmissionsOutboxMessage submission work saved along with its submission, waiting to be published
+type SubmissionsOutboxMessage struct {
+	UUID           string
+	SubmissionUUID string
+	Payload        string
+	Attempts       int
 }
 
 func (sw *SubmissionWork) ToJSON() (string, error) {
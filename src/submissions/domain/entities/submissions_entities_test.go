@@ -0,0 +1,39 @@
+package entities
+
+import "testing"
+
+func TestNormalizeTestResultStatus(t *testing.T) {
+	testCases := []struct {
+		status                 string
+		failureMessage         string
+		expectedStatus         string
+		expectedFailureMessage string
+	}{
+		{status: "passed", expectedStatus: TestResultPassed},
+		{status: " PASS ", expectedStatus: TestResultPassed},
+		{status: "Failure", failureMessage: "expected 2, got 3", expectedStatus: TestResultFailed, expectedFailureMessage: "expected 2, got 3"},
+		{status: "errored", expectedStatus: TestResultError},
+		{status: "ignored", expectedStatus: TestResultSkipped},
+		{status: "timeout", expectedStatus: TestResultError, expectedFailureMessage: `Unknown status reported by the tests runner: "timeout"`},
+		{status: "", failureMessage: "the runner crashed", expectedStatus: TestResultError, expectedFailureMessage: "the runner crashed"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.status, func(t *testing.T) {
+			result := SubmissionTestResult{
+				Name:           "test",
+				Status:         testCase.status,
+				FailureMessage: testCase.failureMessage,
+			}
+			result.NormalizeStatus()
+
+			if result.Status != testCase.expectedStatus {
+				t.Errorf("expected the status %q, got %q", testCase.expectedStatus, result.Status)
+			}
+
+			if result.FailureMessage != testCase.expectedFailureMessage {
+				t.Errorf("expected the failure message %q, got %q", testCase.expectedFailureMessage, result.FailureMessage)
+			}
+		})
+	}
+}
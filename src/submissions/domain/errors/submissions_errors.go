@@ -52,6 +52,16 @@ func (err StudentHasPendingSubmission) StatusCode() int {
 	return http.StatusForbidden
 }
 
+type LaboratoryIsNotOpen struct{}
+
+func (err LaboratoryIsNotOpen) Error() string {
+	return "The laboratory does not accept submissions yet"
+}
+
+func (err LaboratoryIsNotOpen) StatusCode() int {
+	return http.StatusForbidden
+}
+
 type LaboratoryIsClosed struct{}
 
 func (err LaboratoryIsClosed) Error() string {
@@ -71,3 +81,33 @@ func (err UserDoesNotHaveAccessToSubmission) Error() string {
 func (err UserDoesNotHaveAccessToSubmission) StatusCode() int {
 	return http.StatusForbidden
 }
+
+type SubmissionsRerunNotFound struct{}
+
+func (err SubmissionsRerunNotFound) Error() string {
+	return "No rerun of submissions was found with the given UUID"
+}
+
+func (err SubmissionsRerunNotFound) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type SubmissionDeadLetterNotFound struct{}
+
+func (err SubmissionDeadLetterNotFound) Error() string {
+	return "No dead-lettered submission was found with the given UUID"
+}
+
+func (err SubmissionDeadLetterNotFound) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type SubmissionDeadLetterAlreadyReplayed struct{}
+
+func (err SubmissionDeadLetterAlreadyReplayed) Error() string {
+	return "The dead-lettered submission was already replayed"
+}
+
+func (err SubmissionDeadLetterAlreadyReplayed) StatusCode() int {
+	return http.StatusConflict
+}
@@ -66,6 +66,10 @@ func (controller *SubmissionsController) HandleReceiveSubmissions(c *gin.Context
 		return
 	}
 
+	// Notify the teachers watching the laboratory about the new submission
+	laboratoriesRealTimeUpdater := submissionsImplementations.GetLaboratoriesRealTimeUpdatesSenderInstance()
+	go laboratoriesRealTimeUpdater.SendSubmissionUpdate(submissionUUID)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"uuid": submissionUUID,
 	})
@@ -92,15 +96,33 @@ func (controller *SubmissionsController) HandleGetSubmission(c *gin.Context) {
 
 	realTimeUpdater := submissionsImplementations.GetSubmissionsRealTimeUpdatesSenderInstance()
 
-	// Create a channel to send real time updates about the submission
-	updatesChannel := realTimeUpdater.CreateChannel(currentStatus.SubmissionUUID)
-	defer realTimeUpdater.DeleteChannel(currentStatus.SubmissionUUID)
+	// Subscribe to the real time updates about the submission
+	subscription := realTimeUpdater.Subscribe(currentStatus.SubmissionUUID)
+	defer realTimeUpdater.Unsubscribe(subscription)
+
+	// sendUpdate sends an update to the client and returns whether the connection should be kept open
+	sendUpdate := func(update *dtos.SubmissionStatusUpdateDTO) bool {
+		// Parse the update to a JSON
+		json, _ := json.Marshal(update)
+
+		// Send the update
+		c.SSEvent("update", string(json))
+
+		isFinalStatus := update.SubmissionStatus == "ready" || update.SubmissionStatus == "timed_out"
+		shouldCloseConnection := isFinalStatus &&
+			sharedInfrastructure.GetEnvironment().ExecEnvironment == "testing"
+
+		return !shouldCloseConnection
+	}
 
 	/*
-		 Add the current status to the channel. Please, note that further updates will be sent
-		from the Real Time Updates queue manager
+		 Send the current status only to this client, as it may be older than the updates the other
+		subscribers already received. Further updates are sent from the Real Time Updates queue manager
 	*/
-	go realTimeUpdater.SendUpdate(currentStatus)
+	if !sendUpdate(currentStatus) {
+		return
+	}
+	c.Writer.Flush()
 
 	// Connection timeout
 	timeoutCh := time.After(5 * time.Minute)
@@ -109,26 +131,13 @@ func (controller *SubmissionsController) HandleGetSubmission(c *gin.Context) {
 	c.Stream(func(_ io.Writer) bool {
 		select {
 		// A new update was received
-		case update, ok := <-*updatesChannel:
-			// Check the channel is not closed
+		case update, ok := <-subscription.Updates:
+			// Check the channel is not closed (e.g. the client was too slow to consume the updates)
 			if !ok {
 				return false
 			}
 
-			// Parse the update to a JSON
-			json, _ := json.Marshal(update)
-
-			// Send the update
-			c.SSEvent("update", string(json))
-
-			shouldCloseConnection := update.SubmissionStatus == "ready" &&
-				sharedInfrastructure.GetEnvironment().ExecEnvironment == "testing"
-
-			if shouldCloseConnection {
-				return false
-			} else {
-				return true
-			}
+			return sendUpdate(update)
 
 		// The connection timed out
 		case <-timeoutCh:
@@ -162,11 +171,233 @@ func (controller *SubmissionsController) HandleGetSubmissionArchive(c *gin.Conte
 		SubmissionUUID: submissionUUID,
 	}
 
-	archiveBytes, err := controller.UseCases.GetSubmissionArchive(&dto)
+	archive, err := controller.UseCases.GetSubmissionArchive(&dto)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	sharedInfrastructure.SendArchive(c, archive.Content, archive.ContentLength, archive.ContentDisposition)
+}
+
+// HandleGetStudentSubmissionsHistory controller to handle the request to get all the submissions (attempts)
+// of a student in the given test block
+func (controller *SubmissionsController) HandleGetStudentSubmissionsHistory(c *gin.Context) {
+	userUUID := c.GetString("session_uuid")
+	userRole := c.GetString("session_role")
+	testBlockUUID := c.Param("test_block_uuid")
+	studentUUID := c.Param("student_uuid")
+
+	// Validate the testBlockUUID
+	if err := sharedInfrastructure.GetValidator().Var(testBlockUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The test block UUID is not valid",
+		})
+		return
+	}
+
+	// Validate the studentUUID
+	if err := sharedInfrastructure.GetValidator().Var(studentUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The student UUID is not valid",
+		})
+		return
+	}
+
+	dto := dtos.GetStudentSubmissionsHistoryDTO{
+		UserUUID:      userUUID,
+		UserRole:      userRole,
+		StudentUUID:   studentUUID,
+		TestBlockUUID: testBlockUUID,
+	}
+
+	attempts, err := controller.UseCases.GetStudentSubmissionsHistory(&dto)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"submissions": attempts,
+	})
+}
+
+// HandleGetLaboratorySubmissionsUpdates controller to send the real time status updates of the
+// submissions of all the students in a laboratory to the teacher
+func (controller *SubmissionsController) HandleGetLaboratorySubmissionsUpdates(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	laboratoryUUID := c.Param("laboratory_uuid")
+
+	// Validate the laboratoryUUID
+	if err := sharedInfrastructure.GetValidator().Var(laboratoryUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The laboratory UUID is not valid",
+		})
+		return
+	}
+
+	// Validate the teacher owns the laboratory
+	err := controller.UseCases.CanTeacherWatchLaboratorySubmissions(&dtos.GetLaboratorySubmissionsUpdatesDTO{
+		TeacherUUID:    teacherUUID,
+		LaboratoryUUID: laboratoryUUID,
+	})
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	c.Data(http.StatusOK, "application/zip", archiveBytes)
+	realTimeUpdater := submissionsImplementations.GetLaboratoriesRealTimeUpdatesSenderInstance()
+
+	// Subscribe to the real time updates of the laboratory
+	subscription := realTimeUpdater.Subscribe(laboratoryUUID)
+	defer realTimeUpdater.Unsubscribe(subscription)
+
+	// Connection timeout
+	timeoutCh := time.After(5 * time.Minute)
+
+	// Send real time updates
+	c.Stream(func(_ io.Writer) bool {
+		select {
+		// A new update was received
+		case update, ok := <-subscription.Updates:
+			// Check the channel is not closed (e.g. the client was too slow to consume the updates)
+			if !ok {
+				return false
+			}
+
+			// Parse the update to a JSON
+			json, _ := json.Marshal(update)
+
+			// Send the update
+			c.SSEvent("update", string(json))
+			return true
+
+		// The connection timed out
+		case <-timeoutCh:
+			return false
+
+		// The client closed the connection
+		case <-c.Writer.CloseNotify():
+			return false
+		}
+	})
+}
+
+// HandleRerunTestBlockSubmissions controller to requeue the latest submissions of a test block
+func (controller *SubmissionsController) HandleRerunTestBlockSubmissions(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	testBlockUUID := c.Param("test_block_uuid")
+
+	// Validate the test block UUID
+	if err := sharedInfrastructure.GetValidator().Var(testBlockUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The test block UUID is not valid",
+		})
+		return
+	}
+
+	rerun, err := controller.UseCases.RerunTestBlockSubmissions(&dtos.RerunTestBlockSubmissionsDTO{
+		TeacherUUID:   teacherUUID,
+		TestBlockUUID: testBlockUUID,
+		Trigger:       "manual",
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, rerun)
+}
+
+// HandleGetTestBlockSubmissionsReruns controller to get the progress of the batches of requeued submissions
+// of a test block
+func (controller *SubmissionsController) HandleGetTestBlockSubmissionsReruns(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	testBlockUUID := c.Param("test_block_uuid")
+
+	// Validate the test block UUID
+	if err := sharedInfrastructure.GetValidator().Var(testBlockUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The test block UUID is not valid",
+		})
+		return
+	}
+
+	reruns, err := controller.UseCases.GetTestBlockSubmissionsReruns(&dtos.GetTestBlockSubmissionsRerunsDTO{
+		TeacherUUID:   teacherUUID,
+		TestBlockUUID: testBlockUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reruns": reruns,
+	})
+}
+
+// HandleGetSubmissionsRerun controller to get the progress of a batch of requeued submissions
+func (controller *SubmissionsController) HandleGetSubmissionsRerun(c *gin.Context) {
+	teacherUUID := c.GetString("session_uuid")
+	rerunUUID := c.Param("rerun_uuid")
+
+	// Validate the rerun UUID
+	if err := sharedInfrastructure.GetValidator().Var(rerunUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The rerun UUID is not valid",
+		})
+		return
+	}
+
+	rerun, err := controller.UseCases.GetSubmissionsRerun(&dtos.GetSubmissionsRerunDTO{
+		TeacherUUID: teacherUUID,
+		RerunUUID:   rerunUUID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rerun)
+}
+
+// HandleGetSubmissionsDeadLetters controller to get the submission works that could not be processed by the runners
+func (controller *SubmissionsController) HandleGetSubmissionsDeadLetters(c *gin.Context) {
+	includeReplayed := c.Query("include_replayed") == "true"
+
+	deadLetters, err := controller.UseCases.GetSubmissionsDeadLetters(&dtos.GetSubmissionsDeadLettersDTO{
+		IncludeReplayed: includeReplayed,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": deadLetters,
+	})
+}
+
+// HandleReplaySubmissionDeadLetter controller to send a dead-lettered submission work to the queue again
+func (controller *SubmissionsController) HandleReplaySubmissionDeadLetter(c *gin.Context) {
+	deadLetterUUID := c.Param("dead_letter_uuid")
+
+	// Validate the dead letter UUID
+	if err := sharedInfrastructure.GetValidator().Var(deadLetterUUID, "uuid4"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "The dead letter UUID is not valid",
+		})
+		return
+	}
+
+	submissionUUID, err := controller.UseCases.ReplaySubmissionDeadLetter(deadLetterUUID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"submission_uuid": submissionUUID,
+	})
 }
@@ -1,11 +1,7 @@
 package http
 
 import (
-	blocksImplementations "github.com/UPB-Code-Labs/main-api/src/blocks/infrastructure/implementations"
-	laboratoriesImplementation "github.com/UPB-Code-Labs/main-api/src/laboratories/infrastructure/implementations"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
-	staticFilesImplementations "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
-	"github.com/UPB-Code-Labs/main-api/src/submissions/application"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/infrastructure/implementations"
 	"github.com/gin-gonic/gin"
 )
@@ -13,16 +9,10 @@ import (
 func StartSubmissionsRoutes(g *gin.RouterGroup) {
 	submissionsGroup := g.Group("/submissions")
 
-	useCases := application.SubmissionUseCases{
-		StaticFilesRepository:   &staticFilesImplementations.StaticFilesMicroserviceImplementation{},
-		LaboratoriesRepository:  laboratoriesImplementation.GetLaboratoriesPostgresRepositoryInstance(),
-		BlocksRepository:        blocksImplementations.GetBlocksPostgresRepositoryInstance(),
-		SubmissionsRepository:   implementations.GetSubmissionsRepositoryInstance(),
-		SubmissionsQueueManager: implementations.GetSubmissionsRabbitMQQueueManagerInstance(),
-	}
+	useCases := implementations.NewSubmissionUseCases()
 
 	controllers := SubmissionsController{
-		UseCases: &useCases,
+		UseCases: useCases,
 	}
 
 	submissionsGroup.POST(
@@ -40,6 +30,56 @@ func StartSubmissionsRoutes(g *gin.RouterGroup) {
 		controllers.HandleGetSubmission,
 	)
 
+	submissionsGroup.GET(
+		"/test_blocks/:test_block_uuid/students/:student_uuid/history",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher", "student"}),
+		controllers.HandleGetStudentSubmissionsHistory,
+	)
+
+	submissionsGroup.POST(
+		"/test_blocks/:test_block_uuid/reruns",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controllers.HandleRerunTestBlockSubmissions,
+	)
+
+	submissionsGroup.GET(
+		"/test_blocks/:test_block_uuid/reruns",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controllers.HandleGetTestBlockSubmissionsReruns,
+	)
+
+	submissionsGroup.GET(
+		"/reruns/:rerun_uuid",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		controllers.HandleGetSubmissionsRerun,
+	)
+
+	submissionsGroup.GET(
+		"/dead_letters",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controllers.HandleGetSubmissionsDeadLetters,
+	)
+
+	submissionsGroup.POST(
+		"/dead_letters/:dead_letter_uuid/replay",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"admin"}),
+		controllers.HandleReplaySubmissionDeadLetter,
+	)
+
+	submissionsGroup.GET(
+		"/laboratories/:laboratory_uuid/status",
+		sharedInfrastructure.WithAuthenticationMiddleware(),
+		sharedInfrastructure.WithAuthorizationMiddleware([]string{"teacher"}),
+		sharedInfrastructure.WithServerSentEventsMiddleware(),
+		controllers.HandleGetLaboratorySubmissionsUpdates,
+	)
+
 	submissionsGroup.GET(
 		"/:submission_uuid/archive",
 		sharedInfrastructure.WithAuthenticationMiddleware(),
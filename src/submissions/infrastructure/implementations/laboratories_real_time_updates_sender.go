@@ -0,0 +1,71 @@
+package implementations
+
+import (
+	"log"
+	"sync"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/definitions"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+)
+
+// LaboratorySubscription to the real time updates of the submissions of a laboratory. The key of the
+// subscription is the laboratory UUID
+type LaboratorySubscription = sharedInfrastructure.UpdatesHubSubscription[*dtos.LaboratorySubmissionUpdateDTO]
+
+// LaboratoriesRealTimeUpdatesSender sends the status updates of the submissions of a laboratory
+// to the teachers watching the laboratory
+type LaboratoriesRealTimeUpdatesSender struct {
+	SubmissionsRepository definitions.SubmissionsRepository
+
+	// Subscriptions to the updates of each laboratory
+	hub *sharedInfrastructure.UpdatesHub[*dtos.LaboratorySubmissionUpdateDTO]
+}
+
+// Singleton instance
+var laboratoriesRealTimeUpdatesSenderInstance *LaboratoriesRealTimeUpdatesSender
+var laboratoriesRealTimeUpdatesSenderOnce sync.Once
+
+func GetLaboratoriesRealTimeUpdatesSenderInstance() *LaboratoriesRealTimeUpdatesSender {
+	laboratoriesRealTimeUpdatesSenderOnce.Do(func() {
+		laboratoriesRealTimeUpdatesSenderInstance = &LaboratoriesRealTimeUpdatesSender{
+			SubmissionsRepository: GetSubmissionsRepositoryInstance(),
+			hub: sharedInfrastructure.NewUpdatesHub[*dtos.LaboratorySubmissionUpdateDTO](
+				"Laboratories SSE",
+				subscriptionBufferSize,
+			),
+		}
+	})
+
+	return laboratoriesRealTimeUpdatesSenderInstance
+}
+
+// Subscribe creates a new subscription to the updates of the submissions of a laboratory
+func (sender *LaboratoriesRealTimeUpdatesSender) Subscribe(laboratoryUUID string) *LaboratorySubscription {
+	return sender.hub.Subscribe(laboratoryUUID)
+}
+
+// Unsubscribe cancels a subscription and closes its channel
+func (sender *LaboratoriesRealTimeUpdatesSender) Unsubscribe(subscription *LaboratorySubscription) {
+	sender.hub.Unsubscribe(subscription)
+}
+
+// SendSubmissionUpdate sends the current status of a submission and the progress of its student
+// to the subscribers of the laboratory the submission belongs to
+func (sender *LaboratoriesRealTimeUpdatesSender) SendSubmissionUpdate(submissionUUID string) {
+	// Avoid querying the database when no teacher is watching any laboratory
+	if !sender.hub.HasSubscriptions() {
+		return
+	}
+
+	update, err := sender.SubmissionsRepository.GetLaboratorySubmissionUpdate(submissionUUID)
+	if err != nil {
+		log.Println(
+			"[Laboratories SSE]: There was an error while getting the laboratory update of the submission",
+			err.Error(),
+		)
+		return
+	}
+
+	sender.hub.Publish(update.LaboratoryUUID, update)
+}
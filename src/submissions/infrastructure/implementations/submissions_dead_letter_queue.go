@@ -0,0 +1,165 @@
+package implementations
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/application"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
+	"github.com/lib/pq"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// SubmissionsDeadLetterQueueMgr consumes the submission works rejected by the runners or expired in the
+// submissions queue and saves them, so they can be inspected and replayed by the administrators
+type SubmissionsDeadLetterQueueMgr struct {
+	QueueName string
+}
+
+// Time to wait before consuming again when the dead letter queue could not be consumed in the current connection
+const submissionsDeadLetterRetryDelay = 5 * time.Second
+
+// Error code returned by PostgreSQL when the submission of the dead letter does not exist
+const foreignKeyViolationCode = "23503"
+
+// ## Singleton instance ##
+
+var submissionsDeadLetterQueueMgrInstance *SubmissionsDeadLetterQueueMgr
+var submissionsDeadLetterQueueMgrOnce sync.Once
+
+func GetSubmissionsDeadLetterQueueMgrInstance() *SubmissionsDeadLetterQueueMgr {
+	submissionsDeadLetterQueueMgrOnce.Do(func() {
+		submissionsDeadLetterQueueMgrInstance = &SubmissionsDeadLetterQueueMgr{
+			QueueName: sharedInfrastructure.RabbitMQSubmissionsDeadLetterQueueName,
+		}
+	})
+
+	return submissionsDeadLetterQueueMgrInstance
+}
+
+// ## Public methods ##
+
+// ListenForDeadLetters listens for the dead-lettered submission works. When the connection to RabbitMQ
+// is lost, it waits for the reconnection and starts consuming again
+func (queueMgr *SubmissionsDeadLetterQueueMgr) ListenForDeadLetters() {
+	connectionMgr := sharedInfrastructure.GetRabbitMQConnectionManager()
+
+	useCases := NewSubmissionUseCases()
+
+	for {
+		<-connectionMgr.WaitForConnection()
+
+		msgs, err := queueMgr.consume()
+		if err != nil {
+			log.Println(
+				"[RabbitMQ]: There was an error while consuming messages from the submissions dead letter queue",
+				err.Error(),
+			)
+
+			time.Sleep(submissionsDeadLetterRetryDelay)
+			continue
+		}
+
+		// Process until the channel is closed
+		log.Println("[RabbitMQ Submissions Dead Letter Queue]: Listening for dead letters...")
+		for msg := range msgs {
+			queueMgr.processDeadLetter(useCases, msg)
+		}
+
+		log.Println("[RabbitMQ Submissions Dead Letter Queue]: The consumer was stopped, waiting for the connection")
+	}
+}
+
+// ## Private methods ##
+
+// consume starts consuming the messages of the dead letter queue, declared along with the connection
+func (queueMgr *SubmissionsDeadLetterQueueMgr) consume() (<-chan amqp.Delivery, error) {
+	ch, err := sharedInfrastructure.GetRabbitMQChannel()
+	if err != nil {
+		return nil, err
+	}
+
+	qName := queueMgr.QueueName
+	qConsumer := ""
+	qAutoAck := false
+	qExclusive := false
+	qNoLocal := false
+	qNoWait := false
+	qArgs := amqp.Table{}
+
+	return ch.Consume(
+		qName,
+		qConsumer,
+		qAutoAck,
+		qExclusive,
+		qNoLocal,
+		qNoWait,
+		qArgs,
+	)
+}
+
+// processDeadLetter saves a single dead-lettered submission work. Messages that can not be saved because
+// they are not valid are dropped, as they could never be replayed
+func (queueMgr *SubmissionsDeadLetterQueueMgr) processDeadLetter(useCases *application.SubmissionUseCases, msg amqp.Delivery) {
+	work := entities.SubmissionWork{}
+	err := json.Unmarshal(msg.Body, &work)
+	if err != nil || work.SubmissionUUID == "" {
+		log.Println("[RabbitMQ Submissions Dead Letter Queue]: Dropping a message that is not a valid submission work")
+		msg.Ack(false)
+		return
+	}
+
+	reason := getDeadLetterReason(msg.Headers)
+	log.Printf(
+		"[RabbitMQ Submissions Dead Letter Queue]: The work of the submission %s was dead-lettered (%s)",
+		work.SubmissionUUID,
+		reason,
+	)
+
+	err = useCases.SaveSubmissionDeadLetter(&dtos.SaveSubmissionDeadLetterDTO{
+		SubmissionUUID: work.SubmissionUUID,
+		Payload:        string(msg.Body),
+		Reason:         reason,
+	})
+	if err != nil {
+		log.Println(
+			"[RabbitMQ Submissions Dead Letter Queue]: There was an error while saving the dead letter",
+			err.Error(),
+		)
+
+		// The submission was deleted, so there is nothing left to replay
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolationCode {
+			msg.Ack(false)
+			return
+		}
+
+		// Keep the message in the queue until it can be saved
+		time.Sleep(submissionsDeadLetterRetryDelay)
+		msg.Nack(false, true)
+		return
+	}
+
+	msg.Ack(false)
+}
+
+// getDeadLetterReason returns why RabbitMQ dead-lettered a message, according to its headers
+func getDeadLetterReason(headers amqp.Table) string {
+	if reason, ok := headers["x-first-death-reason"].(string); ok {
+		return reason
+	}
+
+	// Older versions of RabbitMQ only set the `x-death` header
+	if deaths, ok := headers["x-death"].([]interface{}); ok && len(deaths) > 0 {
+		if death, ok := deaths[0].(amqp.Table); ok {
+			if reason, ok := death["reason"].(string); ok {
+				return reason
+			}
+		}
+	}
+
+	return "unknown"
+}
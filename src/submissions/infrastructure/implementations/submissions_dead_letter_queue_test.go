@@ -0,0 +1,41 @@
+package implementations
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestGetDeadLetterReason(t *testing.T) {
+	cases := []struct {
+		name     string
+		headers  amqp.Table
+		expected string
+	}{
+		{
+			name:     "first death header",
+			headers:  amqp.Table{"x-first-death-reason": "expired"},
+			expected: "expired",
+		},
+		{
+			name: "death history",
+			headers: amqp.Table{
+				"x-death": []interface{}{
+					amqp.Table{"reason": "rejected", "queue": "submissions"},
+				},
+			},
+			expected: "rejected",
+		},
+		{
+			name:     "no headers",
+			headers:  nil,
+			expected: "unknown",
+		},
+	}
+
+	for _, testCase := range cases {
+		if reason := getDeadLetterReason(testCase.headers); reason != testCase.expected {
+			t.Errorf("%s: expected %q, got %q", testCase.name, testCase.expected, reason)
+		}
+	}
+}
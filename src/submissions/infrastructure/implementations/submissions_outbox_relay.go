@@ -0,0 +1,88 @@
+package implementations
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// Number of messages claimed from the outbox at once
+	submissionsOutboxBatchSize = 50
+
+	// Time between each run of the relay when it is not woken up
+	submissionsOutboxRelayInterval = 5 * time.Second
+
+	// Time the sent messages are kept in the outbox
+	submissionsOutboxRetention = 24 * time.Hour
+)
+
+// SubmissionsOutboxRelay publishes the submission works saved in the outbox to the submissions queue. The
+// messages are marked as sent only after the broker confirms them, so a message may be published twice
+// (e.g. the gateway crashed after publishing it) but it is never lost
+type SubmissionsOutboxRelay struct {
+	// Signals the relay to run without waiting for the next tick
+	wakeUp chan struct{}
+}
+
+// Singleton instance
+var submissionsOutboxRelayInstance *SubmissionsOutboxRelay
+var submissionsOutboxRelayOnce sync.Once
+
+func GetSubmissionsOutboxRelayInstance() *SubmissionsOutboxRelay {
+	submissionsOutboxRelayOnce.Do(func() {
+		submissionsOutboxRelayInstance = &SubmissionsOutboxRelay{
+			wakeUp: make(chan struct{}, 1),
+		}
+	})
+
+	return submissionsOutboxRelayInstance
+}
+
+// Wake asks the relay to publish the pending messages without waiting for its next run
+func (relay *SubmissionsOutboxRelay) Wake() {
+	select {
+	case relay.wakeUp <- struct{}{}:
+	default:
+		// The relay is already going to run
+	}
+}
+
+// Start publishes the pending messages of the outbox every time the relay is woken up or the interval elapses
+func (relay *SubmissionsOutboxRelay) Start() {
+	log.Println("[Submissions outbox]: Relaying the submission works to the queue")
+
+	useCases := NewSubmissionUseCases()
+
+	ticker := time.NewTicker(submissionsOutboxRelayInterval)
+	defer ticker.Stop()
+
+	cleanupTicker := time.NewTicker(time.Hour)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-relay.wakeUp:
+		case <-ticker.C:
+		case <-cleanupTicker.C:
+			err := useCases.SubmissionsRepository.DeleteSentSubmissionsOutboxMessages(submissionsOutboxRetention)
+			if err != nil {
+				log.Println("[Submissions outbox]: There was an error while removing the sent messages", err.Error())
+			}
+			continue
+		}
+
+		// Keep publishing while there are full batches waiting. Failed messages are retried in the next run
+		for {
+			publishedMessages, err := useCases.RelaySubmissionsOutbox(submissionsOutboxBatchSize)
+			if err != nil {
+				log.Println("[Submissions outbox]: There was an error while relaying the messages", err.Error())
+				break
+			}
+
+			if publishedMessages < submissionsOutboxBatchSize {
+				break
+			}
+		}
+	}
+}
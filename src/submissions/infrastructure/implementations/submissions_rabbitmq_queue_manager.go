@@ -2,8 +2,11 @@ package implementations
 
 import (
 	"context"
+	"log"
+	"strconv"
 	"time"
 
+	sharedErrors "github.com/UPB-Code-Labs/main-api/src/shared/domain/errors"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/errors"
@@ -11,7 +14,10 @@ import (
 )
 
 type SubmissionsRabbitMQQueueManager struct {
-	SubmissionsQueue *amqp.Queue
+	SubmissionsQueueName string
+
+	// Time a work can wait in the queue before being sent to the dead letter exchange, zero to wait forever
+	WorkTTL time.Duration
 }
 
 // Singleton
@@ -20,7 +26,8 @@ var submissionsRabbitMQQueueManagerInstance *SubmissionsRabbitMQQueueManager
 func GetSubmissionsRabbitMQQueueManagerInstance() *SubmissionsRabbitMQQueueManager {
 	if submissionsRabbitMQQueueManagerInstance == nil {
 		submissionsRabbitMQQueueManagerInstance = &SubmissionsRabbitMQQueueManager{
-			SubmissionsQueue: sharedInfrastructure.GetRabbitMQSubmissionsQueue(),
+			SubmissionsQueueName: sharedInfrastructure.RabbitMQSubmissionsQueueName,
+			WorkTTL:              time.Duration(sharedInfrastructure.GetEnvironment().SubmissionsWorkTtlSeconds) * time.Second,
 		}
 	}
 
@@ -28,37 +35,47 @@ func GetSubmissionsRabbitMQQueueManagerInstance() *SubmissionsRabbitMQQueueManag
 }
 
 // Methods implementation
+
+// QueueWork publishes a submission work to the submissions queue and waits for the broker to confirm it
 func (queueManager *SubmissionsRabbitMQQueueManager) QueueWork(work *entities.SubmissionWork) (err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	channel := sharedInfrastructure.GetRabbitMQChannel()
-
 	// Parse work to JSON
 	stringifiedWork, err := work.ToJSON()
 	if err != nil {
 		return err
 	}
 
-	// Publish work to queue
+	// Publish work to queue. The work is not queued if the broker does not confirm it or returns it because
+	// it could not be routed to the queue
 	msgExchange := ""
-	msgKey := queueManager.SubmissionsQueue.Name
-	msgMandatory := false
-	msgImmediate := false
+	msgKey := queueManager.SubmissionsQueueName
+
+	msgExpiration := ""
+	if queueManager.WorkTTL > 0 {
+		msgExpiration = strconv.FormatInt(queueManager.WorkTTL.Milliseconds(), 10)
+	}
 
-	err = channel.PublishWithContext(
+	err = sharedInfrastructure.PublishRabbitMQMandatoryMessage(
 		ctx,
 		msgExchange,
 		msgKey,
-		msgMandatory,
-		msgImmediate,
 		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        []byte(stringifiedWork),
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Expiration:   msgExpiration,
+			Body:         []byte(stringifiedWork),
 		},
 	)
 
+	// Fail fast while the connection to the broker is being recovered
+	if _, ok := err.(sharedErrors.BrokerUnavailableError); ok {
+		return err
+	}
+
 	if err != nil {
+		log.Println("[RabbitMQ]: Unable to queue the submission work:", err.Error())
 		return errors.UnableToQueueSubmissionWork{}
 	}
 
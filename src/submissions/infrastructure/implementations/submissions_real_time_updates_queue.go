@@ -3,17 +3,26 @@ package implementations
 import (
 	"encoding/json"
 	"log"
+	"time"
 
+	gradesApplication "github.com/UPB-Code-Labs/main-api/src/grades/application"
+	gradesImplementations "github.com/UPB-Code-Labs/main-api/src/grades/infrastructure/implementations"
+	laboratoriesImplementations "github.com/UPB-Code-Labs/main-api/src/laboratories/infrastructure/implementations"
+	rubricsImplementations "github.com/UPB-Code-Labs/main-api/src/rubrics/infrastructure/implementations"
 	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 type SubmissionsRealTimeUpdatesQueueMgr struct {
-	Queue   *amqp.Queue
-	Channel <-chan amqp.Delivery
+	// Queue declared in the current connection, the queue is exclusive so it is declared again after each reconnection
+	Queue *amqp.Queue
 }
 
+// Time to wait before consuming again when the queue could not be declared in the current connection
+const submissionsRealTimeUpdatesRetryDelay = 5 * time.Second
+
 // ## Singleton instance ##
 
 var submissionsRealTimeUpdatesQueueMgrInstance *SubmissionsRealTimeUpdatesQueueMgr
@@ -21,11 +30,8 @@ var submissionsRealTimeUpdatesQueueMgrInstance *SubmissionsRealTimeUpdatesQueueM
 func GetSubmissionsRealTimeUpdatesQueueMgrInstance() *SubmissionsRealTimeUpdatesQueueMgr {
 	if submissionsRealTimeUpdatesQueueMgrInstance == nil {
 		submissionsRealTimeUpdatesQueueMgrInstance = &SubmissionsRealTimeUpdatesQueueMgr{
-			Queue: getSubmissionsRealTimeUpdatesQueue(),
-			// Channel will be set when ListenForUpdates is called
+			// Queue will be set when ListenForUpdates is called
 		}
-
-		attachSubmissionsRealTimeUpdatesQueueToExchange()
 	}
 
 	return submissionsRealTimeUpdatesQueueMgrInstance
@@ -33,12 +39,54 @@ func GetSubmissionsRealTimeUpdatesQueueMgrInstance() *SubmissionsRealTimeUpdates
 
 // ## Public methods ##
 
-// ListenForUpdates listens for updates in the submission real time updates queue
+// ListenForUpdates listens for updates in the submission real time updates queue. When the connection
+// to RabbitMQ is lost, it waits for the reconnection and starts consuming again
 func (queueMgr *SubmissionsRealTimeUpdatesQueueMgr) ListenForUpdates() {
-	ch := sharedInfrastructure.GetRabbitMQChannel()
+	connectionMgr := sharedInfrastructure.GetRabbitMQConnectionManager()
+
+	for {
+		<-connectionMgr.WaitForConnection()
+
+		msgs, err := queueMgr.consume()
+		if err != nil {
+			log.Println(
+				"[RabbitMQ]: There was an error while consuming messages from the submission real time updates queue",
+				err.Error(),
+			)
+
+			time.Sleep(submissionsRealTimeUpdatesRetryDelay)
+			continue
+		}
+
+		// Process until the channel is closed
+		queueMgr.processUpdates(msgs)
+		log.Println("[RabbitMQ Submissions Real Time Updates Queue]: The consumer was stopped, waiting for the connection")
+	}
+}
+
+// ## Private methods ##
+
+// consume declares the submissions real time updates queue in the current connection, binds it to the
+// submissions exchange and starts consuming its messages
+func (queueMgr *SubmissionsRealTimeUpdatesQueueMgr) consume() (<-chan amqp.Delivery, error) {
+	ch, err := sharedInfrastructure.GetRabbitMQChannel()
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := declareSubmissionsRealTimeUpdatesQueue(ch)
+	if err != nil {
+		return nil, err
+	}
+	queueMgr.Queue = q
+
+	err = attachSubmissionsRealTimeUpdatesQueueToExchange(ch, q)
+	if err != nil {
+		return nil, err
+	}
 
 	// Consume messages
-	qName := queueMgr.Queue.Name
+	qName := q.Name
 	qConsumer := ""
 	qAutoAck := false
 	qExclusive := false
@@ -46,7 +94,7 @@ func (queueMgr *SubmissionsRealTimeUpdatesQueueMgr) ListenForUpdates() {
 	qNoWait := false
 	qArgs := amqp.Table{}
 
-	msgs, err := ch.Consume(
+	return ch.Consume(
 		qName,
 		qConsumer,
 		qAutoAck,
@@ -55,82 +103,49 @@ func (queueMgr *SubmissionsRealTimeUpdatesQueueMgr) ListenForUpdates() {
 		qNoWait,
 		qArgs,
 	)
-
-	if err != nil {
-		log.Fatal(
-			"[RabbitMQ]: There was an error while consuming messages from the submission real time updates queue",
-			err.Error(),
-		)
-	}
-
-	// Set the channel
-	queueMgr.Channel = msgs
-
-	// Process
-	queueMgr.processUpdates()
 }
 
-// ## Private methods ##
-
-// getSubmissionsRealTimeUpdatesQueue returns the submissions real time updates queue
-func getSubmissionsRealTimeUpdatesQueue() *amqp.Queue {
-	noQueueHasBeenDeclared := submissionsRealTimeUpdatesQueueMgrInstance == nil ||
-		submissionsRealTimeUpdatesQueueMgrInstance.Queue == nil
-
-	if noQueueHasBeenDeclared {
-		ch := sharedInfrastructure.GetRabbitMQChannel()
-
-		// Declare queue
-		qName := "" // RabbitMQ will generate a random name
-		qDurable := false
-		qAutoDelete := false
-		qExclusive := true // The queue will be deleted when the instance of the gateway is closed
-		qNoWait := false
-		qArgs := amqp.Table{}
-
-		q, err := ch.QueueDeclare(
-			qName,
-			qDurable,
-			qAutoDelete,
-			qExclusive,
-			qNoWait,
-			qArgs,
-		)
-
-		if err != nil {
-			log.Fatal(
-				"[RabbitMQ]: There was an error while declaring the submission real time updates queue",
-				err.Error(),
-			)
-		}
+// declareSubmissionsRealTimeUpdatesQueue declares the submissions real time updates queue
+func declareSubmissionsRealTimeUpdatesQueue(ch *amqp.Channel) (*amqp.Queue, error) {
+	// Declare queue
+	qName := "" // RabbitMQ will generate a random name
+	qDurable := false
+	qAutoDelete := false
+	qExclusive := true // The queue will be deleted when the instance of the gateway is closed
+	qNoWait := false
+	qArgs := amqp.Table{}
 
-		// Set fair dispatch
-		maxPrefetchCount := 4 // Limit to 4 updates per worker
-		err = ch.Qos(
-			maxPrefetchCount,
-			0,
-			false,
-		)
-		if err != nil {
-			log.Fatal(
-				"[RabbitMQ]: There was an error while setting the fair dispatch for the submission real time updates queue",
-				err.Error(),
-			)
-		}
+	q, err := ch.QueueDeclare(
+		qName,
+		qDurable,
+		qAutoDelete,
+		qExclusive,
+		qNoWait,
+		qArgs,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		log.Println("[RabbitMQ]: Submission real time updates queue declared")
-		return &q
+	// Set fair dispatch
+	maxPrefetchCount := 4 // Limit to 4 updates per worker
+	err = ch.Qos(
+		maxPrefetchCount,
+		0,
+		false,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return submissionsRealTimeUpdatesQueueMgrInstance.Queue
+	log.Println("[RabbitMQ]: Submission real time updates queue declared")
+	return &q, nil
 }
 
 // attachSubmissionsRealTimeUpdatesQueueToExchange attaches the submissions real time updates queue to the submissions exchange
-func attachSubmissionsRealTimeUpdatesQueueToExchange() {
-	ch := sharedInfrastructure.GetRabbitMQChannel()
-
+func attachSubmissionsRealTimeUpdatesQueueToExchange(ch *amqp.Channel, q *amqp.Queue) error {
 	// Bind queue to exchange
-	qName := getSubmissionsRealTimeUpdatesQueue().Name
+	qName := q.Name
 	routingKey := ""
 	exchangeName := "submissions-real-time-updates"
 	noWait := false
@@ -143,22 +158,19 @@ func attachSubmissionsRealTimeUpdatesQueueToExchange() {
 		noWait,
 		args,
 	)
-
 	if err != nil {
-		log.Fatal(
-			"[RabbitMQ]: There was an error while binding the submission real time updates queue to the submissions exchange",
-			err.Error(),
-		)
+		return err
 	}
 
 	log.Println("[RabbitMQ]: Submission real time updates queue bound to submissions exchange")
+	return nil
 }
 
 // processUpdates processes the updates received from the submission real time updates queue
-func (queueMgr *SubmissionsRealTimeUpdatesQueueMgr) processUpdates() {
+func (queueMgr *SubmissionsRealTimeUpdatesQueueMgr) processUpdates(msgs <-chan amqp.Delivery) {
 	log.Println("[RabbitMQ Submissions Real Time Updates Queue]: Listening for updates...")
 
-	for msg := range queueMgr.Channel {
+	for msg := range msgs {
 		go queueMgr.processUpdate(msg)
 	}
 }
@@ -179,7 +191,41 @@ func (queueMgr *SubmissionsRealTimeUpdatesQueueMgr) processUpdate(msg amqp.Deliv
 		return
 	}
 
+	// Persist the results of the test cases (if any)
+	if len(dto.TestResults) > 0 {
+		err = GetSubmissionsRepositoryInstance().SaveSubmissionTestResults(dto.SubmissionUUID, dto.TestResults)
+		if err != nil {
+			log.Println(
+				"[RabbitMQ Submissions Real Time Updates Queue]: There was an error while saving the submission test results",
+				err.Error(),
+			)
+		}
+	} else {
+		dto.TestResults = []*entities.SubmissionTestResult{}
+	}
+
+	// Select the criteria linked to the test block once the submission is ready
+	if dto.SubmissionStatus == "ready" {
+		gradesUseCases := gradesApplication.GradesUseCases{
+			GradesRepository:       gradesImplementations.GetGradesPostgresRepositoryInstance(),
+			LaboratoriesRepository: laboratoriesImplementations.GetLaboratoriesPostgresRepositoryInstance(),
+			RubricsRepository:      rubricsImplementations.GetRubricsPgRepository(),
+		}
+
+		err = gradesUseCases.ApplyAutomaticGrading(dto.SubmissionUUID)
+		if err != nil {
+			log.Println(
+				"[RabbitMQ Submissions Real Time Updates Queue]: There was an error while grading the submission",
+				err.Error(),
+			)
+		}
+	}
+
 	// Send the update to the real time updates sender
 	realTimeUpdater := GetSubmissionsRealTimeUpdatesSenderInstance()
 	realTimeUpdater.SendUpdate(&dto)
+
+	// Send the update to the teachers watching the laboratory
+	laboratoriesRealTimeUpdater := GetLaboratoriesRealTimeUpdatesSenderInstance()
+	laboratoriesRealTimeUpdater.SendSubmissionUpdate(dto.SubmissionUUID)
 }
@@ -2,67 +2,65 @@ package implementations
 
 import (
 	"log"
+	"sync"
 
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
 )
 
+// Number of updates a subscriber can have pending before being considered a slow consumer
+const subscriptionBufferSize = 16
+
 type UpdatesChannel chan *dtos.SubmissionStatusUpdateDTO
 
+// Subscription to the real time updates of a submission. The key of the subscription is the submission UUID
+type Subscription = sharedInfrastructure.UpdatesHubSubscription[*dtos.SubmissionStatusUpdateDTO]
+
 type SubmissionsRealTimeUpdatesSender struct {
 	// New updates are pushed to this channel
 	Updates UpdatesChannel
 
-	// All the open connections
-	OpenConnections map[string]UpdatesChannel
+	// Subscriptions to the updates of each submission
+	hub *sharedInfrastructure.UpdatesHub[*dtos.SubmissionStatusUpdateDTO]
 }
 
 // Singleton instance
 var submissionsRealTimeUpdatesSenderInstance *SubmissionsRealTimeUpdatesSender
+var submissionsRealTimeUpdatesSenderOnce sync.Once
 
 func GetSubmissionsRealTimeUpdatesSenderInstance() *SubmissionsRealTimeUpdatesSender {
-	if submissionsRealTimeUpdatesSenderInstance == nil {
-		submissionsRealTimeUpdatesSenderInstance = &SubmissionsRealTimeUpdatesSender{
-			Updates:         make(UpdatesChannel),
-			OpenConnections: make(map[string]UpdatesChannel),
-		}
-	}
+	submissionsRealTimeUpdatesSenderOnce.Do(func() {
+		submissionsRealTimeUpdatesSenderInstance = NewSubmissionsRealTimeUpdatesSender()
+	})
 
 	return submissionsRealTimeUpdatesSenderInstance
 }
 
-// Create a channel for a submission
-func (sender *SubmissionsRealTimeUpdatesSender) CreateChannel(submissionUUID string) *UpdatesChannel {
-	currentChannel, channelExists := sender.OpenConnections[submissionUUID]
-	if channelExists {
-		return &currentChannel
+// NewSubmissionsRealTimeUpdatesSender creates a sender without subscriptions
+func NewSubmissionsRealTimeUpdatesSender() *SubmissionsRealTimeUpdatesSender {
+	return &SubmissionsRealTimeUpdatesSender{
+		Updates: make(UpdatesChannel),
+		hub: sharedInfrastructure.NewUpdatesHub[*dtos.SubmissionStatusUpdateDTO](
+			"SSE",
+			subscriptionBufferSize,
+		),
 	}
-
-	newChannel := make(UpdatesChannel)
-	sender.OpenConnections[submissionUUID] = newChannel
-
-	log.Printf(
-		"Created a new channel for submission %s. Total channels: %d",
-		submissionUUID,
-		len(sender.OpenConnections),
-	)
-	return &newChannel
 }
 
-// Delete a channel for a submission
-func (sender *SubmissionsRealTimeUpdatesSender) DeleteChannel(submissionUUID string) {
-	_, channelExists := sender.OpenConnections[submissionUUID]
-	if !channelExists {
-		return
-	}
+// Subscribe creates a new subscription to the updates of a submission
+func (sender *SubmissionsRealTimeUpdatesSender) Subscribe(submissionUUID string) *Subscription {
+	return sender.hub.Subscribe(submissionUUID)
+}
 
-	close(sender.OpenConnections[submissionUUID])
-	delete(sender.OpenConnections, submissionUUID)
+// Unsubscribe cancels a subscription and closes its channel. Calling it more than once
+// or after the subscriber was dropped has no effect
+func (sender *SubmissionsRealTimeUpdatesSender) Unsubscribe(subscription *Subscription) {
+	sender.hub.Unsubscribe(subscription)
+}
 
-	log.Printf(
-		"Deleted channel for submission %s. Total channels: %d",
-		submissionUUID,
-		len(sender.OpenConnections),
-	)
+// SubscriptionsCount returns the number of open subscriptions to a submission
+func (sender *SubmissionsRealTimeUpdatesSender) SubscriptionsCount(submissionUUID string) int {
+	return sender.hub.SubscriptionsCount(submissionUUID)
 }
 
 // SendUpdate sends an update to the updates channel
@@ -70,7 +68,8 @@ func (sender *SubmissionsRealTimeUpdatesSender) SendUpdate(update *dtos.Submissi
 	sender.Updates <- update
 }
 
-// listen listens for new updates and new connections
+// Listen listens for new updates and sends them to the subscribers of the submission without
+// blocking. Subscribers that are too slow to consume the updates are dropped
 func (sender *SubmissionsRealTimeUpdatesSender) Listen() {
 	log.Println("[SSE]: Listening for new updates")
 
@@ -81,14 +80,6 @@ func (sender *SubmissionsRealTimeUpdatesSender) Listen() {
 			update.SubmissionStatus,
 		)
 
-		// Send the update to the corresponding channel
-		ch, chExists := sender.OpenConnections[update.SubmissionUUID]
-		if chExists {
-			ch <- update
-			log.Printf(
-				"Sent update to submission %s",
-				update.SubmissionUUID,
-			)
-		}
+		sender.hub.Publish(update.SubmissionUUID, update)
 	}
 }
@@ -0,0 +1,227 @@
+package implementations
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+)
+
+const testTimeout = 2 * time.Second
+
+func startTestSender(t *testing.T) *SubmissionsRealTimeUpdatesSender {
+	sender := NewSubmissionsRealTimeUpdatesSender()
+	go sender.Listen()
+	t.Cleanup(func() {
+		close(sender.Updates)
+	})
+
+	return sender
+}
+
+func receiveUpdate(t *testing.T, subscription *Subscription) *dtos.SubmissionStatusUpdateDTO {
+	t.Helper()
+
+	select {
+	case update, ok := <-subscription.Updates:
+		if !ok {
+			t.Fatalf("the subscription to %s was closed", subscription.Key)
+		}
+		return update
+	case <-time.After(testTimeout):
+		t.Fatalf("no update received for %s", subscription.Key)
+		return nil
+	}
+}
+
+func TestMultipleSubscribersReceiveUpdates(t *testing.T) {
+	sender := startTestSender(t)
+
+	firstTab := sender.Subscribe("submission")
+	secondTab := sender.Subscribe("submission")
+	otherSubmission := sender.Subscribe("other-submission")
+
+	sender.SendUpdate(&dtos.SubmissionStatusUpdateDTO{
+		SubmissionUUID:   "submission",
+		SubmissionStatus: "running",
+	})
+
+	for _, subscription := range []*Subscription{firstTab, secondTab} {
+		update := receiveUpdate(t, subscription)
+		if update.SubmissionStatus != "running" {
+			t.Errorf("expected status running, got %s", update.SubmissionStatus)
+		}
+	}
+
+	select {
+	case update := <-otherSubmission.Updates:
+		t.Errorf("unexpected update for another submission: %v", update)
+	default:
+	}
+}
+
+func TestUnsubscribeKeepsOtherSubscribers(t *testing.T) {
+	sender := startTestSender(t)
+
+	closedTab := sender.Subscribe("submission")
+	openTab := sender.Subscribe("submission")
+
+	sender.Unsubscribe(closedTab)
+	sender.Unsubscribe(closedTab)
+
+	if _, ok := <-closedTab.Updates; ok {
+		t.Fatal("expected the channel of the cancelled subscription to be closed")
+	}
+
+	if count := sender.SubscriptionsCount("submission"); count != 1 {
+		t.Fatalf("expected 1 subscription, got %d", count)
+	}
+
+	sender.SendUpdate(&dtos.SubmissionStatusUpdateDTO{
+		SubmissionUUID:   "submission",
+		SubmissionStatus: "ready",
+	})
+
+	update := receiveUpdate(t, openTab)
+	if update.SubmissionStatus != "ready" {
+		t.Errorf("expected status ready, got %s", update.SubmissionStatus)
+	}
+
+	sender.Unsubscribe(openTab)
+	if count := sender.SubscriptionsCount("submission"); count != 0 {
+		t.Fatalf("expected 0 subscriptions, got %d", count)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	sender := startTestSender(t)
+
+	slowTab := sender.Subscribe("submission")
+	fastTab := sender.Subscribe("submission")
+
+	// Send more updates than the slow subscriber can buffer while the fast subscriber
+	// reads each one of them. Sending must not block
+	totalUpdates := subscriptionBufferSize * 2
+	fastUpdates := 0
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+
+		for i := 0; i < totalUpdates; i++ {
+			sender.SendUpdate(&dtos.SubmissionStatusUpdateDTO{
+				SubmissionUUID:   "submission",
+				SubmissionStatus: fmt.Sprintf("update-%d", i),
+			})
+
+			select {
+			case _, ok := <-fastTab.Updates:
+				if !ok {
+					return
+				}
+				fastUpdates++
+			case <-time.After(testTimeout):
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-sent:
+	case <-time.After(testTimeout):
+		t.Fatal("sending updates was blocked by a slow subscriber")
+	}
+
+	// The slow subscriber receives the buffered updates and then its channel is closed
+	bufferedUpdates := 0
+	for range slowTab.Updates {
+		bufferedUpdates++
+	}
+
+	if bufferedUpdates != subscriptionBufferSize {
+		t.Errorf("expected %d buffered updates, got %d", subscriptionBufferSize, bufferedUpdates)
+	}
+
+	// The fast subscriber is not affected
+	if fastUpdates != totalUpdates {
+		t.Errorf("expected %d updates in the fast subscriber, got %d", totalUpdates, fastUpdates)
+	}
+
+	if count := sender.SubscriptionsCount("submission"); count != 1 {
+		t.Errorf("expected 1 subscription, got %d", count)
+	}
+}
+
+func TestConcurrentSubscribeUnsubscribeAndSend(t *testing.T) {
+	sender := startTestSender(t)
+
+	const submissions = 4
+	const subscribersPerSubmission = 8
+	const updatesPerSubmission = 32
+
+	var wg sync.WaitGroup
+
+	// Subscribers that come and go while the updates are being sent
+	for i := 0; i < submissions*subscribersPerSubmission; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			submissionUUID := fmt.Sprintf("submission-%d", i%submissions)
+			for j := 0; j < 4; j++ {
+				subscription := sender.Subscribe(submissionUUID)
+
+				// Read a few updates (if any) before leaving
+				timeout := time.After(5 * time.Millisecond)
+			read:
+				for {
+					select {
+					case _, ok := <-subscription.Updates:
+						if !ok {
+							break read
+						}
+					case <-timeout:
+						break read
+					}
+				}
+
+				sender.Unsubscribe(subscription)
+			}
+		}(i)
+	}
+
+	// Senders
+	for i := 0; i < submissions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < updatesPerSubmission; j++ {
+				sender.SendUpdate(&dtos.SubmissionStatusUpdateDTO{
+					SubmissionUUID:   fmt.Sprintf("submission-%d", i),
+					SubmissionStatus: "running",
+				})
+			}
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * testTimeout):
+		t.Fatal("concurrent subscribers and senders did not finish")
+	}
+
+	for i := 0; i < submissions; i++ {
+		submissionUUID := fmt.Sprintf("submission-%d", i)
+		if count := sender.SubscriptionsCount(submissionUUID); count != 0 {
+			t.Errorf("expected 0 subscriptions to %s, got %d", submissionUUID, count)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
 	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/errors"
+	"github.com/lib/pq"
 )
 
 type SubmissionsRepositoryImpl struct {
@@ -37,12 +38,13 @@ func (repository *SubmissionsRepositoryImpl) SaveSubmission(dto *dtos.CreateSubm
 	if err != nil {
 		return "", err
 	}
+	defer tx.Rollback()
 
 	// Create an entry in the archives table
 	var dbArchiveUUID string
 	query := `
-		INSERT INTO archives (file_id)
-		VALUES ($1)
+		INSERT INTO archives (file_id, archive_type)
+		VALUES ($1, 'submission')
 		RETURNING id
 	`
 
@@ -54,18 +56,24 @@ func (repository *SubmissionsRepositoryImpl) SaveSubmission(dto *dtos.CreateSubm
 	// Create an entry in the submissions table
 	var dbSubmissionUUID string
 	query = `
-		INSERT INTO submissions (student_id, test_block_id, archive_id)
-		VALUES ($1, $2, $3)
+		INSERT INTO submissions (student_id, test_block_id, archive_id, late_minutes)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id
 	`
 
 	err = tx.QueryRowContext(
-		ctx, query, dto.StudentUUID, dto.TestBlockUUID, dbArchiveUUID,
+		ctx, query, dto.StudentUUID, dto.TestBlockUUID, dbArchiveUUID, dto.LateMinutes,
 	).Scan(&dbSubmissionUUID)
 	if err != nil {
 		return "", err
 	}
 
+	// Save the work of the submission in the outbox, it will be published to the queue by the relay
+	err = saveSubmissionWorkInOutbox(ctx, tx, dbSubmissionUUID)
+	if err != nil {
+		return "", err
+	}
+
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -75,37 +83,37 @@ func (repository *SubmissionsRepositoryImpl) SaveSubmission(dto *dtos.CreateSubm
 	return dbSubmissionUUID, nil
 }
 
-func (repository *SubmissionsRepositoryImpl) ResetSubmissionStatus(submissionUUID string) (err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
-
-	DEFAULT_PASSING_VALUE := false
-	DEFAULT_STATUS_VALUE := "pending"
-	DEFAULT_STDOUT_VALUE := ""
-
+// saveSubmissionWorkInOutbox saves the current work of a submission in the outbox as part of the given transaction
+func saveSubmissionWorkInOutbox(ctx context.Context, tx *sql.Tx, submissionUUID string) error {
 	query := `
-		UPDATE submissions
-		SET
-			passing = $1,
-			status = $2,
-			stdout = $3,
-			submitted_at = CURRENT_TIMESTAMP
-		WHERE id = $4
+		SELECT
+			submission_id, language_file_id, test_file_id, submission_file_id,
+			cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
+		FROM submissions_work_metadata
+		WHERE submission_id = $1
 	`
 
-	_, err = repository.Connection.ExecContext(
-		ctx,
-		query,
-		DEFAULT_PASSING_VALUE,
-		DEFAULT_STATUS_VALUE,
-		DEFAULT_STDOUT_VALUE,
-		submissionUUID,
+	work := entities.SubmissionWork{}
+	err := tx.QueryRowContext(ctx, query, submissionUUID).Scan(
+		&work.SubmissionUUID, &work.LanguageUUID, &work.TestArchiveUUID, &work.SubmissionArchiveUUID,
+		&work.ExecutionLimits.CPUTimeMs, &work.ExecutionLimits.WallTimeMs, &work.ExecutionLimits.MemoryMb, &work.ExecutionLimits.OutputSizeKb,
 	)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	stringifiedWork, err := work.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO submissions_outbox (submission_id, payload)
+		VALUES ($1, $2)
+	`
+
+	_, err = tx.ExecContext(ctx, query, submissionUUID, stringifiedWork)
+	return err
 }
 
 func (repository *SubmissionsRepositoryImpl) GetStudentSubmission(studentUUID string, testBlockUUID string) (submission *entities.Submission, err error) {
@@ -113,9 +121,11 @@ func (repository *SubmissionsRepositoryImpl) GetStudentSubmission(studentUUID st
 	defer cancel()
 
 	query := `
-		SELECT id, archive_id, passing, status, stdout, submitted_at
+		SELECT id, archive_id, passing, status, stdout, submitted_at, late_minutes
 		FROM submissions
 		WHERE student_id = $1 AND test_block_id = $2
+		ORDER BY submitted_at DESC
+		LIMIT 1
 	`
 
 	submission = &entities.Submission{}
@@ -129,6 +139,7 @@ func (repository *SubmissionsRepositoryImpl) GetStudentSubmission(studentUUID st
 		&submission.Status,
 		&submission.Stdout,
 		&submission.SubmittedAt,
+		&submission.LateMinutes,
 	)
 
 	if err != nil {
@@ -142,12 +153,155 @@ func (repository *SubmissionsRepositoryImpl) GetStudentSubmission(studentUUID st
 	return submission, nil
 }
 
+// GetStudentSubmissionsHistory returns all the submissions (attempts) of a student in a test block, from the newest to the oldest
+func (repository *SubmissionsRepositoryImpl) GetStudentSubmissionsHistory(studentUUID string, testBlockUUID string) (submissions []*entities.Submission, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id, archive_id, passing, status, stdout, submitted_at, late_minutes
+		FROM submissions
+		WHERE student_id = $1 AND test_block_id = $2
+		ORDER BY submitted_at DESC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, studentUUID, testBlockUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submissions = []*entities.Submission{}
+	for rows.Next() {
+		submission := &entities.Submission{}
+
+		if err := rows.Scan(
+			&submission.UUID,
+			&submission.ArchiveUUID,
+			&submission.Passing,
+			&submission.Status,
+			&submission.Stdout,
+			&submission.SubmittedAt,
+			&submission.LateMinutes,
+		); err != nil {
+			return nil, err
+		}
+
+		submissions = append(submissions, submission)
+	}
+
+	return submissions, nil
+}
+
+// SaveSubmissionTestResults replaces the results of the test cases of a submission
+func (repository *SubmissionsRepositoryImpl) SaveSubmissionTestResults(submissionUUID string, results []*entities.SubmissionTestResult) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Start the transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Remove the previous results (if any) so the updates are idempotent
+	query := `
+		DELETE FROM submission_test_results
+		WHERE submission_id = $1
+	`
+
+	_, err = tx.ExecContext(ctx, query, submissionUUID)
+	if err != nil {
+		return err
+	}
+
+	// Insert the new results. The statuses are normalized in place, so the update sent to the students
+	// matches the saved results
+	query = `
+		INSERT INTO submission_test_results (submission_id, position, name, status, duration_ms, failure_message)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for position, result := range results {
+		result.NormalizeStatus()
+
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			submissionUUID,
+			position,
+			result.Name,
+			result.Status,
+			result.DurationMs,
+			result.FailureMessage,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
+
+// deleteSubmissionsTestResults removes the results of the test cases of the given submissions as part of the
+// given transaction, so the results of a previous evaluation are not shown while they are evaluated again
+func deleteSubmissionsTestResults(ctx context.Context, tx *sql.Tx, submissionsUUIDs []string) error {
+	query := `
+		DELETE FROM submission_test_results
+		WHERE submission_id = ANY($1)
+	`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(submissionsUUIDs))
+	return err
+}
+
+// GetSubmissionTestResults returns the results of the test cases of a submission
+func (repository *SubmissionsRepositoryImpl) GetSubmissionTestResults(submissionUUID string) (results []*entities.SubmissionTestResult, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT name, status, duration_ms, failure_message
+		FROM submission_test_results
+		WHERE submission_id = $1
+		ORDER BY position ASC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, submissionUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results = []*entities.SubmissionTestResult{}
+	for rows.Next() {
+		result := &entities.SubmissionTestResult{}
+
+		if err := rows.Scan(
+			&result.Name,
+			&result.Status,
+			&result.DurationMs,
+			&result.FailureMessage,
+		); err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
 func (repository *SubmissionsRepositoryImpl) GetSubmissionWorkMetadata(submissionUUID string) (submissionWorkMetadata *entities.SubmissionWork, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	query := `
-		SELECT submission_id, language_file_id, test_file_id, submission_file_id
+		SELECT
+			submission_id, language_file_id, test_file_id, submission_file_id,
+			cpu_time_limit_ms, wall_time_limit_ms, memory_limit_mb, output_size_limit_kb
 		FROM submissions_work_metadata
 		WHERE submission_id = $1
 	`
@@ -158,6 +312,7 @@ func (repository *SubmissionsRepositoryImpl) GetSubmissionWorkMetadata(submissio
 		ctx, query, submissionUUID,
 	).Scan(
 		&submissionWorkMetadata.SubmissionUUID, &submissionWorkMetadata.LanguageUUID, &submissionWorkMetadata.TestArchiveUUID, &submissionWorkMetadata.SubmissionArchiveUUID,
+		&submissionWorkMetadata.ExecutionLimits.CPUTimeMs, &submissionWorkMetadata.ExecutionLimits.WallTimeMs, &submissionWorkMetadata.ExecutionLimits.MemoryMb, &submissionWorkMetadata.ExecutionLimits.OutputSizeKb,
 	)
 
 	if err != nil {
@@ -222,6 +377,56 @@ func (repository *SubmissionsRepositoryImpl) DoesStudentOwnSubmission(studentUUI
 	return isOwner, nil
 }
 
+// GetLaboratorySubmissionUpdate returns the current status of a submission along with the progress
+// of the student in the laboratory the submission belongs to
+func (repository *SubmissionsRepositoryImpl) GetLaboratorySubmissionUpdate(submissionUUID string) (update *dtos.LaboratorySubmissionUpdateDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			tb.laboratory_id, s.id, s.test_block_id, s.status, s.passing,
+			u.id, u.full_name,
+			COALESCE(spv.pending_submissions, 0),
+			COALESCE(spv.running_submissions, 0),
+			COALESCE(spv.failing_submissions, 0),
+			COALESCE(spv.success_submissions, 0)
+		FROM submissions AS s
+		INNER JOIN test_blocks AS tb ON s.test_block_id = tb.id
+		INNER JOIN users AS u ON s.student_id = u.id
+		LEFT JOIN students_progress_view AS spv ON spv.student_id = s.student_id AND spv.laboratory_id = tb.laboratory_id
+		WHERE s.id = $1
+	`
+
+	update = &dtos.LaboratorySubmissionUpdateDTO{}
+
+	err = repository.Connection.QueryRowContext(
+		ctx, query, submissionUUID,
+	).Scan(
+		&update.LaboratoryUUID,
+		&update.SubmissionUUID,
+		&update.TestBlockUUID,
+		&update.SubmissionStatus,
+		&update.TestsPassed,
+		&update.StudentProgress.StudentUUID,
+		&update.StudentProgress.StudentFullName,
+		&update.StudentProgress.PendingSubmissions,
+		&update.StudentProgress.RunningSubmissions,
+		&update.StudentProgress.FailingSubmissions,
+		&update.StudentProgress.SuccessSubmissions,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.StudentSubmissionNotFound{}
+		}
+
+		return nil, err
+	}
+
+	return update, nil
+}
+
 // GetTeacherOfCourseBySubmissionUUID returns the teacher of the course that the submission belongs to
 func (repository *SubmissionsRepositoryImpl) GetTeacherOfCourseBySubmissionUUID(submissionUUID string) (teacherUUID string, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
@@ -255,3 +460,525 @@ func (repository *SubmissionsRepositoryImpl) GetTeacherOfCourseBySubmissionUUID(
 
 	return teacherUUID, nil
 }
+
+// CreateSubmissionsRerun resets the status of the latest (ready or timed out) submissions of a test block and
+// saves them as a new batch. Submissions that are still pending or running are not included, as they will be
+// evaluated against the current tests anyway
+func (repository *SubmissionsRepositoryImpl) CreateSubmissionsRerun(dto *dtos.RerunTestBlockSubmissionsDTO) (rerunUUID string, submissionsUUIDs []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Start the transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback()
+
+	// Save the batch
+	query := `
+		INSERT INTO submissions_reruns (test_block_id, teacher_id, trigger)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	err = tx.QueryRowContext(ctx, query, dto.TestBlockUUID, dto.TeacherUUID, dto.Trigger).Scan(&rerunUUID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Reset the status of the submissions
+	query = `
+		UPDATE submissions
+		SET status = 'pending', requeue_attempts = 0
+		WHERE id IN (
+			SELECT id
+			FROM latest_submissions
+			WHERE test_block_id = $1 AND status IN ('ready', 'timed_out')
+		)
+		RETURNING id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, dto.TestBlockUUID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	submissionsUUIDs = []string{}
+	for rows.Next() {
+		var submissionUUID string
+		if err := rows.Scan(&submissionUUID); err != nil {
+			rows.Close()
+			return "", nil, err
+		}
+
+		submissionsUUIDs = append(submissionsUUIDs, submissionUUID)
+	}
+	rows.Close()
+
+	if err := deleteSubmissionsTestResults(ctx, tx, submissionsUUIDs); err != nil {
+		return "", nil, err
+	}
+
+	// Save the submissions of the batch
+	query = `
+		INSERT INTO submissions_rerun_items (rerun_id, submission_id)
+		VALUES ($1, $2)
+	`
+
+	for _, submissionUUID := range submissionsUUIDs {
+		if _, err := tx.ExecContext(ctx, query, rerunUUID, submissionUUID); err != nil {
+			return "", nil, err
+		}
+
+		// Save the work in the outbox, with the current archives of the test block
+		if err := saveSubmissionWorkInOutbox(ctx, tx, submissionUUID); err != nil {
+			return "", nil, err
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return "", nil, err
+	}
+
+	return rerunUUID, submissionsUUIDs, nil
+}
+
+// submissionsRerunProgressQuery selects the progress of the batches of requeued submissions
+const submissionsRerunProgressQuery = `
+	SELECT
+		sr.id, sr.test_block_id, sr.trigger, sr.created_at,
+		COUNT(sri.submission_id),
+		COUNT(sri.submission_id) FILTER (WHERE sri.is_queued AND s.status = 'pending'),
+		COUNT(sri.submission_id) FILTER (WHERE sri.is_queued AND s.status = 'running'),
+		COUNT(sri.submission_id) FILTER (WHERE sri.is_queued AND s.status = 'ready'),
+		COUNT(sri.submission_id) FILTER (WHERE sri.is_queued AND s.status = 'timed_out'),
+		COUNT(sri.submission_id) FILTER (WHERE NOT sri.is_queued)
+	FROM submissions_reruns AS sr
+	LEFT JOIN submissions_rerun_items AS sri ON sri.rerun_id = sr.id
+	LEFT JOIN submissions AS s ON sri.submission_id = s.id
+`
+
+// scanSubmissionsRerun parses a row selected with the `submissionsRerunProgressQuery`
+func scanSubmissionsRerun(row interface{ Scan(...any) error }) (*dtos.SubmissionsRerunDTO, error) {
+	rerun := dtos.SubmissionsRerunDTO{}
+
+	err := row.Scan(
+		&rerun.UUID,
+		&rerun.TestBlockUUID,
+		&rerun.Trigger,
+		&rerun.CreatedAt,
+		&rerun.TotalSubmissions,
+		&rerun.PendingSubmissions,
+		&rerun.RunningSubmissions,
+		&rerun.ReadySubmissions,
+		&rerun.TimedOutSubmissions,
+		&rerun.NotQueuedSubmissions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	finishedSubmissions := rerun.ReadySubmissions + rerun.TimedOutSubmissions + rerun.NotQueuedSubmissions
+	rerun.IsCompleted = finishedSubmissions == rerun.TotalSubmissions
+	return &rerun, nil
+}
+
+// GetSubmissionsRerun returns the progress of a batch of requeued submissions
+func (repository *SubmissionsRepositoryImpl) GetSubmissionsRerun(rerunUUID string) (rerun *dtos.SubmissionsRerunDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := submissionsRerunProgressQuery + `
+		WHERE sr.id = $1
+		GROUP BY sr.id
+	`
+
+	rerun, err = scanSubmissionsRerun(repository.Connection.QueryRowContext(ctx, query, rerunUUID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.SubmissionsRerunNotFound{}
+		}
+
+		return nil, err
+	}
+
+	return rerun, nil
+}
+
+// GetTestBlockSubmissionsReruns returns the progress of all the batches of requeued submissions of a test block,
+// from the newest to the oldest
+func (repository *SubmissionsRepositoryImpl) GetTestBlockSubmissionsReruns(testBlockUUID string) (reruns []*dtos.SubmissionsRerunDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := submissionsRerunProgressQuery + `
+		WHERE sr.test_block_id = $1
+		GROUP BY sr.id
+		ORDER BY sr.created_at DESC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, testBlockUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reruns = []*dtos.SubmissionsRerunDTO{}
+	for rows.Next() {
+		rerun, err := scanSubmissionsRerun(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reruns = append(reruns, rerun)
+	}
+
+	return reruns, nil
+}
+
+// openSubmissionDeadLetterCondition checks if the work of a submission was dead-lettered and not replayed yet.
+// Requeueing these works would only send them to the dead letter queue again
+const openSubmissionDeadLetterCondition = `
+	EXISTS (
+		SELECT 1
+		FROM submissions_dead_letters AS sdl
+		WHERE sdl.submission_id = submissions.id AND sdl.replayed_at IS NULL
+	)
+`
+
+// RequeueStuckSubmissions resets the status of the submissions whose status has not changed in time and that
+// were not requeued the maximum number of times yet and saves their works in the outbox. Submissions with an
+// open dead letter are not requeued. Returns the UUIDs of the requeued submissions
+func (repository *SubmissionsRepositoryImpl) RequeueStuckSubmissions(dto *dtos.StuckSubmissionsDTO) (submissionsUUIDs []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Start the transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The rows are locked so other instances of the gateway do not requeue the same submissions
+	query := `
+		UPDATE submissions
+		SET status = 'pending', status_updated_at = CURRENT_TIMESTAMP, requeue_attempts = requeue_attempts + 1
+		WHERE id IN (
+			SELECT id
+			FROM submissions
+			WHERE status IN ('pending', 'running')
+				AND status_updated_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+				AND requeue_attempts < $2
+				AND NOT ` + openSubmissionDeadLetterCondition + `
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, dto.TimeoutSeconds, dto.MaxRequeueAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	submissionsUUIDs, err = scanSubmissionsUUIDs(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deleteSubmissionsTestResults(ctx, tx, submissionsUUIDs); err != nil {
+		return nil, err
+	}
+
+	// Save the works in the outbox, they will be published to the queue by the relay
+	for _, submissionUUID := range submissionsUUIDs {
+		if err := saveSubmissionWorkInOutbox(ctx, tx, submissionUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return submissionsUUIDs, nil
+}
+
+// SetStuckSubmissionsAsTimedOut marks as timed out the submissions whose status has not changed in time
+// after being requeued the maximum number of times or whose work was dead-lettered, so the students can
+// submit again. The dead-lettered works can still be replayed by the administrators
+func (repository *SubmissionsRepositoryImpl) SetStuckSubmissionsAsTimedOut(dto *dtos.StuckSubmissionsDTO) (submissionsUUIDs []string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE submissions
+		SET status = 'timed_out', passing = FALSE
+		WHERE id IN (
+			SELECT id
+			FROM submissions
+			WHERE status IN ('pending', 'running')
+				AND status_updated_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+				AND (requeue_attempts >= $2 OR ` + openSubmissionDeadLetterCondition + `)
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, dto.TimeoutSeconds, dto.MaxRequeueAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSubmissionsUUIDs(rows)
+}
+
+// scanSubmissionsUUIDs parses the rows returned by a query that only selects the UUIDs of submissions
+func scanSubmissionsUUIDs(rows *sql.Rows) ([]string, error) {
+	submissionsUUIDs := []string{}
+	for rows.Next() {
+		var submissionUUID string
+		if err := rows.Scan(&submissionUUID); err != nil {
+			return nil, err
+		}
+
+		submissionsUUIDs = append(submissionsUUIDs, submissionUUID)
+	}
+
+	return submissionsUUIDs, rows.Err()
+}
+
+// ClaimSubmissionsOutboxMessages returns the oldest messages of the outbox that were not sent yet. The messages
+// are locked for a while, so other instances of the gateway do not publish them at the same time
+func (repository *SubmissionsRepositoryImpl) ClaimSubmissionsOutboxMessages(limit int) (messages []*entities.SubmissionsOutboxMessage, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE submissions_outbox
+		SET locked_until = CURRENT_TIMESTAMP + INTERVAL '30 seconds'
+		WHERE id IN (
+			SELECT id
+			FROM submissions_outbox
+			WHERE sent_at IS NULL AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+			ORDER BY created_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, submission_id, payload, attempts
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages = []*entities.SubmissionsOutboxMessage{}
+	for rows.Next() {
+		message := entities.SubmissionsOutboxMessage{}
+
+		if err := rows.Scan(
+			&message.UUID,
+			&message.SubmissionUUID,
+			&message.Payload,
+			&message.Attempts,
+		); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, &message)
+	}
+
+	return messages, rows.Err()
+}
+
+// SetSubmissionsOutboxMessageAsSent marks a message of the outbox as sent
+func (repository *SubmissionsRepositoryImpl) SetSubmissionsOutboxMessageAsSent(messageUUID string) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE submissions_outbox
+		SET sent_at = CURRENT_TIMESTAMP, attempts = attempts + 1, locked_until = NULL
+		WHERE id = $1
+	`
+
+	_, err = repository.Connection.ExecContext(ctx, query, messageUUID)
+	return err
+}
+
+// SetSubmissionsOutboxMessageAsFailed releases a message of the outbox that could not be published, so it is retried
+func (repository *SubmissionsRepositoryImpl) SetSubmissionsOutboxMessageAsFailed(messageUUID string, errorMessage string) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE submissions_outbox
+		SET attempts = attempts + 1, last_error = $1, locked_until = NULL
+		WHERE id = $2
+	`
+
+	_, err = repository.Connection.ExecContext(ctx, query, errorMessage, messageUUID)
+	return err
+}
+
+// DeleteSentSubmissionsOutboxMessages removes the messages that were sent longer than the given duration ago
+func (repository *SubmissionsRepositoryImpl) DeleteSentSubmissionsOutboxMessages(olderThan time.Duration) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := `
+		DELETE FROM submissions_outbox
+		WHERE sent_at IS NOT NULL AND sent_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+
+	_, err = repository.Connection.ExecContext(ctx, query, olderThan.Seconds())
+	return err
+}
+
+// SaveSubmissionDeadLetter saves a submission work that was rejected by the runners or expired in the queue
+func (repository *SubmissionsRepositoryImpl) SaveSubmissionDeadLetter(dto *dtos.SaveSubmissionDeadLetterDTO) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO submissions_dead_letters (submission_id, payload, reason)
+		VALUES ($1, $2, $3)
+	`
+
+	_, err = repository.Connection.ExecContext(ctx, query, dto.SubmissionUUID, dto.Payload, dto.Reason)
+	return err
+}
+
+// GetSubmissionsDeadLetters returns the dead-lettered submission works, from the newest to the oldest
+func (repository *SubmissionsRepositoryImpl) GetSubmissionsDeadLetters(dto *dtos.GetSubmissionsDeadLettersDTO) (deadLetters []*dtos.SubmissionDeadLetterDTO, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			sdl.id, sdl.submission_id, s.student_id, s.test_block_id, s.status,
+			sdl.reason, sdl.dead_lettered_at, sdl.replayed_at
+		FROM submissions_dead_letters AS sdl
+		INNER JOIN submissions AS s ON sdl.submission_id = s.id
+		WHERE $1 OR sdl.replayed_at IS NULL
+		ORDER BY sdl.dead_lettered_at DESC
+	`
+
+	rows, err := repository.Connection.QueryContext(ctx, query, dto.IncludeReplayed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetters = []*dtos.SubmissionDeadLetterDTO{}
+	for rows.Next() {
+		deadLetter := dtos.SubmissionDeadLetterDTO{}
+		replayedAt := sql.NullString{}
+
+		if err := rows.Scan(
+			&deadLetter.UUID,
+			&deadLetter.SubmissionUUID,
+			&deadLetter.StudentUUID,
+			&deadLetter.TestBlockUUID,
+			&deadLetter.SubmissionStatus,
+			&deadLetter.Reason,
+			&deadLetter.DeadLetteredAt,
+			&replayedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if replayedAt.Valid {
+			deadLetter.ReplayedAt = &replayedAt.String
+		}
+
+		deadLetters = append(deadLetters, &deadLetter)
+	}
+
+	return deadLetters, rows.Err()
+}
+
+// ReplaySubmissionDeadLetter resets the status of a dead-lettered submission and saves its work in the outbox
+// again. Returns the UUID of the replayed submission
+func (repository *SubmissionsRepositoryImpl) ReplaySubmissionDeadLetter(deadLetterUUID string) (submissionUUID string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Start the transaction
+	tx, err := repository.Connection.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	// Lock the dead letter, so it is not replayed twice at the same time
+	var isReplayed bool
+	query := `
+		SELECT submission_id, replayed_at IS NOT NULL
+		FROM submissions_dead_letters
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = tx.QueryRowContext(ctx, query, deadLetterUUID).Scan(&submissionUUID, &isReplayed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.SubmissionDeadLetterNotFound{}
+		}
+
+		return "", err
+	}
+
+	if isReplayed {
+		return "", errors.SubmissionDeadLetterAlreadyReplayed{}
+	}
+
+	// Reset the status of the submission
+	query = `
+		UPDATE submissions
+		SET status = 'pending', requeue_attempts = 0
+		WHERE id = $1
+	`
+
+	_, err = tx.ExecContext(ctx, query, submissionUUID)
+	if err != nil {
+		return "", err
+	}
+
+	err = deleteSubmissionsTestResults(ctx, tx, []string{submissionUUID})
+	if err != nil {
+		return "", err
+	}
+
+	// Save the work in the outbox, with the current archives of the test block
+	err = saveSubmissionWorkInOutbox(ctx, tx, submissionUUID)
+	if err != nil {
+		return "", err
+	}
+
+	query = `
+		UPDATE submissions_dead_letters
+		SET replayed_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	_, err = tx.ExecContext(ctx, query, deadLetterUUID)
+	if err != nil {
+		return "", err
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return "", err
+	}
+
+	return submissionUUID, nil
+}
@@ -0,0 +1,24 @@
+package implementations
+
+import (
+	blocksImplementations "github.com/UPB-Code-Labs/main-api/src/blocks/infrastructure/implementations"
+	laboratoriesImplementations "github.com/UPB-Code-Labs/main-api/src/laboratories/infrastructure/implementations"
+	languagesImplementations "github.com/UPB-Code-Labs/main-api/src/languages/infrastructure/implementations"
+	staticFilesImplementations "github.com/UPB-Code-Labs/main-api/src/static-files/infrastructure/implementations"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/application"
+)
+
+// NewSubmissionUseCases returns the submissions use cases wired with the implementations of their
+// repositories and workers. It is used by the routes and by the background workers of the submissions
+func NewSubmissionUseCases() *application.SubmissionUseCases {
+	return &application.SubmissionUseCases{
+		StaticFilesRepository:   staticFilesImplementations.GetStaticFilesRepositoryInstance(),
+		LaboratoriesRepository:  laboratoriesImplementations.GetLaboratoriesPostgresRepositoryInstance(),
+		BlocksRepository:        blocksImplementations.GetBlocksPostgresRepositoryInstance(),
+		SubmissionsRepository:   GetSubmissionsRepositoryInstance(),
+		SubmissionsQueueManager: GetSubmissionsRabbitMQQueueManagerInstance(),
+		SubmissionsOutboxRelay:  GetSubmissionsOutboxRelayInstance(),
+		LanguagesRepository:     languagesImplementations.GetLanguagesRepositoryInstance(),
+		ArchivesValidator:       staticFilesImplementations.GetZipArchivesValidatorInstance(),
+	}
+}
@@ -0,0 +1,87 @@
+package implementations
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	sharedInfrastructure "github.com/UPB-Code-Labs/main-api/src/shared/infrastructure"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/dtos"
+	"github.com/UPB-Code-Labs/main-api/src/submissions/domain/entities"
+)
+
+// SubmissionsWatchdog periodically looks for the submissions that are stuck in the `pending` or `running`
+// status (e.g. the runner crashed or the message was lost) and requeues or times them out
+type SubmissionsWatchdog struct {
+	// Parameters used to find the stuck submissions
+	Parameters *dtos.StuckSubmissionsDTO
+
+	// Time between each search of stuck submissions
+	Interval time.Duration
+}
+
+// Singleton instance
+var submissionsWatchdogInstance *SubmissionsWatchdog
+var submissionsWatchdogOnce sync.Once
+
+func GetSubmissionsWatchdogInstance() *SubmissionsWatchdog {
+	submissionsWatchdogOnce.Do(func() {
+		environment := sharedInfrastructure.GetEnvironment()
+
+		submissionsWatchdogInstance = &SubmissionsWatchdog{
+			Parameters: &dtos.StuckSubmissionsDTO{
+				TimeoutSeconds:     environment.SubmissionsTimeoutSeconds,
+				MaxRequeueAttempts: environment.SubmissionsMaxRequeueAttempts,
+			},
+			Interval: time.Duration(environment.SubmissionsWatchdogIntervalSeconds) * time.Second,
+		}
+	})
+
+	return submissionsWatchdogInstance
+}
+
+// Start looks for stuck submissions every interval
+func (watchdog *SubmissionsWatchdog) Start() {
+	log.Println("[Submissions watchdog]: Looking for stuck submissions every", watchdog.Interval)
+
+	useCases := NewSubmissionUseCases()
+
+	ticker := time.NewTicker(watchdog.Interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		result, err := useCases.HandleStuckSubmissions(watchdog.Parameters)
+		if err != nil {
+			log.Println("[Submissions watchdog]: There was an error while handling the stuck submissions", err.Error())
+			continue
+		}
+
+		if len(result.RequeuedSubmissions) > 0 || len(result.TimedOutSubmissions) > 0 {
+			log.Printf(
+				"[Submissions watchdog]: %d submissions were requeued and %d timed out",
+				len(result.RequeuedSubmissions),
+				len(result.TimedOutSubmissions),
+			)
+		}
+
+		// Let the students and teachers know about the new status of the submissions
+		for _, submissionUUID := range result.RequeuedSubmissions {
+			sendStuckSubmissionUpdate(submissionUUID, "pending")
+		}
+
+		for _, submissionUUID := range result.TimedOutSubmissions {
+			sendStuckSubmissionUpdate(submissionUUID, "timed_out")
+		}
+	}
+}
+
+// sendStuckSubmissionUpdate sends the new status of a stuck submission to the real time updates senders
+func sendStuckSubmissionUpdate(submissionUUID string, status string) {
+	GetSubmissionsRealTimeUpdatesSenderInstance().SendUpdate(&dtos.SubmissionStatusUpdateDTO{
+		SubmissionUUID:   submissionUUID,
+		SubmissionStatus: status,
+		TestResults:      []*entities.SubmissionTestResult{},
+	})
+
+	GetLaboratoriesRealTimeUpdatesSenderInstance().SendSubmissionUpdate(submissionUUID)
+}